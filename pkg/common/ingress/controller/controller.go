@@ -69,6 +69,7 @@ type Configuration struct {
 	ControllerName           string
 	WatchIngressWithoutClass bool
 	WatchGateway             bool
+	WatchNamespaceLabels     bool
 	WatchNamespace           string
 	ConfigMapName            string
 
@@ -101,6 +102,7 @@ type Configuration struct {
 	VerifyHostname         bool
 	DefaultHealthzURL      string
 	StatsCollectProcPeriod time.Duration
+	StatsCollectSrvPeriod  time.Duration
 	PublishService         string
 	TrackOldInstances      bool
 	Backend                ingress.Controller
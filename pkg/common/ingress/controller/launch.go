@@ -96,6 +96,10 @@ declare neither the kubernetes.io/ingress.class annotation nor the
 		watchGateway = flags.Bool("watch-gateway", true,
 			`Watch and parse resources from the Gateway API`)
 
+		watchNamespaceLabels = flags.Bool("watch-namespace-labels", false,
+			`Watch Namespace resources and resync the configuration when their labels
+change. Needs permission to list and watch Namespaces. Defaults to false`)
+
 		masterWorker = flags.Bool("master-worker", false,
 			`Defines if haproxy should be configured in master-worker mode. If 'false', one
 single process is forked in the background. If 'true', a master process is
@@ -189,6 +193,11 @@ the time to receive all/most of the changes of a batch update.`)
 haproxy updates Idle_pct every 500ms, which makes that the best configuration
 value. Change to 0 (zero) to disable this metric.`)
 
+		statsCollectSrvPeriod = flags.Duration("stats-collect-servers-period", 0,
+			`Defines the interval between two consecutive readings of the backend servers
+state, used to log and count servers going down. Defaults to 0 (zero), which
+disables the readings.`)
+
 		profiling = flags.Bool("profiling", true,
 			`Enable profiling via web interface host:port/debug/pprof/`)
 
@@ -558,6 +567,7 @@ k8s >=1.21+`)
 		ControllerName:           controllerName,
 		WatchIngressWithoutClass: *watchIngressWithoutClass,
 		WatchGateway:             *watchGateway,
+		WatchNamespaceLabels:     *watchNamespaceLabels,
 		WatchNamespace:           *watchNamespace,
 		ConfigMapName:            *configMap,
 		ReloadStrategy:           *reloadStrategy,
@@ -570,6 +580,7 @@ k8s >=1.21+`)
 		VerifyHostname:           *verifyHostname,
 		DefaultHealthzURL:        *defHealthzURL,
 		StatsCollectProcPeriod:   *statsCollectProcPeriod,
+		StatsCollectSrvPeriod:    *statsCollectSrvPeriod,
 		PublishService:           *publishSvc,
 		Backend:                  backend,
 		ForceNamespaceIsolation:  *forceIsolation,
@@ -685,6 +696,24 @@ func registerHandlers(enableProfiling bool, port int, ic *GenericController) {
 		w.Write([]byte(out))
 	})
 
+	mux.HandleFunc("/cache/purge", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var out string
+		hostname := r.URL.Query().Get("host")
+		err := ic.cfg.Backend.CachePurge(hostname)
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			out = fmt.Sprintf("Error purging the cache: %v.\nSee further information in the controller log.\n", err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			out = fmt.Sprintf("Cache of '%s' successfully purged.\n", hostname)
+		}
+		w.Write([]byte(out))
+	})
+
 	mux.HandleFunc("/build", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		b, _ := json.Marshal(ic.Info())
@@ -50,6 +50,8 @@ type Controller interface {
 	Info() *BackendInfo
 	// AcmeCheck starts a certificate missing/expiring/outdated check
 	AcmeCheck() (int, error)
+	// CachePurge purges the response cache of a hostname
+	CachePurge(hostname string) error
 	// ConfigureFlags allow to configure more flags before the parsing of
 	// command line arguments
 	ConfigureFlags(*pflag.FlagSet)
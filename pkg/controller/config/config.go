@@ -102,8 +102,11 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1.AddToScheme(scheme))
 
+	standalone := opt.ManifestsDir != ""
 	var kubeConfig *rest.Config
 	switch {
+	case standalone:
+		// no API server, resources are read from the manifests directory
 	case restConfig != nil:
 		kubeConfig = restConfig
 	case opt.ApiserverHost != "":
@@ -129,7 +132,7 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 			return nil, err
 		}
 	}
-	if opt.DisableAPIWarnings {
+	if opt.DisableAPIWarnings && kubeConfig != nil {
 		kubeConfig.WarningHandler = rest.NoWarnings{}
 	}
 
@@ -137,8 +140,12 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 	// by the manager to create the controller's client
 	//
 	// the clients below are just used locally to validate some config options
-	client := kubernetes.NewForConfigOrDie(kubeConfig)
-	clientGateway := gwapiversioned.NewForConfigOrDie(kubeConfig)
+	var client *kubernetes.Clientset
+	var clientGateway *gwapiversioned.Clientset
+	if !standalone {
+		client = kubernetes.NewForConfigOrDie(kubeConfig)
+		clientGateway = gwapiversioned.NewForConfigOrDie(kubeConfig)
+	}
 
 	configLog.Info("version info",
 		"controller-publicname", versionInfo.Name,
@@ -166,6 +173,22 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		configLog.Info("DEPRECATED: --force-namespace-isolation is ignored, use allow-cross-namespace command-line options or cross-namespace configuration keys instead.")
 	}
 
+	if standalone {
+		if opt.AcmeServer {
+			return nil, fmt.Errorf("--acme-server is not supported when --manifests-dir is configured")
+		}
+		if opt.PublishService != "" {
+			return nil, fmt.Errorf("--publish-service is not supported when --manifests-dir is configured")
+		}
+		if opt.StatsCollectBkdPeriod > 0 {
+			return nil, fmt.Errorf("--stats-collect-backends-period is not supported when --manifests-dir is configured")
+		}
+		if opt.PodReadinessGate {
+			return nil, fmt.Errorf("--pod-readiness-gate is not supported when --manifests-dir is configured")
+		}
+		configLog.Info("running standalone, reading resources from manifests", "manifests-dir", opt.ManifestsDir)
+	}
+
 	if opt.IngressClass != "" {
 		configLog.Info("watching for ingress resources with 'kubernetes.io/ingress.class'", "annotation", opt.IngressClass)
 	}
@@ -203,7 +226,9 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 	}
 
 	var hasGatewayV1, hasGatewayB1, hasGatewayA2, hasTCPRouteA2 bool
-	if opt.WatchGateway {
+	if opt.WatchGateway && standalone {
+		configLog.Info("gateway API versions are enabled from the resources found in the manifests")
+	} else if opt.WatchGateway {
 		gwapis := []string{"gatewayclass", "gateway", "httproute"}
 		tcpapis := []string{"tcproute"}
 
@@ -263,10 +288,15 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 
 	// we could `|| hasGateway[version...]` instead of `|| opt.WatchGateway` here,
 	// but we're choosing a consistent startup behavior despite of the cluster configuration.
-	election := opt.UpdateStatus || opt.AcmeServer || opt.WatchGateway
+	// there is nothing to elect or to update when running standalone.
+	election := !standalone && (opt.UpdateStatus || opt.AcmeServer || opt.WatchGateway)
+	updateStatus := !standalone && opt.UpdateStatus
 	if election && podNamespace == "" {
 		return nil, fmt.Errorf("POD_NAMESPACE envvar should be configured when --update-status=true, --acme-server=true, or --watch-gateway=true")
 	}
+	if opt.PodReadinessGate && (podNamespace == "" || podName == "") {
+		return nil, fmt.Errorf("POD_NAMESPACE and POD_NAME envvars should be configured when --pod-readiness-gate=true")
+	}
 	if election && opt.IngressClass == "" {
 		return nil, fmt.Errorf("--ingress-class should not be empty when --update-status=true, --acme-server=true, or --watch-gateway=true")
 	}
@@ -280,7 +310,7 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		}
 	}
 
-	if opt.UpdateStatus && podName == "" && opt.PublishService == "" && len(publishAddressHostnames)+len(publishAddressIPs) == 0 {
+	if updateStatus && podName == "" && opt.PublishService == "" && len(publishAddressHostnames)+len(publishAddressIPs) == 0 {
 		return nil, fmt.Errorf("one of --publish-service, --publish-address or POD_NAME envvar should be configured when --update-status=true")
 	}
 
@@ -299,12 +329,23 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		configLog.Info("WARN: changing --master-worker=true due to external haproxy configuration")
 		masterWorkerCfg = true
 	}
+	if opt.StartFromLastConfig && opt.MasterSocket != "" {
+		return nil, fmt.Errorf("--start-from-last-config cannot be used with an external haproxy")
+	}
+	var haproxyVersion utils.HAProxyVersion
+	if opt.MasterSocket == "" {
+		var err error
+		haproxyVersion, err = utils.LocalHAProxyVersion()
+		if err != nil {
+			configLog.Info("WARN: cannot read haproxy version, keywords of newer versions will not be used", "error", err.Error())
+		}
+	}
 	if opt.MasterSocket != "" {
 		configLog.Info("running external haproxy", "master-unix-socket", opt.MasterSocket)
 	} else if masterWorkerCfg {
-		configLog.Info("running embedded haproxy", "mode", "master-worker")
+		configLog.Info("running embedded haproxy", "mode", "master-worker", "version", haproxyVersion.String())
 	} else {
-		configLog.Info("running embedded haproxy", "mode", "daemon")
+		configLog.Info("running embedded haproxy", "mode", "daemon", "version", haproxyVersion.String())
 	}
 
 	if !(opt.ReloadStrategy == "native" || opt.ReloadStrategy == "reusesocket" || opt.ReloadStrategy == "multibinder") {
@@ -319,7 +360,9 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		if err != nil {
 			return nil, fmt.Errorf("invalid format for global ConfigMap '%s': %w", opt.ConfigMap, err)
 		}
-		_, err = client.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+		if !standalone {
+			_, err = client.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+		}
 		if err != nil {
 			return nil, fmt.Errorf("error reading global ConfigMap '%s': %w", opt.ConfigMap, err)
 		}
@@ -331,7 +374,9 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		if err != nil {
 			return nil, fmt.Errorf("invalid format for service '%s': %w", opt.DefaultSvc, err)
 		}
-		_, err = client.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+		if !standalone {
+			_, err = client.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+		}
 		if err != nil {
 			if errors.IsForbidden(err) {
 				return nil, fmt.Errorf("it seems the cluster is running with Authorization enabled (like RBAC) and there is no permissions for the ingress controller. Please check the configuration")
@@ -360,7 +405,9 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		}
 	}
 
-	if opt.WatchNamespace != "" {
+	if standalone {
+		// nothing to validate, resources are read from the manifests directory
+	} else if opt.WatchNamespace != "" {
 		_, err := client.NetworkingV1().Ingresses(opt.WatchNamespace).List(ctx, metav1.ListOptions{Limit: 1})
 		if err != nil {
 			return nil, fmt.Errorf("no namespace with name '%s' found: %w", opt.WatchNamespace, err)
@@ -426,6 +473,7 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 	defaultDirCACerts := "/var/lib/haproxy/cacerts"
 	defaultDirCrl := "/var/lib/haproxy/crl"
 	defaultDirDHParam := "/var/lib/haproxy/dhparam"
+	defaultDirRollback := "/var/lib/haproxy/rollback"
 	defaultDirVarRun := "/var/run/haproxy"
 	defaultDirMaps := "/etc/haproxy/maps"
 	// defaultDirErrorfiles := "/etc/haproxy/errorfiles"
@@ -436,6 +484,7 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		&defaultDirCACerts,
 		&defaultDirCrl,
 		&defaultDirDHParam,
+		&defaultDirRollback,
 		&defaultDirVarRun,
 		&defaultDirMaps,
 		// &defaultDirErrorfiles,
@@ -473,10 +522,12 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		DefaultDirCerts:          defaultDirCerts,
 		DefaultDirCrl:            defaultDirCrl,
 		DefaultDirDHParam:        defaultDirDHParam,
+		DefaultDirRollback:       defaultDirRollback,
 		DefaultDirMaps:           defaultDirMaps,
 		DefaultDirVarRun:         defaultDirVarRun,
 		DefaultService:           opt.DefaultSvc,
 		DefaultSSLCertificate:    opt.DefSSLCertificate,
+		DebugModelHandler:        opt.DebugModelHandler,
 		DisableExternalName:      opt.DisableExternalName,
 		DisableKeywords:          disableKeywords,
 		Election:                 election,
@@ -488,17 +539,20 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		HasGatewayB1:             hasGatewayB1,
 		HasGatewayV1:             hasGatewayV1,
 		HasTCPRouteA2:            hasTCPRouteA2,
+		HAProxyVersion:           haproxyVersion,
 		HealthzAddr:              healthz,
 		HealthzURL:               opt.HealthzURL,
 		IngressClass:             opt.IngressClass,
 		IngressClassPrecedence:   opt.IngressClassPrecedence,
 		KubeConfig:               kubeConfig,
 		LocalFSPrefix:            opt.LocalFSPrefix,
+		ManifestsDir:             opt.ManifestsDir,
 		MasterSocket:             opt.MasterSocket,
 		MasterWorker:             masterWorkerCfg,
 		MaxOldConfigFiles:        opt.MaxOldConfigFiles,
 		PodName:                  podName,
 		PodNamespace:             podNamespace,
+		PodReadinessGate:         opt.PodReadinessGate,
 		Profiling:                opt.Profiling,
 		PublishAddressHostnames:  publishAddressHostnames,
 		PublishAddressIPs:        publishAddressIPs,
@@ -507,24 +561,33 @@ func CreateWithConfig(ctx context.Context, restConfig *rest.Config, opt *Options
 		ReadyzURL:                opt.ReadyzURL,
 		ReloadInterval:           opt.ReloadInterval,
 		ReloadStrategy:           opt.ReloadStrategy,
+		RollbackConfig:           opt.RollbackConfig,
 		ResyncPeriod:             &opt.ResyncPeriod,
 		RootContext:              rootcontext,
 		Scheme:                   scheme,
 		ShutdownTimeout:          &opt.ShutdownTimeout,
 		SortEndpointsBy:          sortEndpoints,
+		StartFromLastConfig:      opt.StartFromLastConfig,
 		StatsCollectProcPeriod:   opt.StatsCollectProcPeriod,
+		StatsCollectSrvPeriod:    opt.StatsCollectSrvPeriod,
+		StatsCollectBkdPeriod:    opt.StatsCollectBkdPeriod,
 		StopHandler:              opt.StopHandler,
 		TCPConfigMapName:         opt.TCPConfigMapName,
 		TrackOldInstances:        opt.TrackOldInstances,
-		UpdateStatus:             opt.UpdateStatus,
+		UpdateStatus:             updateStatus,
 		UpdateStatusOnShutdown:   opt.UpdateStatusOnShutdown,
 		UseNodeInternalIP:        opt.UseNodeInternalIP,
 		ValidateConfig:           opt.ValidateConfig,
 		VerifyHostname:           opt.VerifyHostname,
 		VersionInfo:              versionInfo,
 		WaitBeforeUpdate:         opt.WaitBeforeUpdate,
+		WatchGateway:             opt.WatchGateway,
 		WatchIngressWithoutClass: opt.WatchIngressWithoutClass,
 		WatchNamespace:           opt.WatchNamespace,
+		WatchNamespaceLabels:     opt.WatchNamespaceLabels,
+		WebhookCertDir:           opt.WebhookCertDir,
+		WebhookDryRun:            opt.WebhookDryRun,
+		WebhookPort:              opt.WebhookPort,
 	}, nil
 }
 
@@ -656,10 +719,12 @@ type Config struct {
 	DefaultDirCACerts        string
 	DefaultDirCrl            string
 	DefaultDirDHParam        string
+	DefaultDirRollback       string
 	DefaultDirMaps           string
 	DefaultDirVarRun         string
 	DefaultService           string
 	DefaultSSLCertificate    string
+	DebugModelHandler        bool
 	DisableExternalName      bool
 	DisableKeywords          []string
 	Election                 bool
@@ -671,17 +736,20 @@ type Config struct {
 	HasGatewayB1             bool
 	HasGatewayV1             bool
 	HasTCPRouteA2            bool
+	HAProxyVersion           utils.HAProxyVersion
 	HealthzAddr              string
 	HealthzURL               string
 	IngressClass             string
 	IngressClassPrecedence   bool
 	KubeConfig               *rest.Config
 	LocalFSPrefix            string
+	ManifestsDir             string
 	MasterSocket             string
 	MasterWorker             bool
 	MaxOldConfigFiles        int
 	PodName                  string
 	PodNamespace             string
+	PodReadinessGate         bool
 	Profiling                bool
 	PublishAddressHostnames  []string
 	PublishAddressIPs        []string
@@ -690,12 +758,16 @@ type Config struct {
 	ReadyzURL                string
 	ReloadInterval           time.Duration
 	ReloadStrategy           string
+	RollbackConfig           bool
 	ResyncPeriod             *time.Duration
 	RootContext              context.Context
 	Scheme                   *runtime.Scheme
 	ShutdownTimeout          *time.Duration
 	SortEndpointsBy          string
+	StartFromLastConfig      bool
 	StatsCollectProcPeriod   time.Duration
+	StatsCollectSrvPeriod    time.Duration
+	StatsCollectBkdPeriod    time.Duration
 	StopHandler              bool
 	TCPConfigMapName         string
 	TrackOldInstances        bool
@@ -706,6 +778,11 @@ type Config struct {
 	VerifyHostname           bool
 	VersionInfo              version.Info
 	WaitBeforeUpdate         time.Duration
+	WatchGateway             bool
 	WatchIngressWithoutClass bool
 	WatchNamespace           string
+	WatchNamespaceLabels     bool
+	WebhookCertDir           string
+	WebhookDryRun            bool
+	WebhookPort              int
 }
@@ -42,6 +42,7 @@ func NewOptions() *Options {
 type Options struct {
 	KubeConfig               flag.Value
 	ApiserverHost            string
+	ManifestsDir             string
 	LocalFSPrefix            string
 	DisableAPIWarnings       bool
 	DefaultSvc               string
@@ -50,9 +51,15 @@ type Options struct {
 	ReloadStrategy           string
 	MaxOldConfigFiles        int
 	ValidateConfig           bool
+	RollbackConfig           bool
+	StartFromLastConfig      bool
+	WebhookPort              int
+	WebhookCertDir           string
+	WebhookDryRun            bool
 	ControllerClass          string
 	WatchIngressWithoutClass bool
 	WatchGateway             bool
+	WatchNamespaceLabels     bool
 	MasterWorker             bool
 	MasterSocket             string
 	ConfigMap                string
@@ -74,10 +81,14 @@ type Options struct {
 	ResyncPeriod             time.Duration
 	WatchNamespace           string
 	StatsCollectProcPeriod   time.Duration
+	StatsCollectSrvPeriod    time.Duration
+	StatsCollectBkdPeriod    time.Duration
+	PodReadinessGate         bool
 	HealthzAddr              string
 	HealthzURL               string
 	ReadyzURL                string
 	Profiling                bool
+	DebugModelHandler        bool
 	StopHandler              bool
 	DefSSLCertificate        string
 	VerifyHostname           bool
@@ -134,6 +145,13 @@ func (o *Options) AddFlags(fs *flag.FlagSet) {
 		"A valid kubeconfig must be provided if used.",
 	)
 
+	fs.StringVar(&o.ManifestsDir, "manifests-dir", o.ManifestsDir, ""+
+		"Runs the controller in standalone mode, reading resources from the YAML or JSON "+
+		"manifests found in this directory instead of the Kubernetes API server. The "+
+		"directory is read recursively and watched for changes. Endpoints should be "+
+		"declared statically, using Endpoints manifests.",
+	)
+
 	fs.StringVar(&o.LocalFSPrefix, "local-filesystem-prefix", o.LocalFSPrefix, ""+
 		"Defines the prefix of a temporary directory HAProxy Ingress should create and "+
 		"maintain all the configuration files. Useful for local deployment.",
@@ -176,6 +194,39 @@ func (o *Options) AddFlags(fs *flag.FlagSet) {
 		"as failed (zero)",
 	)
 
+	fs.BoolVar(&o.RollbackConfig, "rollback-config", o.RollbackConfig, ""+
+		"Validates the configuration before reloading HAProxy, and restores the last "+
+		"known good configuration files, maps and certificates if HAProxy rejects it. "+
+		"HAProxy keeps running the restored configuration, and the metric "+
+		"'haproxyingress_fallback_config' is set as one until a new configuration is "+
+		"successfully loaded",
+	)
+
+	fs.BoolVar(&o.StartFromLastConfig, "start-from-last-config", o.StartFromLastConfig, ""+
+		"Starts the embedded HAProxy with the configuration files written by a former "+
+		"controller process, before the first synchronization with the cluster. The first "+
+		"sync is applied as a regular reload, and the readiness endpoint of the controller "+
+		"only reports ready after it succeeds. Cannot be used with an external HAProxy.",
+	)
+
+	fs.IntVar(&o.WebhookPort, "webhook-port", o.WebhookPort, ""+
+		"Port number the validating admission webhook listens to. The webhook validates "+
+		"Ingress, IngressClass and HTTPRoute resources before they are persisted, "+
+		"rejecting resources that would be partially or not applied. Default value is "+
+		"0, which disables the webhook server.",
+	)
+
+	fs.StringVar(&o.WebhookCertDir, "webhook-cert-dir", o.WebhookCertDir, ""+
+		"Directory with the tls.crt and tls.key files used by the validating admission "+
+		"webhook. Defaults to the controller-runtime's default directory.",
+	)
+
+	fs.BoolVar(&o.WebhookDryRun, "webhook-dry-run", o.WebhookDryRun, ""+
+		"Renders the configuration of resources accepted by the validating admission "+
+		"webhook into a temporary directory and checks it with 'haproxy -c', rejecting "+
+		"the resource if the check fails. Default value is false.",
+	)
+
 	fs.StringVar(&o.ControllerClass, "controller-class", o.ControllerClass, ""+
 		"Defines an alternative controller name this controller should listen to. If "+
 		"empty, this controller will listen to ingress resources whose controller's "+
@@ -194,6 +245,11 @@ func (o *Options) AddFlags(fs *flag.FlagSet) {
 		"Watch and parse resources from the Gateway API.",
 	)
 
+	fs.BoolVar(&o.WatchNamespaceLabels, "watch-namespace-labels", o.WatchNamespaceLabels, ""+
+		"Watch Namespace resources and resync the configuration when their labels "+
+		"change. Needs permission to list and watch Namespaces.",
+	)
+
 	fs.BoolVar(&o.MasterWorker, "master-worker", o.MasterWorker, ""+
 		"Defines if haproxy should be configured in master-worker mode. If 'false', one "+
 		"single process is forked in the background. If 'true', a master process is "+
@@ -306,6 +362,25 @@ func (o *Options) AddFlags(fs *flag.FlagSet) {
 		"value. Change to 0 (zero) to disable this metric.",
 	)
 
+	fs.DurationVar(&o.StatsCollectSrvPeriod, "stats-collect-servers-period", o.StatsCollectSrvPeriod, ""+
+		"Defines the interval between two consecutive readings of the backend servers "+
+		"state, used to log and count servers going down, and to record events on the "+
+		"services whose servers were ejected by passive health checks. Defaults to 0 "+
+		"(zero), which disables the readings.",
+	)
+
+	fs.DurationVar(&o.StatsCollectBkdPeriod, "stats-collect-backends-period", o.StatsCollectBkdPeriod, ""+
+		"Defines the interval between two consecutive summaries of the backends health, "+
+		"published as events and annotations on the Ingress and HTTPRoute resources "+
+		"that declare the backends. Defaults to 0 (zero), which disables the summary.",
+	)
+
+	fs.BoolVar(&o.PodReadinessGate, "pod-readiness-gate", o.PodReadinessGate, ""+
+		"Adds backend pods waiting for the haproxy-ingress.github.io/server-up readiness "+
+		"gate to haproxy, and sets the condition once all the controller replicas report "+
+		"the pod as an UP server. Pods opt in by declaring the readiness gate.",
+	)
+
 	fs.StringVar(&o.HealthzAddr, "healthz-addr", o.HealthzAddr, ""+
 		"The address the healthz service should bind to. Configure with an empty string "+
 		"to disable it.",
@@ -323,6 +398,11 @@ func (o *Options) AddFlags(fs *flag.FlagSet) {
 		"Enable profiling via web interface host:healthzport/debug/pprof/",
 	)
 
+	fs.BoolVar(&o.DebugModelHandler, "debug-model-handler", o.DebugModelHandler, ""+
+		"Allows to dump the internal model and the resource tracker via "+
+		"host:healthzport/debug/model/ and host:healthzport/debug/tracker endpoints.",
+	)
+
 	fs.BoolVar(&o.StopHandler, "stop-handler", o.StopHandler, ""+
 		"Allows to stop the controller via a POST request to host:healthzport/stop "+
 		"endpoint.",
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+// RenderOptions ...
+type RenderOptions struct {
+	Manifests string
+	OutputDir string
+	Check     bool
+}
+
+// NewRenderOptions ...
+func NewRenderOptions() *RenderOptions {
+	return &RenderOptions{
+		OutputDir: "haproxy-render",
+	}
+}
+
+// AddFlags ...
+func (o *RenderOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Manifests, "manifests", o.Manifests, ""+
+		"Comma-separated list of files or directories with the YAML or JSON manifests "+
+		"of the resources to render. Directories are read recursively, looking for "+
+		".yaml, .yml and .json files.",
+	)
+
+	fs.StringVar(&o.OutputDir, "output-dir", o.OutputDir, ""+
+		"Directory where haproxy.cfg, maps, crt-lists and certificates should be "+
+		"written. The directory is created if it does not exist.",
+	)
+
+	fs.BoolVar(&o.Check, "check", o.Check, ""+
+		"Runs 'haproxy -c' against the rendered configuration. haproxy binary should "+
+		"be in the PATH.",
+	)
+}
+
+// CreateRender builds the static configuration used to render the haproxy
+// configuration from manifest files. Only the options used by the converters
+// are read, and the cluster is never reached: the gateway API versions
+// are enabled later, from the resources found in the manifests.
+func CreateRender(opt *Options, render *RenderOptions) (*Config, error) {
+	if render.Manifests == "" {
+		return nil, fmt.Errorf("--manifests should be configured")
+	}
+	if render.OutputDir == "" {
+		return nil, fmt.Errorf("--output-dir should be configured")
+	}
+
+	var level klog.Level
+	if err := level.Set(strconv.Itoa(opt.LogLevel - 1)); err != nil {
+		return nil, err
+	}
+	ctrl.SetLogger(klog.NewKlogr())
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
+
+	var annPrefixList []string
+	for _, prefix := range strings.Split(opt.AnnPrefix, ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" {
+			annPrefixList = append(annPrefixList, prefix)
+		}
+	}
+	if len(annPrefixList) == 0 {
+		return nil, fmt.Errorf("at least one annotation prefix should be configured")
+	}
+
+	controllerName := "haproxy-ingress.github.io/controller"
+	if opt.ControllerClass != "" {
+		controllerName += "/" + strings.TrimLeft(opt.ControllerClass, "/")
+	}
+
+	outputDir, err := filepath.Abs(render.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+	defaultDirCerts := filepath.Join(outputDir, "crt")
+	defaultDirCACerts := filepath.Join(outputDir, "cacerts")
+	defaultDirCrl := filepath.Join(outputDir, "crl")
+	defaultDirDHParam := filepath.Join(outputDir, "dhparam")
+	defaultDirMaps := filepath.Join(outputDir, "maps")
+	for _, dir := range []string{
+		defaultDirCerts,
+		defaultDirCACerts,
+		defaultDirCrl,
+		defaultDirDHParam,
+		defaultDirMaps,
+		filepath.Join(outputDir, "lua"),
+		filepath.Join(outputDir, "errorfiles"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to 'mkdir %s': %w", dir, err)
+		}
+	}
+
+	// best effort, an unknown version just disables keywords of newer
+	// versions, and the converter warns when they are used
+	haproxyVersion, _ := utils.LocalHAProxyVersion()
+
+	return &Config{
+		AllowCrossNamespace:      opt.AllowCrossNamespace,
+		AnnPrefix:                annPrefixList,
+		BackendShards:            opt.BackendShards,
+		ConfigMapName:            opt.ConfigMap,
+		ControllerName:           controllerName,
+		DefaultDirCACerts:        defaultDirCACerts,
+		DefaultDirCerts:          defaultDirCerts,
+		DefaultDirCrl:            defaultDirCrl,
+		DefaultDirDHParam:        defaultDirDHParam,
+		DefaultDirMaps:           defaultDirMaps,
+		DefaultDirVarRun:         outputDir,
+		DefaultService:           opt.DefaultSvc,
+		DefaultSSLCertificate:    opt.DefSSLCertificate,
+		DisableExternalName:      opt.DisableExternalName,
+		DisableKeywords:          utils.Split(opt.DisableConfigKeywords, ","),
+		HAProxyVersion:           haproxyVersion,
+		IngressClass:             opt.IngressClass,
+		IngressClassPrecedence:   opt.IngressClassPrecedence,
+		LocalFSPrefix:            opt.LocalFSPrefix,
+		PodNamespace:             os.Getenv("POD_NAMESPACE"),
+		RootContext:              logr.NewContext(context.Background(), ctrl.Log),
+		Scheme:                   scheme,
+		SortEndpointsBy:          "endpoint",
+		TCPConfigMapName:         opt.TCPConfigMapName,
+		WatchIngressWithoutClass: opt.WatchIngressWithoutClass,
+	}, nil
+}
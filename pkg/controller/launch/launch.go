@@ -17,10 +17,13 @@ limitations under the License.
 package launch
 
 import (
+	"context"
 	"fmt"
 
+	"golang.org/x/sync/errgroup"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
@@ -30,6 +33,10 @@ import (
 
 // Run ...
 func Run(cfg *config.Config) error {
+	if cfg.ManifestsDir != "" {
+		return runStandalone(cfg)
+	}
+
 	rootLogger := ctrl.Log
 	launchLog := rootLogger.WithName("launch")
 	ctx := cfg.RootContext
@@ -82,3 +89,47 @@ func Run(cfg *config.Config) error {
 
 	return nil
 }
+
+// runStandalone starts the controller services without a manager, since there
+// is no API server to connect to. Resources are read from the manifests
+// directory, which is watched for changes by one of the services.
+func runStandalone(cfg *config.Config) error {
+	launchLog := ctrl.Log.WithName("launch")
+	ctx := cfg.RootContext
+
+	launchLog.Info("configuring standalone services")
+	runnables := &runnables{}
+	services := &services.Services{
+		Config: cfg,
+	}
+	if err := services.SetupStandalone(ctx, runnables); err != nil {
+		return fmt.Errorf("unable to create services: %w", err)
+	}
+
+	launchLog.Info("starting standalone services")
+	if err := runnables.Start(ctx); err != nil {
+		return fmt.Errorf("problem running services: %w", err)
+	}
+
+	return nil
+}
+
+type runnables struct {
+	items []manager.Runnable
+}
+
+func (r *runnables) Add(item manager.Runnable) error {
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *runnables) Start(ctx context.Context) error {
+	wg, ctxrun := errgroup.WithContext(ctx)
+	for i := range r.items {
+		item := r.items[i]
+		wg.Go(func() error {
+			return item.Start(ctxrun)
+		})
+	}
+	return wg.Wait()
+}
@@ -142,9 +142,12 @@ func createCache(
 		recorder,
 		cfg.Client,
 		cfg.WatchGateway,
+		cfg.WatchNamespaceLabels,
 		cfg.WatchNamespace,
 		cfg.ForceNamespaceIsolation,
 		!cfg.DisablePodList,
+		os.Getenv("POD_NAMESPACE"),
+		os.Getenv("POD_NAME"),
 		cfg.ResyncPeriod,
 		cfg.EnableEndpointSlicesAPI,
 	)
@@ -305,9 +308,19 @@ func (c *k8scache) GetConfigMap(configMapName string) (*api.ConfigMap, error) {
 }
 
 func (c *k8scache) GetNamespace(name string) (*api.Namespace, error) {
+	if c.listers.namespaceLister != nil {
+		return c.listers.namespaceLister.Get(name)
+	}
 	return c.client.CoreV1().Namespaces().Get(c.ctx, name, metav1.GetOptions{})
 }
 
+func (c *k8scache) GetNamespaceList() ([]*api.Namespace, error) {
+	if c.listers.namespaceLister == nil {
+		return nil, fmt.Errorf("namespaces are not being watched, see --watch-namespace-labels")
+	}
+	return c.listers.namespaceLister.List(labels.Everything())
+}
+
 func (c *k8scache) GetEndpoints(service *api.Service) (*api.Endpoints, error) {
 	return c.listers.endpointLister.Endpoints(service.Namespace).Get(service.Name)
 }
@@ -368,6 +381,19 @@ func (c *k8scache) GetPodNamespace() string {
 	return c.podNamespace
 }
 
+// GetControllerPodList lists the controller pods from the informer cache,
+// this runs on every sync so it should not read the API server.
+func (c *k8scache) GetControllerPodList() ([]*api.Pod, error) {
+	if c.listers.controllerPodLister == nil {
+		return nil, fmt.Errorf("missing POD_NAMESPACE or POD_NAME envvar")
+	}
+	selector, err := c.listers.controllerPodSelector()
+	if err != nil {
+		return nil, err
+	}
+	return c.listers.controllerPodLister.Pods(c.listers.controllerNamespace).List(selector)
+}
+
 var contentProtocolRegex = regexp.MustCompile(`^([a-z]+)://(.*)$`)
 
 func getContentProtocol(input string) (proto, content string) {
@@ -549,6 +575,47 @@ func (c *k8scache) GetPasswdSecretContent(defaultNamespace, secretName string, t
 	return data, nil
 }
 
+func (c *k8scache) GetJWTSecretContent(defaultNamespace, secretName string, track []convtypes.TrackingRef) ([]byte, error) {
+	proto, content := getContentProtocol(secretName)
+	if proto == "file" {
+		return os.ReadFile(content)
+	} else if proto != "secret" {
+		return nil, fmt.Errorf("unsupported protocol: %s", proto)
+	}
+	namespace, name, err := c.buildResourceName(defaultNamespace, "secret", content, c.dynamicConfig.CrossNamespaceSecretJWT)
+	if err != nil {
+		return nil, err
+	}
+	c.tracker.TrackRefName(track, convtypes.ResourceSecret, namespace+"/"+name)
+	secret, err := c.listers.secretLister.Secrets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	keyName := "jwt.key"
+	data, found := secret.Data[keyName]
+	if !found {
+		return nil, fmt.Errorf("secret '%s/%s' does not have key '%s'", namespace, name, keyName)
+	}
+	return data, nil
+}
+
+func (c *k8scache) GetAPIKeySecretContent(defaultNamespace, secretName string, track []convtypes.TrackingRef) (map[string][]byte, error) {
+	proto, content := getContentProtocol(secretName)
+	if proto != "secret" {
+		return nil, fmt.Errorf("unsupported protocol: %s", proto)
+	}
+	namespace, name, err := c.buildResourceName(defaultNamespace, "secret", content, c.dynamicConfig.CrossNamespaceSecretPasswd)
+	if err != nil {
+		return nil, err
+	}
+	c.tracker.TrackRefName(track, convtypes.ResourceSecret, namespace+"/"+name)
+	secret, err := c.listers.secretLister.Secrets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
 // Implements acme.ClientResolver
 func (c *k8scache) GetKey() (crypto.Signer, error) {
 	secret, err := c.GetSecret(c.acmeSecretKeyName)
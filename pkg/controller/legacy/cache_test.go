@@ -17,7 +17,14 @@ limitations under the License.
 package legacy
 
 import (
+	"reflect"
+	"sort"
 	"testing"
+
+	api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerscore "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 func TestGetContentProtocol(t *testing.T) {
@@ -79,3 +86,52 @@ func TestGetContentProtocol(t *testing.T) {
 		}
 	}
 }
+
+func TestGetControllerPodList(t *testing.T) {
+	pod := func(namespace, name string, labels map[string]string) *api.Pod {
+		return &api.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, p := range []*api.Pod{
+		pod("ingress", "haproxy-1", map[string]string{"app": "haproxy", "pod-template-hash": "1"}),
+		pod("ingress", "haproxy-2", map[string]string{"app": "haproxy", "pod-template-hash": "2"}),
+		pod("ingress", "other-1", map[string]string{"app": "other"}),
+		pod("default", "haproxy-3", map[string]string{"app": "haproxy"}),
+	} {
+		_ = indexer.Add(p)
+	}
+	c := &k8scache{listers: &listers{
+		controllerNamespace: "ingress",
+		controllerPodName:   "haproxy-1",
+		controllerPodLister: listerscore.NewPodLister(indexer),
+	}}
+	pods, err := c.GetControllerPodList()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	if expected := []string{"haproxy-1", "haproxy-2"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("pods differ, expected %v but was %v", expected, names)
+	}
+	if !c.listers.isControllerPod(pod("ingress", "haproxy-4", map[string]string{"app": "haproxy"})) {
+		t.Errorf("expected haproxy-4 as a controller pod")
+	}
+	if c.listers.isControllerPod(pod("default", "haproxy-3", map[string]string{"app": "haproxy"})) {
+		t.Errorf("expected haproxy-3 from another namespace as not a controller pod")
+	}
+
+	// the labels of the pod in the cache must not be changed
+	own, _, _ := indexer.GetByKey("ingress/haproxy-1")
+	if _, found := own.(*api.Pod).Labels["pod-template-hash"]; !found {
+		t.Errorf("expected the cached controller pod unchanged")
+	}
+
+	c.listers.controllerPodLister = nil
+	if _, err := c.GetControllerPodList(); err == nil {
+		t.Errorf("expected an error without the controller pod lister")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
@@ -153,6 +154,14 @@ func (hc *HAProxyController) configController() {
 	if err := hc.instance.ParseTemplates(); err != nil {
 		klog.Exitf("error creating HAProxy instance: %v", err)
 	}
+	var haproxyVersion utils.HAProxyVersion
+	if !instanceOptions.IsExternal {
+		var err error
+		haproxyVersion, err = utils.LocalHAProxyVersion()
+		if err != nil {
+			hc.logger.Warn("cannot read haproxy version, keywords of newer versions will not be used: %v", err)
+		}
+	}
 	hc.converterOptions = &convtypes.ConverterOptions{
 		Logger:           hc.logger,
 		Cache:            hc.cache,
@@ -164,6 +173,7 @@ func (hc *HAProxyController) configController() {
 		AdminSocket:      instanceOptions.AdminSocket,
 		AcmeSocket:       instanceOptions.AcmeSocket,
 		AnnotationPrefix: hc.cfg.AnnPrefix,
+		PodName:          os.Getenv("POD_NAME"),
 		DefaultBackend:   hc.cfg.DefaultService,
 		DefaultCrtSecret: hc.cfg.DefaultSSLCertificate,
 		FakeCrtFile:      hc.createFakeCrtFile(),
@@ -174,6 +184,8 @@ func (hc *HAProxyController) configController() {
 		HasGatewayA2:     hc.cache.hasGateway(),
 		HasGatewayB1:     false,
 		EnableEPSlices:   hc.cfg.EnableEndpointSlicesAPI,
+		NamespaceLabels:  hc.cfg.WatchNamespaceLabels,
+		HAProxyVersion:   haproxyVersion,
 	}
 }
 
@@ -188,6 +200,11 @@ func (hc *HAProxyController) startServices() {
 			hc.instance.CalcIdleMetric()
 		}, hc.cfg.StatsCollectProcPeriod, hc.stopCh)
 	}
+	if hc.cfg.StatsCollectSrvPeriod.Milliseconds() > 0 {
+		go wait.Until(func() {
+			hc.instance.CheckServersState()
+		}, hc.cfg.StatsCollectSrvPeriod, hc.stopCh)
+	}
 	if hc.leaderelector != nil {
 		go hc.leaderelector.Run(hc.stopCh)
 	}
@@ -244,6 +261,17 @@ func (hc *HAProxyController) AcmeCheck() (int, error) {
 	return hc.acmeCheck("external call")
 }
 
+// CachePurge ...
+func (hc *HAProxyController) CachePurge(hostname string) error {
+	hc.writeModelMutex.Lock()
+	defer hc.writeModelMutex.Unlock()
+	err := hc.instance.CachePurge(hostname)
+	if err != nil {
+		hc.logger.Error("error purging cache of '%s': %v", hostname, err)
+	}
+	return err
+}
+
 // OnStartedLeading ...
 // implements LeaderSubscriber
 func (hc *HAProxyController) OnStartedLeading(ctx context.Context) {
@@ -25,6 +25,7 @@ import (
 	discoveryv1 "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	discovery "k8s.io/client-go/discovery"
 	"k8s.io/client-go/informers"
@@ -42,6 +43,7 @@ import (
 	gwapiinformersgatewayv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1alpha2"
 	gwapilistersgatewayv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1alpha2"
 
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/annotations"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
 )
 
@@ -64,6 +66,9 @@ type listers struct {
 	//
 	hasPodLister bool
 	//
+	controllerNamespace string
+	controllerPodName   string
+	//
 	ingressLister       listersnetworking.IngressLister
 	ingressClassLister  listersnetworking.IngressClassLister
 	gatewayLister       gwapilistersgatewayv1alpha2.GatewayLister
@@ -75,6 +80,8 @@ type listers struct {
 	secretLister        listerscore.SecretLister
 	configMapLister     listerscore.ConfigMapLister
 	podLister           listerscore.PodLister
+	controllerPodLister listerscore.PodLister
+	namespaceLister     listerscore.NamespaceLister
 	//
 	ingressInformer       cache.SharedInformer
 	ingressClassInformer  cache.SharedInformer
@@ -87,6 +94,8 @@ type listers struct {
 	secretInformer        cache.SharedInformer
 	configMapInformer     cache.SharedInformer
 	podInformer           cache.SharedInformer
+	controllerPodInformer cache.SharedInformer
+	namespaceInformer     cache.SharedInformer
 }
 
 func createListers(
@@ -95,9 +104,12 @@ func createListers(
 	recorder record.EventRecorder,
 	client types.Client,
 	watchGateway bool,
+	watchNamespaceLabels bool,
 	watchNamespace string,
 	isolateNamespace bool,
 	podWatch bool,
+	controllerNamespace string,
+	controllerPodName string,
 	resync time.Duration,
 	enableEndpointSlicesAPI bool,
 ) *listers {
@@ -140,6 +152,19 @@ func createListers(
 	} else {
 		l.createPodLister(localInformer.Core().V1().Pods())
 	}
+	if controllerNamespace != "" && controllerPodName != "" {
+		if podWatch && (clusterWatch || watchNamespace == controllerNamespace) {
+			// controller pods are already watched by the pod lister
+			l.createControllerPodLister(ingressInformer.Core().V1().Pods(), controllerNamespace, controllerPodName, false)
+		} else {
+			informer := informers.NewSharedInformerFactoryWithOptions(client, resync, informers.WithNamespace(controllerNamespace))
+			l.createControllerPodLister(informer.Core().V1().Pods(), controllerNamespace, controllerPodName, true)
+		}
+	}
+	if watchNamespaceLabels {
+		// namespaces are cluster scoped, the namespace option of the factory does not apply
+		l.createNamespaceLister(resourceInformer.Core().V1().Namespaces())
+	}
 
 	if watchGateway {
 		if hasGatewayAPI(client.GatewayAPIV1alpha2().Discovery(), gatewayv1alpha2.GroupVersion, "gatewayclass", "gateway", "httproute") {
@@ -216,6 +241,20 @@ func (l *listers) RunAsync(stopCh <-chan struct{}) {
 	go l.secretInformer.Run(stopCh)
 	go l.configMapInformer.Run(stopCh)
 	go l.podInformer.Run(stopCh)
+	if l.controllerPodInformer != nil {
+		go l.controllerPodInformer.Run(stopCh)
+		if !cache.WaitForCacheSync(stopCh, l.controllerPodInformer.HasSynced) {
+			syncFailed()
+			return
+		}
+	}
+	if l.namespaceInformer != nil {
+		go l.namespaceInformer.Run(stopCh)
+		if !cache.WaitForCacheSync(stopCh, l.namespaceInformer.HasSynced) {
+			syncFailed()
+			return
+		}
+	}
 	var synced bool
 	if l.enableEndpointSlicesAPI {
 		go l.endpointSliceInformer.Run(stopCh)
@@ -556,3 +595,96 @@ func (l *listers) createPodLister(informer informerscore.PodInformer) {
 		},
 	})
 }
+
+// createControllerPodLister watches the pods of the controller, changes on them
+// trigger a sync so the peers configuration is updated. run should be true if
+// the informer is not already started by another lister.
+func (l *listers) createControllerPodLister(informer informerscore.PodInformer, namespace, podName string, run bool) {
+	l.controllerNamespace = namespace
+	l.controllerPodName = podName
+	l.controllerPodLister = informer.Lister()
+	if run {
+		l.controllerPodInformer = informer.Informer()
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if l.isControllerPod(obj.(*api.Pod)) {
+				l.events.Notify(nil, obj)
+			}
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldPod := old.(*api.Pod)
+			curPod := cur.(*api.Pod)
+			if oldPod.Status.PodIP != curPod.Status.PodIP ||
+				oldPod.DeletionTimestamp != curPod.DeletionTimestamp ||
+				!reflect.DeepEqual(oldPod.Labels, curPod.Labels) {
+				if l.isControllerPod(oldPod) || l.isControllerPod(curPod) {
+					l.events.Notify(old, cur)
+				}
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			pod, ok := obj.(*api.Pod)
+			if !ok || l.isControllerPod(pod) {
+				l.events.Notify(obj, nil)
+			}
+		},
+	})
+}
+
+func (l *listers) isControllerPod(pod *api.Pod) bool {
+	if pod.Namespace != l.controllerNamespace {
+		return false
+	}
+	selector, err := l.controllerPodSelector()
+	return err == nil && selector.Matches(labels.Set(pod.Labels))
+}
+
+// controllerPodSelector builds a selector that matches all the controller pods
+// from the template labels of the pod the controller is running on.
+func (l *listers) controllerPodSelector() (labels.Selector, error) {
+	pod, err := l.controllerPodLister.Pods(l.controllerNamespace).Get(l.controllerPodName)
+	if err != nil {
+		return nil, err
+	}
+	podLabels := labels.Set{}
+	for k, v := range pod.Labels {
+		podLabels[k] = v
+	}
+	// remove labels that uniquely identify a pod
+	delete(podLabels, "controller-revision-hash")
+	delete(podLabels, "pod-template-generation")
+	delete(podLabels, "pod-template-hash")
+	return labels.SelectorFromSet(podLabels), nil
+}
+
+func (l *listers) createNamespaceLister(informer informerscore.NamespaceInformer) {
+	l.namespaceLister = informer.Lister()
+	l.namespaceInformer = informer.Informer()
+	l.namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if _, found := obj.(*api.Namespace).Labels[annotations.HostDelegationLabel]; found {
+				l.events.Notify(nil, nil)
+			}
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldNS := old.(*api.Namespace)
+			curNS := cur.(*api.Namespace)
+			if !reflect.DeepEqual(oldNS.Labels, curNS.Labels) {
+				// namespace labels can change delegations and annotation policies
+				// of any hostname, a full resync is needed
+				l.events.Notify(nil, nil)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			ns, ok := obj.(*api.Namespace)
+			if !ok {
+				l.events.Notify(nil, nil)
+				return
+			}
+			if _, found := ns.Labels[annotations.HostDelegationLabel]; found {
+				l.events.Notify(nil, nil)
+			}
+		},
+	})
+}
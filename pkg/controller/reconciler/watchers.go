@@ -39,7 +39,9 @@ import (
 
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/services"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/annotations"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	convutils "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/utils"
 )
 
 func createWatchers(ctx context.Context, cfg *config.Config, val services.IsValidResource) *watchers {
@@ -63,6 +65,9 @@ type watchers struct {
 
 func (w *watchers) getHandlers() []*hdlr {
 	handlers := w.handlersCore()
+	if w.cfg.WatchNamespaceLabels {
+		handlers = append(handlers, w.handlersNamespace()...)
+	}
 	handlers = append(handlers, w.handlersIngress()...)
 	if w.cfg.HasGatewayA2 {
 		handlers = append(handlers, w.handlersGatewayv1alpha2()...)
@@ -138,7 +143,8 @@ func (w *watchers) handlersCore() []*hdlr {
 				predicate.NewPredicateFuncs(func(o client.Object) bool {
 					cm := o.(*api.ConfigMap)
 					key := cm.Namespace + "/" + cm.Name
-					return key == w.cfg.ConfigMapName || key == w.cfg.TCPConfigMapName
+					_, resourceBackend := cm.Labels[types.ResourceBackendLabel]
+					return key == w.cfg.ConfigMapName || key == w.cfg.TCPConfigMapName || resourceBackend
 				}),
 			},
 		},
@@ -206,7 +212,42 @@ func (w *watchers) handlersCore() []*hdlr {
 				predicate.Funcs{
 					CreateFunc: func(e event.CreateEvent) bool { return false },
 					UpdateFunc: func(e event.UpdateEvent) bool {
-						return e.ObjectOld.GetDeletionTimestamp() != e.ObjectNew.GetDeletionTimestamp()
+						if e.ObjectOld.GetDeletionTimestamp() != e.ObjectNew.GetDeletionTimestamp() {
+							return true
+						}
+						// pods waiting for the readiness gate, whose containers changed their readiness
+						if w.cfg.PodReadinessGate &&
+							convutils.ReadinessGatePending(e.ObjectOld.(*api.Pod)) != convutils.ReadinessGatePending(e.ObjectNew.(*api.Pod)) {
+							return true
+						}
+						// controller pods receiving or changing their IP, used to build the peers section
+						return e.ObjectNew.GetNamespace() == w.cfg.PodNamespace &&
+							e.ObjectOld.(*api.Pod).Status.PodIP != e.ObjectNew.(*api.Pod).Status.PodIP
+					},
+				},
+			},
+		},
+	}
+}
+
+func (w *watchers) handlersNamespace() []*hdlr {
+	hasDelegation := func(o client.Object) bool {
+		_, found := o.GetLabels()[annotations.HostDelegationLabel]
+		return found
+	}
+	return []*hdlr{
+		{
+			// namespace labels can change delegations and annotation policies
+			// of any hostname, a full resync is needed
+			typ:  &api.Namespace{},
+			res:  types.ResourceNamespace,
+			full: true,
+			pr: []predicate.Predicate{
+				predicate.Funcs{
+					CreateFunc: func(e event.CreateEvent) bool { return hasDelegation(e.Object) },
+					DeleteFunc: func(e event.DeleteEvent) bool { return hasDelegation(e.Object) },
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
 					},
 				},
 			},
@@ -240,7 +281,7 @@ func (w *watchers) handlersIngress() []*hdlr {
 			},
 			pr: []predicate.Predicate{
 				predicate.Or(
-					predicate.AnnotationChangedPredicate{},
+					annotationChangedPredicate(services.BackendHealthAnnotation),
 					predicate.GenerationChangedPredicate{},
 				),
 				predicate.Funcs{
@@ -573,6 +614,30 @@ func (h *hdlr) notify(event string, o client.Object, q workqueue.RateLimitingInt
 	}
 }
 
+// annotationChangedPredicate works like predicate.AnnotationChangedPredicate,
+// except that changes on the ignored annotations, which are managed by the
+// controller itself, do not trigger a reconciliation.
+func annotationChangedPredicate(ignore ...string) predicate.Predicate {
+	annotations := func(obj client.Object) map[string]string {
+		ann := make(map[string]string, len(obj.GetAnnotations()))
+		for key, value := range obj.GetAnnotations() {
+			ann[key] = value
+		}
+		for _, key := range ignore {
+			delete(ann, key)
+		}
+		return ann
+	}
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return !reflect.DeepEqual(annotations(e.ObjectOld), annotations(e.ObjectNew))
+		},
+	}
+}
+
 func appenddedup(slice []string, s string) []string {
 	for _, item := range slice {
 		if item == s {
@@ -58,6 +58,13 @@ func createCacheFacade(ctx context.Context, client client.Client, config *config
 	}
 }
 
+// newValidateCache creates a cache facade for the converters of a validation.
+// It is bound to the tracker and dynamic config of the validation, and writes
+// certificate files in the directories of cfg, which should be temporary ones.
+func (c *c) newValidateCache(cfg *config.Config, tracker convtypes.Tracker, dynconfig *convtypes.DynamicConfig) *c {
+	return createCacheFacade(c.ctx, c.client, cfg, tracker, CreateSSLCerts(cfg), dynconfig, func(client.Object) {})
+}
+
 type c struct {
 	ctx       context.Context
 	log       logr.Logger
@@ -436,6 +443,18 @@ func (c *c) GetNamespace(name string) (*api.Namespace, error) {
 	return &ns, err
 }
 
+func (c *c) GetNamespaceList() ([]*api.Namespace, error) {
+	list := api.NamespaceList{}
+	if err := c.client.List(c.ctx, &list); err != nil {
+		return nil, err
+	}
+	nsList := make([]*api.Namespace, len(list.Items))
+	for i := range list.Items {
+		nsList[i] = &list.Items[i]
+	}
+	return nsList, nil
+}
+
 func isTerminatingPod(svc *api.Service, pod *api.Pod) bool {
 	if svc.GetNamespace() != pod.GetNamespace() {
 		return false
@@ -485,6 +504,45 @@ func (c *c) GetPodNamespace() string {
 	return c.config.ElectionNamespace
 }
 
+func (c *c) GetControllerPodList() ([]*api.Pod, error) {
+	podList, err := listControllerPods(c.ctx, c.client, c.config)
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*api.Pod, len(podList))
+	for i := range podList {
+		pods[i] = &podList[i]
+	}
+	return pods, nil
+}
+
+func listControllerPods(ctx context.Context, cli client.Client, cfg *config.Config) ([]api.Pod, error) {
+	// read controller's pod - we need the pod's template labels to find all the other pods
+	if cfg.PodName == "" {
+		return nil, fmt.Errorf("POD_NAME envvar was not configured")
+	}
+	pod := api.Pod{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: cfg.PodNamespace, Name: cfg.PodName}, &pod); err != nil {
+		return nil, err
+	}
+
+	// remove labels that uniquely identify a pod
+	podLabels := pod.GetLabels()
+	delete(podLabels, "controller-revision-hash")
+	delete(podLabels, "pod-template-generation")
+	delete(podLabels, "pod-template-hash")
+
+	// read all controller's pod
+	podList := api.PodList{}
+	if err := cli.List(ctx, &podList, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(podLabels),
+		Namespace:     cfg.PodNamespace,
+	}); err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
+
 var contentProtocolRegex = regexp.MustCompile(`^([a-z]+)://(.*)$`)
 
 func getContentProtocol(input string) (proto, content string) {
@@ -642,6 +700,49 @@ func (c *c) GetPasswdSecretContent(defaultNamespace, secretName string, track []
 	return data, nil
 }
 
+func (c *c) GetJWTSecretContent(defaultNamespace, secretName string, track []convtypes.TrackingRef) ([]byte, error) {
+	proto, content := getContentProtocol(secretName)
+	if proto == "file" {
+		return os.ReadFile(content)
+	} else if proto != "secret" {
+		return nil, fmt.Errorf("unsupported protocol: %s", proto)
+	}
+	namespace, name, err := buildResourceName(defaultNamespace, "secret", content, c.dynconfig.CrossNamespaceSecretJWT)
+	if err != nil {
+		return nil, err
+	}
+	c.tracker.TrackRefName(track, convtypes.ResourceSecret, namespace+"/"+name)
+	secret := api.Secret{}
+	err = c.client.Get(c.ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret)
+	if err != nil {
+		return nil, err
+	}
+	keyName := "jwt.key"
+	data, found := secret.Data[keyName]
+	if !found {
+		return nil, fmt.Errorf("secret '%s/%s' does not have key '%s'", namespace, name, keyName)
+	}
+	return data, nil
+}
+
+func (c *c) GetAPIKeySecretContent(defaultNamespace, secretName string, track []convtypes.TrackingRef) (map[string][]byte, error) {
+	proto, content := getContentProtocol(secretName)
+	if proto != "secret" {
+		return nil, fmt.Errorf("unsupported protocol: %s", proto)
+	}
+	namespace, name, err := buildResourceName(defaultNamespace, "secret", content, c.dynconfig.CrossNamespaceSecretPasswd)
+	if err != nil {
+		return nil, err
+	}
+	c.tracker.TrackRefName(track, convtypes.ResourceSecret, namespace+"/"+name)
+	secret := api.Secret{}
+	err = c.client.Get(c.ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret)
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
 func (c *c) SwapChangedObjects() *convtypes.ChangedObjects {
 	// deprecated func
 	// converter is adapted to not call this facade
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var errMemClientReadOnly = fmt.Errorf("resources read from manifests cannot be changed by the controller")
+
+// newMemClient creates a client whose resources are the ones read from
+// manifest files, used when there is no API server to connect to. Reads
+// are served from memory, writes from the controller services, like
+// status updates, are refused. Resources are changed via set and remove.
+func newMemClient(scheme *runtime.Scheme, objs []client.Object) (*memClient, error) {
+	c := &memClient{
+		scheme: scheme,
+		objs:   map[schema.GroupVersionKind]map[types.NamespacedName]client.Object{},
+	}
+	for _, obj := range objs {
+		if err := c.set(obj); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+type memClient struct {
+	scheme *runtime.Scheme
+	mutex  sync.RWMutex
+	objs   map[schema.GroupVersionKind]map[types.NamespacedName]client.Object
+}
+
+var _ client.Client = &memClient{}
+
+// set adds a resource, or replaces a resource with the same kind, namespace and name.
+func (c *memClient) set(obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	objs := c.objs[gvk]
+	if objs == nil {
+		objs = map[types.NamespacedName]client.Object{}
+		c.objs[gvk] = objs
+	}
+	objs[client.ObjectKeyFromObject(obj)] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+// remove removes a resource, it is a no-op if the resource is not found.
+func (c *memClient) remove(obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.objs[gvk], client.ObjectKeyFromObject(obj))
+	return nil
+}
+
+func (c *memClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	c.mutex.RLock()
+	stored, found := c.objs[gvk][key]
+	c.mutex.RUnlock()
+	if !found {
+		return apierrors.NewNotFound(c.groupResource(gvk), key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *memClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.FieldSelector != nil && !listOpts.FieldSelector.Empty() {
+		return fmt.Errorf("field selectors are not supported on resources read from manifests")
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	objs := c.objs[gvk]
+	keys := make([]types.NamespacedName, 0, len(objs))
+	for key := range objs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	items := make([]runtime.Object, 0, len(keys))
+	for _, key := range keys {
+		obj := objs[key]
+		if listOpts.Namespace != "" && obj.GetNamespace() != listOpts.Namespace {
+			continue
+		}
+		if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		items = append(items, obj.DeepCopyObject())
+	}
+	return meta.SetList(list, items)
+}
+
+func (c *memClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return errMemClientReadOnly
+}
+
+func (c *memClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return errMemClientReadOnly
+}
+
+func (c *memClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return errMemClientReadOnly
+}
+
+func (c *memClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return errMemClientReadOnly
+}
+
+func (c *memClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	return errMemClientReadOnly
+}
+
+func (c *memClient) Status() client.SubResourceWriter {
+	return memSubResource{}
+}
+
+func (c *memClient) SubResource(subResource string) client.SubResourceClient {
+	return memSubResource{}
+}
+
+func (c *memClient) Scheme() *runtime.Scheme {
+	return c.scheme
+}
+
+func (c *memClient) RESTMapper() meta.RESTMapper {
+	return nil
+}
+
+func (c *memClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return apiutil.GVKForObject(obj, c.scheme)
+}
+
+func (c *memClient) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return false, err
+	}
+	return !clusterScopedKinds[gvk.Kind], nil
+}
+
+func (c *memClient) groupResource(gvk schema.GroupVersionKind) schema.GroupResource {
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	return resource.GroupResource()
+}
+
+// memSubResource refuses all the subresource operations, e.g. status updates,
+// since there is no API server to store them.
+type memSubResource struct{}
+
+func (memSubResource) Get(ctx context.Context, obj, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	return errMemClientReadOnly
+}
+
+func (memSubResource) Create(ctx context.Context, obj, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return errMemClientReadOnly
+}
+
+func (memSubResource) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return errMemClientReadOnly
+}
+
+func (memSubResource) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return errMemClientReadOnly
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMemClientGet(t *testing.T) {
+	cli := setupMemClient(t)
+	ctx := context.Background()
+
+	svc := &api.Service{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "svc1"}, svc); err != nil {
+		t.Fatalf("unexpected error reading svc1: %v", err)
+	}
+	if port := svc.Spec.Ports[0].Port; port != 8080 {
+		t.Errorf("expected port 8080 on svc1, found %d", port)
+	}
+
+	// changing the returned object should not change the stored one
+	svc.Spec.Ports[0].Port = 80
+	svc = &api.Service{}
+	_ = cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "svc1"}, svc)
+	if port := svc.Spec.Ports[0].Port; port != 8080 {
+		t.Errorf("expected port 8080 on svc1 after changing a copy, found %d", port)
+	}
+
+	// same name, distinct kind
+	err := cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "svc1"}, &networking.Ingress{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found reading an ingress named svc1, found: %v", err)
+	}
+
+	cls := &networking.IngressClass{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: "haproxy"}, cls); err != nil {
+		t.Errorf("unexpected error reading ingress class: %v", err)
+	}
+
+	if err := cli.remove(&api.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc1"}}); err != nil {
+		t.Errorf("unexpected error removing svc1: %v", err)
+	}
+	err = cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "svc1"}, &api.Service{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found reading svc1 after removing, found: %v", err)
+	}
+}
+
+func TestMemClientList(t *testing.T) {
+	testCases := []struct {
+		opts     []client.ListOption
+		expected []string
+	}{
+		// 0
+		{
+			expected: []string{"default/svc1", "default/svc2", "other/svc1"},
+		},
+		// 1
+		{
+			opts:     []client.ListOption{client.InNamespace("default")},
+			expected: []string{"default/svc1", "default/svc2"},
+		},
+		// 2
+		{
+			opts:     []client.ListOption{&client.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "echo"})}},
+			expected: []string{"default/svc2", "other/svc1"},
+		},
+		// 3
+		{
+			opts:     []client.ListOption{client.InNamespace("other"), client.MatchingLabels{"app": "echo"}},
+			expected: []string{"other/svc1"},
+		},
+		// 4
+		{
+			opts: []client.ListOption{client.InNamespace("none")},
+		},
+	}
+	cli := setupMemClient(t)
+	for i, test := range testCases {
+		list := api.ServiceList{}
+		if err := cli.List(context.Background(), &list, test.opts...); err != nil {
+			t.Errorf("unexpected error on %d: %v", i, err)
+			continue
+		}
+		var actual []string
+		for _, svc := range list.Items {
+			actual = append(actual, svc.Namespace+"/"+svc.Name)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("services differ on %d - expected: %v - actual: %v", i, test.expected, actual)
+		}
+	}
+}
+
+func TestMemClientReadOnly(t *testing.T) {
+	cli := setupMemClient(t)
+	ctx := context.Background()
+	svc := &api.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc1"}}
+	errs := map[string]error{
+		"create": cli.Create(ctx, svc),
+		"update": cli.Update(ctx, svc),
+		"delete": cli.Delete(ctx, svc),
+		"status": cli.Status().Patch(ctx, svc, client.MergeFrom(svc)),
+	}
+	for op, err := range errs {
+		if err != errMemClientReadOnly {
+			t.Errorf("expected read only error on %s, found: %v", op, err)
+		}
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(svc), svc); err != nil {
+		t.Errorf("unexpected error reading svc1: %v", err)
+	}
+}
+
+func setupMemClient(t *testing.T) *memClient {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	svc := func(namespace, name string, labels map[string]string) *api.Service {
+		return &api.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+			Spec:       api.ServiceSpec{Ports: []api.ServicePort{{Port: 8080}}},
+		}
+	}
+	cli, err := newMemClient(scheme, []client.Object{
+		svc("other", "svc1", map[string]string{"app": "echo"}),
+		svc("default", "svc2", map[string]string{"app": "echo"}),
+		svc("default", "svc1", nil),
+		&networking.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "haproxy"}},
+	})
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	return cli
+}
@@ -24,15 +24,20 @@ import (
 )
 
 type metrics struct {
-	responseTime       *prometheus.HistogramVec
-	ctlProcTimeSum     *prometheus.CounterVec
-	ctlProcCount       *prometheus.CounterVec
-	procSecondsCounter *prometheus.CounterVec
-	updatesCounter     *prometheus.CounterVec
-	updateSuccessGauge *prometheus.GaugeVec
-	certExpireGauge    *prometheus.GaugeVec
-	certSigningCounter *prometheus.CounterVec
-	lastTrack          time.Time
+	responseTime        *prometheus.HistogramVec
+	ctlProcTimeSum      *prometheus.CounterVec
+	ctlProcCount        *prometheus.CounterVec
+	procSecondsCounter  *prometheus.CounterVec
+	updatesCounter      *prometheus.CounterVec
+	reloadReasonsCount  *prometheus.CounterVec
+	updateSuccessGauge  *prometheus.GaugeVec
+	fallbackConfigGauge *prometheus.GaugeVec
+	certExpireGauge     *prometheus.GaugeVec
+	certSigningCounter  *prometheus.CounterVec
+	serverDownCounter   *prometheus.CounterVec
+	serversDownGauge    *prometheus.GaugeVec
+	backendRsp5xxGauge  *prometheus.GaugeVec
+	lastTrack           time.Time
 }
 
 func (m *metrics) register(reg prometheus.Registerer) {
@@ -42,9 +47,14 @@ func (m *metrics) register(reg prometheus.Registerer) {
 		m.ctlProcCount,
 		m.procSecondsCounter,
 		m.updatesCounter,
+		m.reloadReasonsCount,
 		m.updateSuccessGauge,
+		m.fallbackConfigGauge,
 		m.certExpireGauge,
 		m.certSigningCounter,
+		m.serverDownCounter,
+		m.serversDownGauge,
+		m.backendRsp5xxGauge,
 	)
 }
 
@@ -92,6 +102,14 @@ func createMetrics(bucketsResponseTime []float64) *metrics {
 			},
 			[]string{"status"},
 		),
+		reloadReasonsCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "reload_reasons_total",
+				Help:      "Cumulative number of full updates by the reason that prevented a dynamic update, and the namespace of the resource that caused it.",
+			},
+			[]string{"reason", "namespace"},
+		),
 		updateSuccessGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -100,6 +118,14 @@ func createMetrics(bucketsResponseTime []float64) *metrics {
 			},
 			[]string{},
 		),
+		fallbackConfigGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "fallback_config",
+				Help:      "Whether haproxy is running the last known good configuration because the current one was rejected.",
+			},
+			[]string{},
+		),
 		certExpireGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -116,6 +142,30 @@ func createMetrics(bucketsResponseTime []float64) *metrics {
 			},
 			[]string{"domains", "reason", "success"},
 		),
+		serverDownCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "backend_server_down_count",
+				Help:      "Cumulative number of backend servers going down, either by active or passive health checks.",
+			},
+			[]string{"backend"},
+		),
+		serversDownGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "backend_servers_down",
+				Help:      "Number of backend servers currently down.",
+			},
+			[]string{"backend"},
+		),
+		backendRsp5xxGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "backend_response_5xx_ratio",
+				Help:      "Ratio of 5xx responses of a backend between the last two backends health summaries.",
+			},
+			[]string{"backend"},
+		),
 	}
 	return metrics
 }
@@ -132,6 +182,10 @@ func (m *metrics) HAProxySetSSLCertResponseTime(duration time.Duration) {
 	m.responseTime.WithLabelValues("set_ssl_cert").Observe(duration.Seconds())
 }
 
+func (m *metrics) HAProxySetMapResponseTime(duration time.Duration) {
+	m.responseTime.WithLabelValues("set_map").Observe(duration.Seconds())
+}
+
 func (m *metrics) ControllerProcTime(task string, duration time.Duration) {
 	m.ctlProcTimeSum.WithLabelValues(task).Add(duration.Seconds())
 	m.ctlProcCount.WithLabelValues(task).Inc()
@@ -160,11 +214,20 @@ func (m *metrics) IncUpdateFull() {
 	m.updatesCounter.WithLabelValues("full").Inc()
 }
 
+func (m *metrics) IncReloadReason(reason, namespace string) {
+	m.reloadReasonsCount.WithLabelValues(reason, namespace).Inc()
+}
+
 func (m *metrics) UpdateSuccessful(success bool) {
 	value := map[bool]float64{false: 0, true: 1}
 	m.updateSuccessGauge.WithLabelValues().Set(value[success])
 }
 
+func (m *metrics) SetFallbackConfig(fallback bool) {
+	value := map[bool]float64{false: 0, true: 1}
+	m.fallbackConfigGauge.WithLabelValues().Set(value[fallback])
+}
+
 func (m *metrics) SetCertExpireDate(domain, cn string, notAfter *time.Time) {
 	if notAfter == nil {
 		m.certExpireGauge.DeleteLabelValues(domain, cn)
@@ -188,3 +251,23 @@ func (m *metrics) IncCertSigningExpiring(domains string, success bool) {
 func (m *metrics) IncCertSigningOutdated(domains string, success bool) {
 	m.certSigningCounter.WithLabelValues(domains, "outdated", strconv.FormatBool(success)).Inc()
 }
+
+func (m *metrics) IncServerDown(backend string) {
+	m.serverDownCounter.WithLabelValues(backend).Inc()
+}
+
+func (m *metrics) SetServersDown(backend string, count int) {
+	if count == 0 {
+		m.serversDownGauge.DeleteLabelValues(backend)
+		return
+	}
+	m.serversDownGauge.WithLabelValues(backend).Set(float64(count))
+}
+
+func (m *metrics) SetBackendRsp5xxRatio(backend string, ratio float64) {
+	m.backendRsp5xxGauge.WithLabelValues(backend).Set(ratio)
+}
+
+func (m *metrics) DeleteBackendRsp5xxRatio(backend string) {
+	m.backendRsp5xxGauge.DeleteLabelValues(backend)
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/tracker"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+// Render reads resources from manifest files and writes the haproxy
+// configuration files they would produce, without connecting to a
+// cluster. The regular cache facade is used, backed by an in-memory client
+// whose objects are the ones found in the manifests.
+func Render(opt *config.Options, render *config.RenderOptions) error {
+	cfg, err := config.CreateRender(opt, render)
+	if err != nil {
+		return err
+	}
+	ctx := cfg.RootContext
+	log := logr.FromContextOrDiscard(ctx).WithName("render")
+
+	objs, err := readManifests(cfg.Scheme, utils.Split(render.Manifests, ","))
+	if err != nil {
+		return err
+	}
+	if opt.WatchGateway {
+		configGatewayAPIFromObjects(cfg, objs)
+	}
+	log.Info("resources read from manifests", "count", len(objs))
+	cli, err := newMemClient(cfg.Scheme, objs)
+	if err != nil {
+		return err
+	}
+
+	legacylogger := initLogFactory(ctx)
+	sslCerts := CreateSSLCerts(cfg)
+	fakeCrt, fakeCA, err := sslCerts.createFakeCertAndCA()
+	if err != nil {
+		return fmt.Errorf("error generating self signed fake certificate and certificate authority: %w", err)
+	}
+	dynConfig := &convtypes.DynamicConfig{
+		StaticCrossNamespaceSecrets: cfg.AllowCrossNamespace,
+	}
+	tracker := tracker.NewTracker()
+	cache := createCacheFacade(ctx, cli, cfg, tracker, sslCerts, dynConfig, func(client.Object) {})
+	var rootFSPrefix string
+	if cfg.LocalFSPrefix != "" {
+		rootFSPrefix = "rootfs"
+	}
+	outputDir := filepath.Dir(cfg.DefaultDirMaps)
+	instanceOptions := haproxy.InstanceOptions{
+		RootFSPrefix:   rootFSPrefix,
+		HAProxyCfgDir:  outputDir,
+		HAProxyMapsDir: cfg.DefaultDirMaps,
+		AdminSocket:    cfg.DefaultDirVarRun + "/admin.sock",
+		AcmeSocket:     cfg.DefaultDirVarRun + "/acme.sock",
+		BackendShards:  cfg.BackendShards,
+	}
+	converterOptions := &convtypes.ConverterOptions{
+		Logger:           legacylogger.new("converter"),
+		Cache:            cache,
+		Tracker:          tracker,
+		DynamicConfig:    dynConfig,
+		AdminSocket:      instanceOptions.AdminSocket,
+		AcmeSocket:       instanceOptions.AcmeSocket,
+		AnnotationPrefix: cfg.AnnPrefix,
+		DefaultBackend:   cfg.DefaultService,
+		DefaultCrtSecret: cfg.DefaultSSLCertificate,
+		FakeCrtFile:      fakeCrt,
+		FakeCAFile:       fakeCA,
+		DisableKeywords:  cfg.DisableKeywords,
+		HasGatewayA2:     cfg.HasGatewayA2,
+		HasGatewayB1:     cfg.HasGatewayB1,
+		HasGatewayV1:     cfg.HasGatewayV1,
+		HasTCPRouteA2:    cfg.HasTCPRouteA2,
+		HAProxyVersion:   cfg.HAProxyVersion,
+	}
+	changed := &convtypes.ChangedObjects{
+		NeedFullSync: true,
+	}
+	if cfg.ConfigMapName != "" {
+		configMap, err := cache.GetConfigMap(cfg.ConfigMapName)
+		if err != nil {
+			return fmt.Errorf("error reading global ConfigMap: %w", err)
+		}
+		changed.GlobalConfigMapDataCur = configMap.Data
+	}
+	if cfg.TCPConfigMapName != "" {
+		configMap, err := cache.GetConfigMap(cfg.TCPConfigMapName)
+		if err != nil {
+			return fmt.Errorf("error reading TCP services ConfigMap: %w", err)
+		}
+		changed.TCPConfigMapDataCur = configMap.Data
+	}
+
+	instance := haproxy.CreateInstance(legacylogger.new("haproxy"), instanceOptions)
+	if err := instance.ParseTemplates(); err != nil {
+		return fmt.Errorf("error creating HAProxy instance: %w", err)
+	}
+	converters.NewConverter(utils.NewTimer(nil), instance.Config(), changed, converterOptions).Sync()
+	if render.Check {
+		if err := instance.CheckConfig(); err != nil {
+			return fmt.Errorf("configuration check failed: %w", err)
+		}
+	} else if err := instance.WriteConfig(); err != nil {
+		return err
+	}
+	log.Info("configuration successfully rendered", "output-dir", outputDir, "checked", render.Check)
+	return nil
+}
+
+// readManifests reads all the resources found in a list of files or directories.
+// Unsupported kinds are ignored; namespaced resources without a namespace are
+// added to the default namespace, just like kubectl does.
+func readManifests(scheme *runtime.Scheme, paths []string) ([]client.Object, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if file != path && strings.HasPrefix(d.Name(), ".") {
+				// hidden files and directories, like the ones created by editors
+				// or the timestamped directories of a mounted ConfigMap
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			ext := strings.ToLower(filepath.Ext(file))
+			if file == path || ext == ".yaml" || ext == ".yml" || ext == ".json" {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifests: %w", err)
+		}
+	}
+	var objs []client.Object
+	for _, file := range files {
+		fileObjs, err := readManifestFile(scheme, file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifest '%s': %w", file, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+	return objs, nil
+}
+
+func readManifestFile(scheme *runtime.Scheme, file string) ([]client.Object, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var objs []client.Object
+	decoder := yaml.NewYAMLOrJSONDecoder(bufio.NewReader(f), 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			// empty document, e.g. a trailing `---`
+			continue
+		}
+		items := []unstructured.Unstructured{*u}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, err
+			}
+			items = list.Items
+		}
+		for i := range items {
+			obj, err := convertManifest(scheme, &items[i])
+			if err != nil {
+				return nil, err
+			}
+			if obj != nil {
+				objs = append(objs, obj)
+			}
+		}
+	}
+	return objs, nil
+}
+
+var clusterScopedKinds = map[string]bool{
+	"GatewayClass": true,
+	"IngressClass": true,
+	"Namespace":    true,
+	"Node":         true,
+}
+
+func convertManifest(scheme *runtime.Scheme, u *unstructured.Unstructured) (client.Object, error) {
+	gvk := u.GroupVersionKind()
+	if !scheme.Recognizes(gvk) {
+		return nil, nil
+	}
+	robj, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, robj); err != nil {
+		return nil, fmt.Errorf("error converting %s '%s': %w", gvk.Kind, u.GetName(), err)
+	}
+	obj, ok := robj.(client.Object)
+	if !ok {
+		return nil, nil
+	}
+	if obj.GetNamespace() == "" && !clusterScopedKinds[gvk.Kind] {
+		obj.SetNamespace("default")
+	}
+	// resourceVersion has no meaning outside of an API server
+	obj.SetResourceVersion("")
+	setManifestDefaults(obj)
+	return obj, nil
+}
+
+// setManifestDefaults assigns the default values the API server would assign
+// to the fields used by the converters, e.g. the protocol of a port, which is
+// compared when matching service and endpoint ports.
+func setManifestDefaults(obj client.Object) {
+	switch obj := obj.(type) {
+	case *api.Service:
+		for i := range obj.Spec.Ports {
+			port := &obj.Spec.Ports[i]
+			if port.Protocol == "" {
+				port.Protocol = api.ProtocolTCP
+			}
+			if port.TargetPort == (intstr.IntOrString{}) {
+				port.TargetPort = intstr.FromInt32(port.Port)
+			}
+		}
+		if obj.Spec.Type == "" {
+			obj.Spec.Type = api.ServiceTypeClusterIP
+		}
+	case *api.Endpoints:
+		for i := range obj.Subsets {
+			for j := range obj.Subsets[i].Ports {
+				port := &obj.Subsets[i].Ports[j]
+				if port.Protocol == "" {
+					port.Protocol = api.ProtocolTCP
+				}
+			}
+		}
+	case *discoveryv1.EndpointSlice:
+		for i := range obj.Ports {
+			if obj.Ports[i].Protocol == nil {
+				obj.Ports[i].Protocol = ptr.To(api.ProtocolTCP)
+			}
+		}
+	}
+}
+
+// configGatewayAPIFromObjects enables the gateway API versions found in the
+// manifests, using the same precedence of the API discovery of a cluster.
+func configGatewayAPIFromObjects(cfg *config.Config, objs []client.Object) {
+	found := map[string]bool{}
+	var tcpA2 bool
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Group != gatewayv1.GroupName {
+			continue
+		}
+		switch gvk.Kind {
+		case "GatewayClass", "Gateway", "HTTPRoute":
+			found[gvk.Version] = true
+		case "TCPRoute":
+			tcpA2 = tcpA2 || gvk.Version == gatewayv1alpha2.GroupVersion.Version
+		}
+	}
+	gwV1 := found[gatewayv1.GroupVersion.Version]
+	gwB1 := found[gatewayv1beta1.GroupVersion.Version]
+	gwA2 := found[gatewayv1alpha2.GroupVersion.Version]
+	cfg.HasGatewayV1 = gwV1
+	cfg.HasGatewayB1 = gwB1 && !cfg.HasGatewayV1
+	cfg.HasGatewayA2 = gwA2 && !cfg.HasGatewayB1
+	cfg.HasTCPRouteA2 = tcpA2 && (gwV1 || gwB1 || gwA2)
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	api "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+)
+
+func TestReadManifests(t *testing.T) {
+	testCases := []struct {
+		files    map[string]string
+		paths    []string
+		expected []string
+		expErr   string
+	}{
+		// 0
+		{
+			files: map[string]string{
+				"app.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: echo
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: echo
+  namespace: app
+---
+`,
+			},
+			expected: []string{"*v1.Service default/echo", "*v1.Ingress app/echo"},
+		},
+		// 1
+		{
+			files: map[string]string{
+				"list.yml": `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: tls
+- apiVersion: networking.k8s.io/v1
+  kind: IngressClass
+  metadata:
+    name: haproxy
+`,
+				"cm.json": `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"global","namespace":"ingress"}}`,
+			},
+			expected: []string{"*v1.ConfigMap ingress/global", "*v1.Secret default/tls", "*v1.IngressClass /haproxy"},
+		},
+		// 2
+		{
+			files: map[string]string{
+				"crd.yaml": `
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: unknown
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: echo
+`,
+				"README.md":         "not a manifest",
+				".hidden.yaml":      "apiVersion: v1\nkind: Service\nmetadata:\n  name: hidden1\n",
+				"..data/echo.yaml":  "apiVersion: v1\nkind: Service\nmetadata:\n  name: hidden2\n",
+				"sub/route.yaml":    "apiVersion: gateway.networking.k8s.io/v1\nkind: HTTPRoute\nmetadata:\n  name: echo\n",
+				"sub/sub/gw.yaml":   "apiVersion: gateway.networking.k8s.io/v1\nkind: GatewayClass\nmetadata:\n  name: haproxy\n",
+				"sub/.editor.swp":   "not a manifest",
+				"sub/endpoint.yaml": "",
+			},
+			expected: []string{"*v1.Service default/echo", "*v1.HTTPRoute default/echo", "*v1.GatewayClass /haproxy"},
+		},
+		// 3
+		{
+			files: map[string]string{
+				"echo.txt": "apiVersion: v1\nkind: Service\nmetadata:\n  name: echo\n",
+				"ign.txt":  "apiVersion: v1\nkind: Service\nmetadata:\n  name: ignored\n",
+			},
+			paths:    []string{"echo.txt"},
+			expected: []string{"*v1.Service default/echo"},
+		},
+		// 4
+		{
+			files: map[string]string{
+				"app.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: [echo\n",
+			},
+			expErr: "error reading manifest '<dir>/app.yaml': ",
+		},
+		// 5
+		{
+			files: map[string]string{
+				"app.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: echo\nspec:\n  ports: 8080\n",
+			},
+			expErr: "error reading manifest '<dir>/app.yaml': error converting Service 'echo': ",
+		},
+		// 6
+		{
+			paths:  []string{"missing"},
+			expErr: "error reading manifests: lstat <dir>/missing: no such file or directory",
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = gatewayv1.Install(scheme)
+	for i, test := range testCases {
+		dir := t.TempDir()
+		for name, content := range test.files {
+			file := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		paths := []string{dir}
+		if test.paths != nil {
+			paths = nil
+			for _, path := range test.paths {
+				paths = append(paths, filepath.Join(dir, path))
+			}
+		}
+		objs, err := readManifests(scheme, paths)
+		var errStr string
+		if err != nil {
+			errStr = strings.ReplaceAll(err.Error(), dir, "<dir>")
+		}
+		if test.expErr == "" && errStr != "" || !strings.HasPrefix(errStr, test.expErr) {
+			t.Errorf("error differs on %d - expected prefix: '%s' - actual: '%s'", i, test.expErr, errStr)
+		}
+		var actual []string
+		for _, obj := range objs {
+			actual = append(actual, fmt.Sprintf("%T %s/%s", obj, obj.GetNamespace(), obj.GetName()))
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("objects differ on %d\nexpected: %v\n  actual: %v", i, test.expected, actual)
+		}
+	}
+}
+
+func TestConvertManifest(t *testing.T) {
+	testCases := []struct {
+		manifest string
+		expected client.Object
+	}{
+		// 0
+		{
+			manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: echo
+  resourceVersion: "100"
+spec:
+  ports:
+  - port: 8080
+  - port: 8443
+    protocol: UDP
+    targetPort: https
+`,
+			expected: &api.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo"},
+				Spec: api.ServiceSpec{
+					Type: api.ServiceTypeClusterIP,
+					Ports: []api.ServicePort{
+						{Port: 8080, Protocol: api.ProtocolTCP, TargetPort: intstr.FromInt32(8080)},
+						{Port: 8443, Protocol: api.ProtocolUDP, TargetPort: intstr.FromString("https")},
+					},
+				},
+			},
+		},
+		// 1
+		{
+			manifest: `
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: echo
+  namespace: app
+subsets:
+- ports:
+  - port: 8080
+`,
+			expected: &api.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "echo"},
+				Subsets: []api.EndpointSubset{{
+					Ports: []api.EndpointPort{{Port: 8080, Protocol: api.ProtocolTCP}},
+				}},
+			},
+		},
+		// 2
+		{
+			manifest: `
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: echo-abc
+addressType: IPv4
+ports:
+- port: 8080
+`,
+			expected: &discoveryv1.EndpointSlice{
+				ObjectMeta:  metav1.ObjectMeta{Namespace: "default", Name: "echo-abc"},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: ptr.To[int32](8080), Protocol: ptr.To(api.ProtocolTCP)}},
+			},
+		},
+		// 3
+		{
+			manifest: `
+apiVersion: gateway.networking.k8s.io/v1
+kind: GatewayClass
+metadata:
+  name: haproxy
+`,
+			expected: &gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "haproxy"},
+			},
+		},
+		// 4
+		{
+			manifest: `
+apiVersion: gateway.networking.k8s.io/v1alpha2
+kind: TCPRoute
+metadata:
+  name: echo
+`,
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = gatewayv1.Install(scheme)
+	for i, test := range testCases {
+		u := &unstructured.Unstructured{}
+		if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(test.manifest), 4096).Decode(&u.Object); err != nil {
+			t.Fatalf("error parsing manifest %d: %v", i, err)
+		}
+		obj, err := convertManifest(scheme, u)
+		if err != nil {
+			t.Errorf("unexpected error on %d: %v", i, err)
+			continue
+		}
+		if obj != nil {
+			// type meta is tested by the objects read from the manifests
+			reflect.ValueOf(obj).Elem().FieldByName("TypeMeta").SetZero()
+		}
+		if !reflect.DeepEqual(obj, test.expected) {
+			t.Errorf("object differs on %d\nexpected: %+v\n  actual: %+v", i, test.expected, obj)
+		}
+	}
+}
+
+func TestConfigGatewayAPIFromObjects(t *testing.T) {
+	gwclass := func(gv string) client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(gv)
+		obj.SetKind("GatewayClass")
+		return obj
+	}
+	tcproute := func(gv string) client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(gv)
+		obj.SetKind("TCPRoute")
+		return obj
+	}
+	svc := &api.Service{}
+	svc.SetGroupVersionKind(api.SchemeGroupVersion.WithKind("Service"))
+	v1 := gatewayv1.GroupVersion.String()
+	b1 := gatewayv1beta1.GroupVersion.String()
+	a2 := gatewayv1alpha2.GroupVersion.String()
+
+	testCases := []struct {
+		objs     []client.Object
+		expected string
+	}{
+		// 0
+		{
+			objs:     []client.Object{svc},
+			expected: "v1=false b1=false a2=false tcpA2=false",
+		},
+		// 1
+		{
+			objs:     []client.Object{gwclass(v1)},
+			expected: "v1=true b1=false a2=false tcpA2=false",
+		},
+		// 2
+		{
+			objs:     []client.Object{gwclass(v1), gwclass(b1), gwclass(a2)},
+			expected: "v1=true b1=false a2=true tcpA2=false",
+		},
+		// 3
+		{
+			objs:     []client.Object{gwclass(b1), gwclass(a2)},
+			expected: "v1=false b1=true a2=false tcpA2=false",
+		},
+		// 4
+		{
+			objs:     []client.Object{gwclass(a2), tcproute(a2)},
+			expected: "v1=false b1=false a2=true tcpA2=true",
+		},
+		// 5
+		{
+			objs:     []client.Object{tcproute(a2)},
+			expected: "v1=false b1=false a2=false tcpA2=false",
+		},
+		// 6
+		{
+			objs:     []client.Object{gwclass(v1), tcproute(a2)},
+			expected: "v1=true b1=false a2=false tcpA2=true",
+		},
+	}
+	for i, test := range testCases {
+		cfg := &config.Config{}
+		configGatewayAPIFromObjects(cfg, test.objs)
+		actual := fmt.Sprintf("v1=%t b1=%t a2=%t tcpA2=%t", cfg.HasGatewayV1, cfg.HasGatewayB1, cfg.HasGatewayA2, cfg.HasTCPRouteA2)
+		if actual != test.expected {
+			t.Errorf("gateway API config differs on %d - expected: %s - actual: %s", i, test.expected, actual)
+		}
+	}
+}
@@ -18,12 +18,19 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/go-logr/logr"
+	networking "k8s.io/api/networking/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/acme"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
@@ -49,17 +56,28 @@ type Services struct {
 	cache        *c
 	converterOpt *convtypes.ConverterOptions
 	instance     haproxy.Instance
+	instanceOpt  *haproxy.InstanceOptions
 	metrics      *metrics
 	modelMutex   sync.Mutex
 	reloadCount  int
 	reloadQueue  utils.Queue
+	svcbkdhealth *svcBackendHealth
+	svcevents    *svcEvents
 	svcleader    *svcLeader
 	svchealthz   *svcHealthz
+	svcrdngate   *svcReadinessGate
+	svcsrvstate  *svcServersState
 	svcstatus    *svcStatusUpdater
 	svcstatusing *svcStatusIng
+	svcwebhook   *svcWebhook
 	updateCount  int
 }
 
+// Runnables ...
+type Runnables interface {
+	Add(manager.Runnable) error
+}
+
 // SetupWithManager ...
 func (s *Services) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	s.legacylogger = initLogFactory(ctx)
@@ -102,13 +120,42 @@ func (s *Services) setup(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	svchealthz, err := initSvcHealthz(ctx, cfg, metrics, s.acmeExternalCallCheck)
+	svchealthz, err := initSvcHealthz(ctx, cfg, metrics, s.acmeExternalCallCheck, s.cachePurge, s.explainRoute, s.dumpModel, s.fallbackState, s.haproxySynced)
 	if err != nil {
 		return err
 	}
+	var isLeader func() bool
+	if cfg.Election {
+		isLeader = svcleader.isLeader
+	}
+	var converterLogger types.Logger = s.legacylogger.new("converter")
+	var instanceLogger types.Logger = s.legacylogger.new("haproxy")
+	var svcevents *svcEvents
+	var svcbkdhealth *svcBackendHealth
+	var svcrdngate *svcReadinessGate
+	if cfg.ManifestsDir == "" {
+		// events need an API server to be stored
+		svcevents, err = initSvcEvents(ctx, cfg, s.Client, isLeader)
+		if err != nil {
+			return err
+		}
+		converterLogger = svcevents.newLogger(converterLogger)
+		instanceLogger = svcevents.newLogger(instanceLogger)
+		if cfg.StatsCollectBkdPeriod > 0 {
+			svcbkdhealth = initSvcBackendHealth(ctx, cfg, s.Client, svcevents, metrics, s.backendsHealth)
+		}
+		if cfg.PodReadinessGate {
+			svcrdngate = initSvcReadinessGate(ctx, cfg, s.Client, s.targetsUp)
+		}
+	}
+	var svcsrvstate *svcServersState
+	if cfg.StatsCollectSrvPeriod > 0 {
+		svcsrvstate = initSvcServersState(cfg, svcevents, s.checkServersState)
+	}
 	svcstatus := initSvcStatusUpdater(ctx, s.Client)
 	cache := createCacheFacade(ctx, s.Client, cfg, tracker, sslCerts, dynConfig, svcstatus.update)
 	svcstatusing := initSvcStatusIng(ctx, cfg, s.Client, cache, svcstatus.update)
+	svcwebhook := initSvcWebhook(ctx, cfg, s.validateResource)
 	var acmeClient *svcAcmeClient
 	var acmeServer *svcAcmeServer
 	var acmeSigner acme.Signer
@@ -122,19 +169,27 @@ func (s *Services) setup(ctx context.Context) error {
 		acmeLeaderElector = acmeClient
 	}
 	instanceOptions := haproxy.InstanceOptions{
-		RootFSPrefix:      rootFSPrefix,
-		LocalFSPrefix:     cfg.LocalFSPrefix,
-		HAProxyCfgDir:     cfg.LocalFSPrefix + "/etc/haproxy",
-		HAProxyMapsDir:    cfg.DefaultDirMaps,
-		IsMasterWorker:    cfg.MasterWorker,
-		IsExternal:        cfg.MasterSocket != "",
-		MasterSocket:      masterSocket,
-		AdminSocket:       adminSocket,
-		AcmeSocket:        acmeSocket,
-		BackendShards:     cfg.BackendShards,
-		Metrics:           metrics,
-		ReloadQueue:       reloadQueue,
-		ReloadStrategy:    cfg.ReloadStrategy,
+		RootFSPrefix:   rootFSPrefix,
+		LocalFSPrefix:  cfg.LocalFSPrefix,
+		HAProxyCfgDir:  cfg.LocalFSPrefix + "/etc/haproxy",
+		HAProxyMapsDir: cfg.DefaultDirMaps,
+		IsMasterWorker: cfg.MasterWorker,
+		IsExternal:     cfg.MasterSocket != "",
+		MasterSocket:   masterSocket,
+		AdminSocket:    adminSocket,
+		AcmeSocket:     acmeSocket,
+		BackendShards:  cfg.BackendShards,
+		Metrics:        metrics,
+		ReloadQueue:    reloadQueue,
+		ReloadStrategy: cfg.ReloadStrategy,
+		RollbackConfig: cfg.RollbackConfig,
+		RollbackDir:    cfg.DefaultDirRollback,
+		RollbackDirs: []string{
+			cfg.DefaultDirCerts,
+			cfg.DefaultDirCACerts,
+			cfg.DefaultDirCrl,
+			cfg.DefaultDirDHParam,
+		},
 		MaxOldConfigFiles: cfg.MaxOldConfigFiles,
 		SortEndpointsBy:   cfg.SortEndpointsBy,
 		StopCh:            ctx.Done(),
@@ -145,7 +200,7 @@ func (s *Services) setup(ctx context.Context) error {
 		LeaderElector:     acmeLeaderElector,
 	}
 	converterOptions := &convtypes.ConverterOptions{
-		Logger:           s.legacylogger.new("converter"),
+		Logger:           converterLogger,
 		Cache:            cache,
 		Tracker:          tracker,
 		DynamicConfig:    dynConfig,
@@ -155,6 +210,7 @@ func (s *Services) setup(ctx context.Context) error {
 		AdminSocket:      instanceOptions.AdminSocket,
 		AcmeSocket:       instanceOptions.AcmeSocket,
 		AnnotationPrefix: cfg.AnnPrefix,
+		PodName:          cfg.PodName,
 		DefaultBackend:   cfg.DefaultService,
 		DefaultCrtSecret: cfg.DefaultSSLCertificate,
 		FakeCrtFile:      fakeCrt,
@@ -167,27 +223,43 @@ func (s *Services) setup(ctx context.Context) error {
 		HasGatewayV1:     cfg.HasGatewayV1,
 		HasTCPRouteA2:    cfg.HasTCPRouteA2,
 		EnableEPSlices:   cfg.EnableEndpointSliceAPI,
+		PodReadinessGate: cfg.PodReadinessGate,
+		NamespaceLabels:  cfg.WatchNamespaceLabels,
+		HAProxyVersion:   cfg.HAProxyVersion,
 	}
-	instance := haproxy.CreateInstance(s.legacylogger.new("haproxy"), instanceOptions)
+	instance := haproxy.CreateInstance(instanceLogger, instanceOptions)
 	if err := instance.ParseTemplates(); err != nil {
 		return fmt.Errorf("error creating HAProxy instance: %w", err)
 	}
+	if cfg.StartFromLastConfig {
+		if err := instance.StartLastConfig(); errors.Is(err, fs.ErrNotExist) {
+			s.log.Info("last written configuration not found, haproxy will be started after the first sync")
+		} else if err != nil {
+			s.log.Error(err, "cannot start haproxy from the last written configuration, haproxy will be started after the first sync")
+		}
+	}
 	s.acmeClient = acmeClient
 	s.acmeServer = acmeServer
 	s.cache = cache
 	s.converterOpt = converterOptions
 	s.instance = instance
+	s.instanceOpt = &instanceOptions
 	s.metrics = metrics
 	s.modelMutex = sync.Mutex{}
 	s.reloadQueue = reloadQueue
+	s.svcbkdhealth = svcbkdhealth
+	s.svcevents = svcevents
 	s.svcleader = svcleader
 	s.svchealthz = svchealthz
+	s.svcrdngate = svcrdngate
+	s.svcsrvstate = svcsrvstate
 	s.svcstatus = svcstatus
 	s.svcstatusing = svcstatusing
+	s.svcwebhook = svcwebhook
 	return nil
 }
 
-func (s *Services) withManager(mgr ctrl.Manager) error {
+func (s *Services) withManager(mgr Runnables) error {
 	if s.Config.Election {
 		if err := mgr.Add(s.svcleader); err != nil {
 			return err
@@ -205,6 +277,20 @@ func (s *Services) withManager(mgr ctrl.Manager) error {
 				return err
 			}
 		}
+		if s.svcbkdhealth != nil {
+			if err := s.svcleader.addRunnable(s.svcbkdhealth); err != nil {
+				return err
+			}
+		}
+	} else if s.svcbkdhealth != nil {
+		if err := mgr.Add(s.svcbkdhealth); err != nil {
+			return err
+		}
+	}
+	if s.svcevents != nil {
+		if err := mgr.Add(s.svcevents); err != nil {
+			return err
+		}
 	}
 	if s.reloadQueue != nil {
 		if err := mgr.Add(&svcReloadQueue{
@@ -221,6 +307,16 @@ func (s *Services) withManager(mgr ctrl.Manager) error {
 			return err
 		}
 	}
+	if s.svcsrvstate != nil {
+		if err := mgr.Add(s.svcsrvstate); err != nil {
+			return err
+		}
+	}
+	if s.svcrdngate != nil {
+		if err := mgr.Add(s.svcrdngate); err != nil {
+			return err
+		}
+	}
 	if s.acmeServer != nil {
 		if err := mgr.Add(s.acmeServer); err != nil {
 			return err
@@ -231,6 +327,11 @@ func (s *Services) withManager(mgr ctrl.Manager) error {
 			return err
 		}
 	}
+	if s.svcwebhook != nil {
+		if err := mgr.Add(s.svcwebhook); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -259,6 +360,11 @@ func (s *Services) ReconcileIngress(ctx context.Context, changed *convtypes.Chan
 	s.updateCount++
 	s.log.Info("starting haproxy update", "id", s.updateCount)
 	timer := utils.NewTimer(s.metrics.ControllerProcTime)
+	if s.instance.FallbackState() != nil {
+		// haproxy is running a restored configuration, whose files do not
+		// reflect the model anymore, so they need to be fully rebuilt
+		changed.NeedFullSync = true
+	}
 	converters.NewConverter(timer, s.instance.Config(), changed, s.converterOpt).Sync()
 	if s.svcleader.isLeader() {
 		s.instance.AcmeUpdate()
@@ -268,6 +374,86 @@ func (s *Services) ReconcileIngress(ctx context.Context, changed *convtypes.Chan
 	s.log.WithValues("id", s.updateCount).WithValues(timer.AsValues("total")...).Info("finish haproxy update")
 }
 
+// validateResource runs the converters with obj replacing its stored version and
+// returns the warnings that refer to obj, errs should reject obj. Resources of
+// other controllers are always valid.
+func (s *Services) validateResource(obj client.Object) (errs, warnings []string, err error) {
+	switch obj := obj.(type) {
+	case *networking.Ingress:
+		if !s.cache.IsValidIngress(obj) {
+			return nil, nil, nil
+		}
+	case *networking.IngressClass:
+		if !s.cache.IsValidIngressClass(obj) {
+			return nil, nil, nil
+		}
+	}
+	var globalConfig map[string]string
+	if s.Config.ConfigMapName != "" {
+		configMap, err := s.cache.GetConfigMap(s.Config.ConfigMapName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading global ConfigMap: %w", err)
+		}
+		globalConfig = configMap.Data
+	}
+
+	// the validation has its own model, tracker, cache and output directory,
+	// so it does not change, or wait for, the running converters
+	dir, err := os.MkdirTemp("", "haproxy-validate-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+	cfg := *s.Config
+	cfg.DefaultDirCerts = filepath.Join(dir, "certs")
+	cfg.DefaultDirCACerts = filepath.Join(dir, "cacerts")
+	cfg.DefaultDirCrl = filepath.Join(dir, "crl")
+	cfg.DefaultDirDHParam = filepath.Join(dir, "dhparam")
+	subdirs := []string{"certs", "cacerts", "crl", "dhparam"}
+	if s.Config.WebhookDryRun {
+		subdirs = append(subdirs, "maps", "lua", "errorfiles")
+	}
+	for _, subdir := range subdirs {
+		if err := os.Mkdir(filepath.Join(dir, subdir), 0755); err != nil {
+			return nil, nil, err
+		}
+	}
+	instanceOptions := haproxy.InstanceOptions{}
+	if s.Config.WebhookDryRun {
+		instanceOptions = haproxy.InstanceOptions{
+			RootFSPrefix:   s.instanceOpt.RootFSPrefix,
+			HAProxyCfgDir:  dir,
+			HAProxyMapsDir: filepath.Join(dir, "maps"),
+			BackendShards:  s.instanceOpt.BackendShards,
+			IsExternal:     s.instanceOpt.IsExternal,
+		}
+	}
+	instance := haproxy.CreateInstance(s.legacylogger.new("validate"), instanceOptions)
+	if s.Config.WebhookDryRun {
+		if err := instance.ParseTemplates(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// the dynamic config is updated by the running converters
+	s.modelMutex.Lock()
+	dynconfig := *s.converterOpt.DynamicConfig
+	s.modelMutex.Unlock()
+	tracker := tracker.NewTracker()
+	opt := *s.converterOpt
+	opt.Tracker = tracker
+	opt.DynamicConfig = &dynconfig
+	opt.Cache = s.cache.newValidateCache(&cfg, tracker, &dynconfig)
+	errs, warnings, err = converters.Validate(instance.Config(), &opt, globalConfig, obj)
+	if err != nil || len(errs) > 0 || !s.Config.WebhookDryRun {
+		return errs, warnings, err
+	}
+	if err := instance.CheckConfig(); err != nil {
+		errs = append(errs, fmt.Sprintf("configuration check failed: %v", err))
+	}
+	return errs, warnings, nil
+}
+
 func (s *Services) acmeCheck(source string) (count int, err error) {
 	if !s.svcleader.isLeader() {
 		err = fmt.Errorf("cannot check acme certificates, this controller is not the leader")
@@ -287,6 +473,136 @@ func (s *Services) acmeCheck(source string) (count int, err error) {
 	return count, err
 }
 
+func (s *Services) cachePurge(hostname string) error {
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	err := s.instance.CachePurge(hostname)
+	if err != nil {
+		s.log.Error(err, "failed purging cache", "hostname", hostname)
+	}
+	return err
+}
+
+func (s *Services) explainRoute(req *haproxy.RouteRequest) (*haproxy.RouteExplain, error) {
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	return s.instance.ExplainRoute(req)
+}
+
+// haproxySynced does not use modelMutex: it is called by the readiness
+// check, which should not wait for a long running update.
+func (s *Services) haproxySynced() bool {
+	return s.instance.Synced()
+}
+
+func (s *Services) fallbackState() *haproxy.FallbackState {
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	return s.instance.FallbackState()
+}
+
+// backendsHealth reads the stats before locking the model, so updates do
+// not need to wait for the haproxy socket.
+func (s *Services) backendsHealth() ([]*haproxy.BackendHealth, error) {
+	stats, err := s.instance.Stats()
+	if err != nil {
+		return nil, err
+	}
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	return s.instance.BackendsHealth(stats), nil
+}
+
+// checkServersState reads the servers state before locking the model, the
+// same way backendsHealth does.
+func (s *Services) checkServersState() []*haproxy.EjectedServer {
+	serversDown := s.instance.CheckServersState()
+	if len(serversDown) == 0 {
+		return nil
+	}
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	return s.instance.EjectedServers(serversDown)
+}
+
+// targetsUp reads the servers state before locking the model, the same
+// way backendsHealth does.
+func (s *Services) targetsUp() (map[string]bool, error) {
+	states, err := s.instance.ServersState()
+	if err != nil {
+		return nil, err
+	}
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	return s.instance.TargetsUp(states), nil
+}
+
+func (s *Services) dumpModel(section string, filter *haproxy.ModelFilter) ([]byte, error) {
+	s.modelMutex.Lock()
+	defer s.modelMutex.Unlock()
+	if section == "tracker" {
+		return encodeModel(filterTrackingGraph(s.converterOpt.Tracker.ListLinks(), filter))
+	}
+	model, err := s.instance.Config().DumpModel(section, filter)
+	if err != nil {
+		return nil, err
+	}
+	// the dump references the model, so it is encoded before releasing the lock
+	return encodeModel(model)
+}
+
+// filterTrackingGraph keeps the resources matching the filter, as well as
+// the resources directly linked to a hostname or a backend being filtered.
+func filterTrackingGraph(graph convtypes.TrackingGraph, filter *haproxy.ModelFilter) convtypes.TrackingGraph {
+	if filter.Namespace == "" && filter.Hostname == "" && filter.Backend == "" {
+		return graph
+	}
+	match := func(ref convtypes.TrackingRef) bool {
+		if filter.Namespace != "" {
+			prefix := filter.Namespace + "/"
+			if ref.Context == convtypes.ResourceHABackend {
+				prefix = filter.Namespace + "_"
+			}
+			if !strings.HasPrefix(ref.UniqueName, prefix) {
+				return false
+			}
+		}
+		if filter.Hostname != "" && (ref.Context != convtypes.ResourceHAHostname || ref.UniqueName != filter.Hostname) {
+			return false
+		}
+		if filter.Backend != "" && (ref.Context != convtypes.ResourceHABackend || ref.UniqueName != filter.Backend) {
+			return false
+		}
+		return true
+	}
+	output := convtypes.TrackingGraph{}
+	add := func(ctx convtypes.ResourceType, name string, refs []convtypes.TrackingRef) {
+		names := output[ctx]
+		if names == nil {
+			names = map[string][]convtypes.TrackingRef{}
+			output[ctx] = names
+		}
+		names[name] = refs
+	}
+	for ctx, names := range graph {
+		for name, refs := range names {
+			if match(convtypes.TrackingRef{Context: ctx, UniqueName: name}) {
+				add(ctx, name, refs)
+				continue
+			}
+			if filter.Hostname != "" || filter.Backend != "" {
+				for _, ref := range refs {
+					if match(ref) {
+						add(ctx, name, refs)
+						break
+					}
+				}
+			}
+		}
+	}
+	return output
+}
+
 func (s *Services) reloadHAProxy(interface{}) {
 	s.modelMutex.Lock()
 	defer s.modelMutex.Unlock()
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+)
+
+// BackendHealthAnnotation is the annotation, managed by the controller, that
+// summarizes the health of the backends declared by an Ingress or HTTPRoute.
+const BackendHealthAnnotation = "haproxy-ingress.github.io/backend-health"
+
+const eventsReasonBackendHealth = "BackendHealth"
+
+type svcBackendsHealthFnc func() ([]*haproxy.BackendHealth, error)
+
+func initSvcBackendHealth(ctx context.Context, cfg *config.Config, cli client.Client, events *svcEvents, metrics *metrics, health svcBackendsHealthFnc) *svcBackendHealth {
+	return &svcBackendHealth{
+		log:     logr.FromContextOrDiscard(ctx).WithName("backendhealth"),
+		cli:     cli,
+		events:  events,
+		metrics: metrics,
+		health:  health,
+		period:  cfg.StatsCollectBkdPeriod,
+	}
+}
+
+type svcBackendHealth struct {
+	log         logr.Logger
+	cli         client.Client
+	events      *svcEvents
+	metrics     *metrics
+	health      svcBackendsHealthFnc
+	period      time.Duration
+	backends    map[string]*haproxy.BackendHealth
+	annotations map[api.ObjectReference]string
+}
+
+func (s *svcBackendHealth) Start(ctx context.Context) error {
+	// the annotations of a former leader might be outdated, so they are
+	// always patched on the first summary
+	s.backends = map[string]*haproxy.BackendHealth{}
+	s.annotations = map[api.ObjectReference]string{}
+	wait.UntilWithContext(ctx, s.update, s.period)
+	return nil
+}
+
+func (s *svcBackendHealth) update(ctx context.Context) {
+	healthList, err := s.health()
+	if err != nil {
+		s.log.V(1).Info("cannot read backends health", "error", err.Error())
+		return
+	}
+	backends := make(map[string]*haproxy.BackendHealth, len(healthList))
+	items := map[api.ObjectReference][]string{}
+	for _, health := range healthList {
+		var refs []*api.ObjectReference
+		for _, src := range health.Sources {
+			ref := src.ObjectReference()
+			if ref == nil {
+				continue
+			}
+			switch convtypes.ResourceType(ref.Kind) {
+			case convtypes.ResourceIngress, convtypes.ResourceHTTPRoute:
+				// only the state of the servers is published, so the resource
+				// is not patched on every summary due to the 5xx rate
+				items[*ref] = append(items[*ref], health.ID+": "+health.State())
+				refs = append(refs, ref)
+			}
+		}
+		s.notify(health, s.backends[health.ID], refs)
+		s.metrics.SetBackendRsp5xxRatio(health.ID, health.Rsp5xxRatio())
+		backends[health.ID] = health
+	}
+	for id := range s.backends {
+		if _, found := backends[id]; !found {
+			s.metrics.DeleteBackendRsp5xxRatio(id)
+		}
+	}
+	s.backends = backends
+
+	annotations := make(map[api.ObjectReference]string, len(items))
+	for ref, item := range items {
+		sort.Strings(item)
+		value := strings.Join(item, "; ")
+		if s.annotations[ref] != value {
+			if err := s.patch(ctx, ref, &value); err != nil {
+				s.log.Error(err, "cannot update backend health annotation", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+				continue
+			}
+		}
+		annotations[ref] = value
+	}
+	for ref, value := range s.annotations {
+		if _, found := items[ref]; !found {
+			if err := s.patch(ctx, ref, nil); client.IgnoreNotFound(err) != nil {
+				s.log.Error(err, "cannot remove backend health annotation", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+				annotations[ref] = value
+			}
+		}
+	}
+	s.annotations = annotations
+}
+
+// notify records an event on the resources that declare a backend whenever
+// the state of its servers changes. Backends that start healthy are ignored.
+func (s *svcBackendHealth) notify(health, last *haproxy.BackendHealth, refs []*api.ObjectReference) {
+	if last == nil {
+		if health.Down == 0 {
+			return
+		}
+	} else if health.Up == last.Up && health.Down == last.Down && health.Maint == last.Maint {
+		return
+	}
+	eventtype := api.EventTypeNormal
+	if health.Down > 0 {
+		eventtype = api.EventTypeWarning
+	}
+	message := fmt.Sprintf("backend '%s': %s", health.ID, health.String())
+	for _, ref := range refs {
+		s.events.event(ref, eventtype, eventsReasonBackendHealth, message)
+	}
+}
+
+// patch updates the backend health annotation of a resource, a nil value
+// removes the annotation.
+func (s *svcBackendHealth) patch(ctx context.Context, ref api.ObjectReference, value *string) error {
+	obj := s.events.newObject(ref.Kind)
+	if obj == nil {
+		return fmt.Errorf("unsupported kind: %s", ref.Kind)
+	}
+	obj.SetNamespace(ref.Namespace)
+	obj.SetName(ref.Name)
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{
+				BackendHealthAnnotation: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.cli.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+}
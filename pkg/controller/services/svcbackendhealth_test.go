@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+)
+
+func TestBackendHealthUpdate(t *testing.T) {
+	ing1 := &haproxy.BackendSource{Kind: "Ingress", Namespace: "default", Name: "ing1"}
+	ing2 := &haproxy.BackendSource{Kind: "Ingress", Namespace: "default", Name: "ing2"}
+	svc1 := &haproxy.BackendSource{Kind: "Service", Namespace: "default", Name: "svc1"}
+	route1 := &haproxy.BackendSource{Kind: "HTTPRoute", Namespace: "default", Name: "route1"}
+	missing := &haproxy.BackendSource{Kind: "Ingress", Namespace: "default", Name: "missing"}
+	health := func(id string, up, down int, reason string, sources ...*haproxy.BackendSource) *haproxy.BackendHealth {
+		return &haproxy.BackendHealth{ID: id, Up: up, Down: down, DownReason: reason, Sources: sources}
+	}
+
+	// steps run in sequence on the same service, each one starting
+	// from the backends and annotations left by the former one
+	testCases := []struct {
+		health    []*haproxy.BackendHealth
+		err       error
+		expEvents []string
+		expAnns   map[string]string
+	}{
+		// 0
+		{
+			health: []*haproxy.BackendHealth{
+				health("default_b1_8080", 2, 0, "", ing1, svc1),
+				health("default_b2_8080", 1, 1, "L7 timeout", route1),
+			},
+			expEvents: []string{
+				"Warning BackendHealth backend 'default_b2_8080': 1/2 endpoints DOWN (L7 timeout) involvedObject{kind=HTTPRoute,apiVersion=gateway.networking.k8s.io/v1}",
+			},
+			expAnns: map[string]string{
+				"Ingress/ing1":     "default_b1_8080: 2/2 endpoints UP",
+				"HTTPRoute/route1": "default_b2_8080: 1/2 endpoints DOWN",
+			},
+		},
+		// 1
+		{
+			err: fmt.Errorf("haproxy is not running"),
+			expAnns: map[string]string{
+				"Ingress/ing1":     "default_b1_8080: 2/2 endpoints UP",
+				"HTTPRoute/route1": "default_b2_8080: 1/2 endpoints DOWN",
+			},
+		},
+		// 2
+		{
+			health: []*haproxy.BackendHealth{
+				health("default_b1_8080", 1, 1, "", ing1, svc1),
+				health("default_b2_8080", 2, 0, "", route1),
+				health("default_b3_8080", 2, 0, "", ing1),
+			},
+			expEvents: []string{
+				"Warning BackendHealth backend 'default_b1_8080': 1/2 endpoints DOWN involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+				"Normal BackendHealth backend 'default_b2_8080': 2/2 endpoints UP involvedObject{kind=HTTPRoute,apiVersion=gateway.networking.k8s.io/v1}",
+			},
+			expAnns: map[string]string{
+				"Ingress/ing1":     "default_b1_8080: 1/2 endpoints DOWN; default_b3_8080: 2/2 endpoints UP",
+				"HTTPRoute/route1": "default_b2_8080: 2/2 endpoints UP",
+			},
+		},
+		// 3
+		{
+			health: []*haproxy.BackendHealth{
+				health("default_b3_8080", 2, 0, "", ing2),
+			},
+			expAnns: map[string]string{
+				"Ingress/ing2": "default_b3_8080: 2/2 endpoints UP",
+			},
+		},
+		// 4
+		{
+			health: []*haproxy.BackendHealth{
+				health("default_b3_8080", 2, 0, "", ing2),
+				health("default_b4_8080", 0, 2, "L4 timeout", missing),
+			},
+			expAnns: map[string]string{
+				"Ingress/ing2": "default_b3_8080: 2/2 endpoints UP",
+			},
+		},
+	}
+
+	c := setupEvents(t, 0, true)
+	var healthList []*haproxy.BackendHealth
+	var healthErr error
+	svc := &svcBackendHealth{
+		log:     logr.Discard(),
+		cli:     c.events.cli,
+		events:  c.events,
+		metrics: createMetrics(nil),
+		health: func() ([]*haproxy.BackendHealth, error) {
+			return healthList, healthErr
+		},
+		backends:    map[string]*haproxy.BackendHealth{},
+		annotations: map[api.ObjectReference]string{},
+	}
+	refs := []*haproxy.BackendSource{ing1, ing2, svc1, route1}
+	for i, test := range testCases {
+		healthList, healthErr = test.health, test.err
+		svc.update(context.Background())
+		c.compareEvents(i, test.expEvents)
+
+		actual := map[string]string{}
+		for _, src := range refs {
+			obj := c.events.newObject(src.Kind)
+			ref := src.ObjectReference()
+			if err := c.events.cli.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+				t.Errorf("error reading %s on %d: %v", src.Kind, i, err)
+				continue
+			}
+			if ann, found := obj.GetAnnotations()[BackendHealthAnnotation]; found {
+				actual[src.Kind+"/"+src.Name] = ann
+			}
+		}
+		if !reflect.DeepEqual(actual, test.expAnns) {
+			t.Errorf("annotations differ on %d\nexpected: %v\n  actual: %v", i, test.expAnns, actual)
+		}
+		// annotations that failed to be patched are retried on the next update
+		if len(svc.annotations) != len(test.expAnns) {
+			t.Errorf("tracked annotations differ on %d - expected: %d - actual: %d", i, len(test.expAnns), len(svc.annotations))
+		}
+	}
+
+	// a change only in the 5xx rate does not patch the resource
+	readVersion := func() string {
+		ing := &networking.Ingress{}
+		if err := c.events.cli.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "ing2"}, ing); err != nil {
+			t.Fatalf("error reading ingress: %v", err)
+		}
+		return ing.ResourceVersion
+	}
+	version := readVersion()
+	healthList = []*haproxy.BackendHealth{
+		{ID: "default_b3_8080", Up: 2, Requests: 100, Rsp5xx: 25, Sources: []*haproxy.BackendSource{ing2}},
+	}
+	svc.update(context.Background())
+	c.compareEvents(len(testCases), nil)
+	if v := readVersion(); v != version {
+		t.Errorf("expected ingress not patched, resourceVersion changed from %s to %s", version, v)
+	}
+	if ratio := testutil.ToFloat64(svc.metrics.backendRsp5xxGauge.WithLabelValues("default_b3_8080")); ratio != 0.25 {
+		t.Errorf("expected 5xx ratio 0.25, found %v", ratio)
+	}
+	if count := testutil.CollectAndCount(svc.metrics.backendRsp5xxGauge); count != 1 {
+		t.Errorf("expected the 5xx ratio of 1 backend, found %d", count)
+	}
+}
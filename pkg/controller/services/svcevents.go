@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+const (
+	// the same warning on the same object is sent once in this period,
+	// so warnings are deduplicated across syncs
+	eventsDedupPeriod = 30 * time.Minute
+	eventsQPS         = 2
+	eventsBurst       = 50
+	eventsReason      = "ConfigurationWarning"
+)
+
+func initSvcEvents(ctx context.Context, cfg *config.Config, cli client.Client, isLeader func() bool) (*svcEvents, error) {
+	r, err := initRecorderProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	recorder, broadcaster := r.GetClusterEventRecorderFor("events")
+	s := &svcEvents{
+		ctx:         ctx,
+		log:         logr.FromContextOrDiscard(ctx).WithName("events"),
+		cfg:         cfg,
+		cli:         cli,
+		recorder:    recorder,
+		broadcaster: broadcaster,
+		limiter:     flowcontrol.NewTokenBucketRateLimiter(eventsQPS, eventsBurst),
+		isLeader:    isLeader,
+		sent:        map[string]time.Time{},
+	}
+	s.queue = utils.NewQueue(s.record)
+	return s, nil
+}
+
+type svcEvents struct {
+	ctx         context.Context
+	log         logr.Logger
+	cfg         *config.Config
+	cli         client.Client
+	recorder    record.EventRecorder
+	broadcaster record.EventBroadcaster
+	limiter     flowcontrol.RateLimiter
+	isLeader    func() bool
+	queue       utils.Queue
+	mu          sync.Mutex
+	sent        map[string]time.Time
+}
+
+// eventItem is an event waiting to be recorded, its fields are comparable
+// so it can be used as a queue item.
+type eventItem struct {
+	ref       api.ObjectReference
+	eventtype string
+	reason    string
+	message   string
+}
+
+func (s *svcEvents) Start(ctx context.Context) error {
+	s.queue.RunWithContext(ctx)
+	s.broadcaster.Shutdown()
+	return nil
+}
+
+// newLogger wraps a converter's logger, warnings that refer to a Kubernetes
+// object are also recorded as Warning events on that object.
+func (s *svcEvents) newLogger(logger types.Logger) types.Logger {
+	return &eventLogger{
+		Logger: logger,
+		events: s,
+	}
+}
+
+func (s *svcEvents) warn(ref *api.ObjectReference, message string) {
+	s.event(ref, api.EventTypeWarning, eventsReason, message)
+}
+
+// event records an event on the object ref refers to. The same event on the
+// same object is sent once in the dedup period. Events are queued and
+// recorded asynchronously, so the caller is never blocked by the API server.
+func (s *svcEvents) event(ref *api.ObjectReference, eventtype, reason, message string) {
+	if s.isLeader != nil && !s.isLeader() {
+		return
+	}
+	key := fmt.Sprintf("%s/%s/%s:%s:%s", ref.Kind, ref.Namespace, ref.Name, reason, message)
+	now := time.Now()
+	s.mu.Lock()
+	if sent, found := s.sent[key]; found && now.Sub(sent) < eventsDedupPeriod {
+		s.mu.Unlock()
+		return
+	}
+	if !s.limiter.TryAccept() {
+		s.mu.Unlock()
+		s.log.V(1).Info("rate limit exceeded, event discarded", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+		return
+	}
+	s.sent[key] = now
+	s.cleanup(now)
+	s.mu.Unlock()
+
+	s.queue.Add(eventItem{
+		ref:       *ref,
+		eventtype: eventtype,
+		reason:    reason,
+		message:   message,
+	})
+}
+
+// record sends a queued event to the API server, the uid and the api version
+// of the object are read from the informer cache if missing in the reference.
+func (s *svcEvents) record(item interface{}) {
+	event := item.(eventItem)
+	ref := &event.ref
+	if ref.UID == "" || ref.APIVersion == "" {
+		obj, err := s.getObject(ref)
+		if err != nil {
+			s.log.V(1).Info("cannot read object, event discarded", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name, "error", err.Error())
+			return
+		}
+		// kubectl describe filters events by the object uid
+		ref.UID = obj.GetUID()
+		if ref.APIVersion == "" {
+			if gvk, err := apiutil.GVKForObject(obj, s.cli.Scheme()); err == nil {
+				ref.APIVersion = gvk.GroupVersion().String()
+			}
+		}
+	}
+	s.recorder.Event(ref, event.eventtype, event.reason, event.message)
+}
+
+func (s *svcEvents) getObject(ref *api.ObjectReference) (client.Object, error) {
+	obj := s.newObject(ref.Kind)
+	if obj == nil {
+		return nil, fmt.Errorf("unsupported kind: %s", ref.Kind)
+	}
+	if err := s.cli.Get(s.ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *svcEvents) newObject(kind string) client.Object {
+	switch convtypes.ResourceType(kind) {
+	case convtypes.ResourceIngress:
+		return &networking.Ingress{}
+	case convtypes.ResourceService:
+		return &api.Service{}
+	case convtypes.ResourceHTTPRoute:
+		// the same route is served by all the watched API versions
+		switch {
+		case s.cfg.HasGatewayV1:
+			return &gatewayv1.HTTPRoute{}
+		case s.cfg.HasGatewayB1:
+			return &gatewayv1beta1.HTTPRoute{}
+		case s.cfg.HasGatewayA2:
+			return &gatewayv1alpha2.HTTPRoute{}
+		}
+	}
+	return nil
+}
+
+// cleanup removes expired entries, should be called with the lock held
+func (s *svcEvents) cleanup(now time.Time) {
+	for key, sent := range s.sent {
+		if now.Sub(sent) >= eventsDedupPeriod {
+			delete(s.sent, key)
+		}
+	}
+}
+
+type eventLogger struct {
+	types.Logger
+	events *svcEvents
+}
+
+func (l *eventLogger) Warn(msg string, args ...interface{}) {
+	l.Logger.Warn(msg, args...)
+	var message string
+	for _, arg := range args {
+		if referrer, ok := arg.(convtypes.ObjectReferrer); ok {
+			if ref := referrer.ObjectReference(); ref != nil {
+				if message == "" {
+					message = fmt.Sprintf(msg, args...)
+				}
+				l.events.warn(ref, message)
+			}
+		}
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/types/helper_test"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+type objRef struct {
+	kind, namespace, name string
+}
+
+func (r *objRef) ObjectReference() *api.ObjectReference {
+	if r.kind == "" {
+		return nil
+	}
+	return &api.ObjectReference{
+		Kind:      r.kind,
+		Namespace: r.namespace,
+		Name:      r.name,
+	}
+}
+
+func (r *objRef) String() string {
+	return r.kind + " '" + r.namespace + "/" + r.name + "'"
+}
+
+func TestEventLoggerWarn(t *testing.T) {
+	ing1 := &objRef{kind: "Ingress", namespace: "default", name: "ing1"}
+	ing2 := &objRef{kind: "Ingress", namespace: "default", name: "ing2"}
+	svc1 := &objRef{kind: "Service", namespace: "default", name: "svc1"}
+	route1 := &objRef{kind: "HTTPRoute", namespace: "default", name: "route1"}
+	missing := &objRef{kind: "Ingress", namespace: "default", name: "missing"}
+	noref := &objRef{}
+
+	type warn struct {
+		msg  string
+		args []interface{}
+	}
+	testCases := []struct {
+		burst     int
+		notLeader bool
+		warns     []warn
+		expEvents []string
+		expLogs   string
+	}{
+		// 0
+		{
+			warns: []warn{
+				{msg: "invalid value on %s", args: []interface{}{ing1}},
+			},
+			expEvents: []string{
+				"Warning ConfigurationWarning invalid value on Ingress 'default/ing1' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+			},
+			expLogs: `
+WARN invalid value on Ingress 'default/ing1'`,
+		},
+		// 1
+		{
+			warns: []warn{
+				{msg: "conflict between %s and %s", args: []interface{}{ing1, svc1}},
+			},
+			expEvents: []string{
+				"Warning ConfigurationWarning conflict between Ingress 'default/ing1' and Service 'default/svc1' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+				"Warning ConfigurationWarning conflict between Ingress 'default/ing1' and Service 'default/svc1' involvedObject{kind=Service,apiVersion=v1}",
+			},
+			expLogs: `
+WARN conflict between Ingress 'default/ing1' and Service 'default/svc1'`,
+		},
+		// 2
+		{
+			warns: []warn{
+				{msg: "invalid value on %s", args: []interface{}{route1}},
+			},
+			expEvents: []string{
+				"Warning ConfigurationWarning invalid value on HTTPRoute 'default/route1' involvedObject{kind=HTTPRoute,apiVersion=gateway.networking.k8s.io/v1}",
+			},
+			expLogs: `
+WARN invalid value on HTTPRoute 'default/route1'`,
+		},
+		// 3
+		{
+			warns: []warn{
+				{msg: "invalid value on %s", args: []interface{}{noref}},
+				{msg: "invalid value on %s", args: []interface{}{"Ingress 'default/ing1'"}},
+				{msg: "invalid value on %s", args: []interface{}{missing}},
+			},
+			expLogs: `
+WARN invalid value on  '/'
+WARN invalid value on Ingress 'default/ing1'
+WARN invalid value on Ingress 'default/missing'`,
+		},
+		// 4
+		{
+			warns: []warn{
+				{msg: "invalid value on %s", args: []interface{}{ing1}},
+				{msg: "invalid value on %s", args: []interface{}{ing1}},
+				{msg: "invalid value on %s", args: []interface{}{ing2}},
+				{msg: "other warning on %s", args: []interface{}{ing1}},
+			},
+			expEvents: []string{
+				"Warning ConfigurationWarning invalid value on Ingress 'default/ing1' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+				"Warning ConfigurationWarning invalid value on Ingress 'default/ing2' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+				"Warning ConfigurationWarning other warning on Ingress 'default/ing1' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+			},
+			expLogs: `
+WARN invalid value on Ingress 'default/ing1'
+WARN invalid value on Ingress 'default/ing1'
+WARN invalid value on Ingress 'default/ing2'
+WARN other warning on Ingress 'default/ing1'`,
+		},
+		// 5
+		{
+			burst: 2,
+			warns: []warn{
+				{msg: "warning 1 on %s", args: []interface{}{ing1}},
+				{msg: "warning 2 on %s", args: []interface{}{ing1}},
+				{msg: "warning 3 on %s", args: []interface{}{ing1}},
+			},
+			expEvents: []string{
+				"Warning ConfigurationWarning warning 1 on Ingress 'default/ing1' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+				"Warning ConfigurationWarning warning 2 on Ingress 'default/ing1' involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+			},
+			expLogs: `
+WARN warning 1 on Ingress 'default/ing1'
+WARN warning 2 on Ingress 'default/ing1'
+WARN warning 3 on Ingress 'default/ing1'`,
+		},
+		// 6
+		{
+			notLeader: true,
+			warns: []warn{
+				{msg: "invalid value on %s", args: []interface{}{ing1}},
+			},
+			expLogs: `
+WARN invalid value on Ingress 'default/ing1'`,
+		},
+	}
+	for i, test := range testCases {
+		c := setupEvents(t, test.burst, !test.notLeader)
+		logger := c.events.newLogger(c.logger)
+		for _, w := range test.warns {
+			logger.Warn(w.msg, w.args...)
+		}
+		c.compareEvents(i, test.expEvents)
+		c.logger.CompareLoggingID(strconv.Itoa(i), test.expLogs)
+	}
+}
+
+func TestEventsDedup(t *testing.T) {
+	c := setupEvents(t, 0, true)
+	ref := func() *api.ObjectReference {
+		return &api.ObjectReference{Kind: "Ingress", Namespace: "default", Name: "ing1"}
+	}
+
+	c.events.warn(ref(), "warning 1")
+	c.events.warn(ref(), "warning 1")
+	c.events.event(ref(), api.EventTypeNormal, "OtherReason", "warning 1")
+	c.compareEvents(0, []string{
+		"Warning ConfigurationWarning warning 1 involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+		"Normal OtherReason warning 1 involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+	})
+
+	// expire the dedup period of all the sent events
+	for key := range c.events.sent {
+		c.events.sent[key] = time.Now().Add(-eventsDedupPeriod)
+	}
+	c.events.warn(ref(), "warning 1")
+	c.compareEvents(1, []string{
+		"Warning ConfigurationWarning warning 1 involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+	})
+	if l := len(c.events.sent); l != 1 {
+		t.Errorf("expected 1 sent event after cleanup, found %d", l)
+	}
+}
+
+func TestEventsLeader(t *testing.T) {
+	c := setupEvents(t, 0, false)
+	ref := func() *api.ObjectReference {
+		return &api.ObjectReference{Kind: "Ingress", Namespace: "default", Name: "ing1"}
+	}
+
+	c.events.warn(ref(), "warning 1")
+	c.compareEvents(0, nil)
+
+	// events skipped by a follower are sent when it becomes the leader
+	c.leader = true
+	c.events.warn(ref(), "warning 1")
+	c.compareEvents(1, []string{
+		"Warning ConfigurationWarning warning 1 involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+	})
+}
+
+func TestEventsUID(t *testing.T) {
+	c := setupEvents(t, 0, true)
+	var actual runtime.Object
+	c.events.recorder = &refRecorder{EventRecorder: c.recorder, obj: &actual}
+	ref := &api.ObjectReference{Kind: "Ingress", Namespace: "default", Name: "ing1"}
+	c.events.warn(ref, "warning 1")
+	expected := &api.ObjectReference{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Namespace:  "default",
+		Name:       "ing1",
+		UID:        "uid-default-ing1",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("object reference differs\nexpected: %+v\n  actual: %+v", expected, actual)
+	}
+	if ref.UID != "" {
+		t.Errorf("expected the caller's object reference unchanged, found uid %s", ref.UID)
+	}
+	c.compareEvents(0, []string{
+		"Warning ConfigurationWarning warning 1 involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+	})
+}
+
+func TestEventsQueue(t *testing.T) {
+	c := setupEvents(t, 0, true)
+	var queued []interface{}
+	c.events.queue = &syncQueue{sync: func(item interface{}) {
+		queued = append(queued, item)
+	}}
+	c.events.warn(&api.ObjectReference{Kind: "Ingress", Namespace: "default", Name: "ing1"}, "warning 1")
+
+	// nothing is recorded, or read from the API, before the queue runs
+	c.compareEvents(0, nil)
+	if l := len(queued); l != 1 {
+		t.Fatalf("expected 1 queued event, found %d", l)
+	}
+	c.events.record(queued[0])
+	c.compareEvents(1, []string{
+		"Warning ConfigurationWarning warning 1 involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}",
+	})
+}
+
+// syncQueue processes the added items synchronously
+type syncQueue struct {
+	utils.Queue
+	sync func(item interface{})
+}
+
+func (q *syncQueue) Add(item interface{}) {
+	q.sync(item)
+}
+
+// refRecorder stores the object of the last recorded event
+type refRecorder struct {
+	record.EventRecorder
+	obj *runtime.Object
+}
+
+func (r *refRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	*r.obj = object
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+type eventsConfig struct {
+	t        *testing.T
+	events   *svcEvents
+	logger   *helper_test.LoggerMock
+	recorder *record.FakeRecorder
+	leader   bool
+}
+
+func setupEvents(t *testing.T, burst int, leader bool) *eventsConfig {
+	if burst == 0 {
+		burst = eventsBurst
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = gatewayv1.Install(scheme)
+	objMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID("uid-default-" + name)}
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&networking.Ingress{ObjectMeta: objMeta("ing1")},
+		&networking.Ingress{ObjectMeta: objMeta("ing2")},
+		&api.Service{ObjectMeta: objMeta("svc1")},
+		&gatewayv1.HTTPRoute{ObjectMeta: objMeta("route1")},
+	).Build()
+	recorder := record.NewFakeRecorder(100)
+	recorder.IncludeObject = true
+	c := &eventsConfig{
+		t:        t,
+		logger:   helper_test.NewLoggerMock(t),
+		recorder: recorder,
+		leader:   leader,
+	}
+	c.events = &svcEvents{
+		ctx:      context.Background(),
+		cfg:      &config.Config{HasGatewayV1: true},
+		cli:      cli,
+		recorder: recorder,
+		limiter:  flowcontrol.NewTokenBucketRateLimiter(0.001, burst),
+		isLeader: func() bool { return c.leader },
+		sent:     map[string]time.Time{},
+	}
+	c.events.queue = &syncQueue{sync: c.events.record}
+	return c
+}
+
+func (c *eventsConfig) compareEvents(id int, expected []string) {
+	var actual []string
+	for len(c.recorder.Events) > 0 {
+		actual = append(actual, <-c.recorder.Events)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		c.t.Errorf("events differ on %d\nexpected: %v\n  actual: %v", id, expected, actual)
+	}
+}
@@ -1,11 +1,14 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,9 +19,20 @@ import (
 	"k8s.io/apiserver/pkg/server/healthz"
 
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
 )
 
-func initSvcHealthz(ctx context.Context, cfg *config.Config, metrics *metrics, acmeCheck svcAcmeCheckFnc) (*svcHealthz, error) {
+type svcCachePurgeFnc func(hostname string) error
+
+type svcExplainRouteFnc func(req *haproxy.RouteRequest) (*haproxy.RouteExplain, error)
+
+type svcDumpModelFnc func(section string, filter *haproxy.ModelFilter) ([]byte, error)
+
+type svcFallbackStateFnc func() *haproxy.FallbackState
+
+type svcHAProxySyncedFnc func() bool
+
+func initSvcHealthz(ctx context.Context, cfg *config.Config, metrics *metrics, acmeCheck svcAcmeCheckFnc, cachePurge svcCachePurgeFnc, explainRoute svcExplainRouteFnc, dumpModel svcDumpModelFnc, fallbackState svcFallbackStateFnc, haproxySynced svcHAProxySyncedFnc) (*svcHealthz, error) {
 	if cfg.HealthzAddr == "" {
 		return nil, nil
 	}
@@ -28,10 +42,28 @@ func initSvcHealthz(ctx context.Context, cfg *config.Config, metrics *metrics, a
 	}
 	mux := http.NewServeMux()
 	healthz.InstallPathHandler(mux, cfg.HealthzURL)
-	healthz.InstallPathHandler(mux, cfg.ReadyzURL)
+	var readyzChecks []healthz.HealthChecker
+	if cfg.StartFromLastConfig {
+		// haproxy is running a configuration that might be outdated
+		// until the first sync is applied
+		readyzChecks = append(readyzChecks, healthz.NamedCheck("haproxy-synced", func(*http.Request) error {
+			if !haproxySynced() {
+				return fmt.Errorf("haproxy is not running a synchronized configuration yet")
+			}
+			return nil
+		}))
+	}
+	healthz.InstallPathHandler(mux, cfg.ReadyzURL, readyzChecks...)
 	mux.Handle("/", s.createRootHealthzHandler())
 	mux.Handle("/acme/check", s.createAcmeHandler(acmeCheck))
 	mux.Handle("/build", s.createBuildHandler(cfg))
+	mux.Handle("/cache/purge", s.createCachePurgeHandler(cachePurge))
+	mux.Handle("/config/state", s.createConfigStateHandler(fallbackState))
+	if cfg.DebugModelHandler {
+		mux.Handle("/debug/model/", s.createDumpModelHandler(dumpModel, ""))
+		mux.Handle("/debug/tracker", s.createDumpModelHandler(dumpModel, "tracker"))
+	}
+	mux.Handle("/debug/route", s.createExplainRouteHandler(explainRoute))
 	if cfg.Profiling {
 		mux.HandleFunc("/debug/pprof/", pprof.Index)
 		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
@@ -60,7 +92,10 @@ type svcHealthz struct {
 }
 
 func (s *svcHealthz) createRootHealthzHandler() http.HandlerFunc {
-	var pprofDisabled, stopDisabled string
+	var modelDisabled, pprofDisabled, stopDisabled string
+	if !s.cfg.DebugModelHandler {
+		modelDisabled = " (DISABLED)"
+	}
 	if !s.cfg.Profiling {
 		pprofDisabled = " (DISABLED)"
 	}
@@ -72,7 +107,12 @@ func (s *svcHealthz) createRootHealthzHandler() http.HandlerFunc {
 	contentType := "text/plain"
 	page := `/acme/check (only POST): starts a new check for certificates that need to be issued
 /build : build info
+/cache/purge?host=<hostname> (only POST): purges the response cache of a hostname
+/config/state : 200 if haproxy runs the current configuration, 503 if it runs the last known good one
+/debug/model/{global,hosts,backends,tcpservices,userlists}[?namespace=<ns>&host=<hostname>&backend=<id>] : converted model in JSON format` + modelDisabled + `
 /debug/pprof/ : pprof index` + pprofDisabled + `
+/debug/route?host=<hostname>&path=<path>[&header=<name>:<value>...][&https=true] : explains how a request is routed
+/debug/tracker[?namespace=<ns>&host=<hostname>&backend=<id>] : links between resources and the model in JSON format` + modelDisabled + `
 /metrics : HAProxy Ingress metrics in Prometheus format
 /stop : stops the controller process` + stopDisabled + `
 `
@@ -111,6 +151,124 @@ func (s *svcHealthz) createAcmeHandler(acmeCheck func() (int, error)) http.Handl
 	}
 }
 
+func (s *svcHealthz) createCachePurgeHandler(cachePurge svcCachePurgeFnc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			handle404(w)
+			return
+		}
+		var out string
+		hostname := r.URL.Query().Get("host")
+		err := cachePurge(hostname)
+		w.Header().Set("Content-Type", "text/plain")
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			out = fmt.Sprintf("Error purging the cache: %s.\nSee further information in the controller log.\n", err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			out = fmt.Sprintf("Cache of '%s' successfully purged.\n", hostname)
+		}
+		_, _ = w.Write([]byte(out))
+	}
+}
+
+func (s *svcHealthz) createConfigStateHandler(fallbackState svcFallbackStateFnc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			handle404(w)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		state := fallbackState()
+		if state == nil {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("ok\n"))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(fmt.Sprintf("Running on fallback config since %s, the last configuration was rejected:\n%s\n",
+			state.Since.Format(time.RFC3339), state.Reason)))
+	}
+}
+
+func (s *svcHealthz) createExplainRouteHandler(explainRoute svcExplainRouteFnc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			handle404(w)
+			return
+		}
+		query := r.URL.Query()
+		https, _ := strconv.ParseBool(query.Get("https"))
+		req := &haproxy.RouteRequest{
+			Host:    query.Get("host"),
+			Path:    query.Get("path"),
+			Headers: http.Header{},
+			HTTPS:   https,
+		}
+		for _, header := range query["header"] {
+			name, value, found := strings.Cut(header, ":")
+			if !found || name == "" {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(fmt.Sprintf("Invalid header, expected <name>:<value>: '%s'.\n", header)))
+				return
+			}
+			req.Headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		explain, err := explainRoute(req)
+		if err != nil {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(fmt.Sprintf("Error explaining the route: %s.\n", err)))
+			return
+		}
+		out, _ := json.MarshalIndent(explain, "", "  ")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(append(out, '\n'))
+	}
+}
+
+func (s *svcHealthz) createDumpModelHandler(dumpModel svcDumpModelFnc, section string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			handle404(w)
+			return
+		}
+		modelSection := section
+		if modelSection == "" {
+			modelSection = strings.TrimPrefix(r.URL.Path, "/debug/model/")
+		}
+		query := r.URL.Query()
+		filter := &haproxy.ModelFilter{
+			Namespace: query.Get("namespace"),
+			Hostname:  query.Get("host"),
+			Backend:   query.Get("backend"),
+		}
+		out, err := dumpModel(modelSection, filter)
+		if err != nil {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(fmt.Sprintf("Error reading the model: %s.\n", err)))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	}
+}
+
+// encodeModel encodes a model dump. The model has pointers to the
+// live model, so it should be called while the model is locked.
+func encodeModel(model interface{}) ([]byte, error) {
+	out := &bytes.Buffer{}
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(model); err != nil {
+		return nil, fmt.Errorf("error encoding the model: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
 func (s *svcHealthz) createBuildHandler(cfg *config.Config) http.HandlerFunc {
 	build, _ := json.Marshal(cfg.VersionInfo)
 	return func(w http.ResponseWriter, r *http.Request) {
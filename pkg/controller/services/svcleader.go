@@ -43,6 +43,16 @@ const (
 type SvcLeaderChangedFnc func(ctx context.Context, isLeader bool)
 
 func initSvcLeader(ctx context.Context, cfg *config.Config) (*svcLeader, error) {
+	s := &svcLeader{
+		ctx: ctx,
+		log: logr.FromContextOrDiscard(ctx).WithName("leader"),
+	}
+
+	if !cfg.Election {
+		// also the case of a standalone controller, which has no API server to elect a leader
+		return s, nil
+	}
+
 	r, err := initRecorderProvider(cfg)
 	if err != nil {
 		return nil, err
@@ -58,11 +68,6 @@ func initSvcLeader(ctx context.Context, cfg *config.Config) (*svcLeader, error)
 		return nil, err
 	}
 
-	s := &svcLeader{
-		ctx: ctx,
-		log: logr.FromContextOrDiscard(ctx).WithName("leader"),
-	}
-
 	if rl != nil {
 		s.le, err = leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
 			Name:          cfg.ElectionID,
@@ -64,3 +64,15 @@ func (r *recorderProvider) GetEventRecorderFor(name string) record.EventRecorder
 		Host:      r.hostname,
 	})
 }
+
+// GetClusterEventRecorderFor returns a recorder that can record events of
+// objects on any namespace, and its broadcaster, which should be shut down
+// when the recorder is not used anymore.
+func (r *recorderProvider) GetClusterEventRecorderFor(name string) (record.EventRecorder, record.EventBroadcaster) {
+	broadcaster := record.NewBroadcaster()
+	_ = broadcaster.StartRecordingToSink(&corev1client.EventSinkImpl{Interface: r.cli.Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{
+		Component: r.electionID + "_" + name,
+		Host:      r.hostname,
+	}), broadcaster
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+// SetupStandalone configures the services of a controller that reads its
+// resources from a directory of manifests instead of the API server. Resources
+// are stored in an in-memory client used by the same cache facade of a controller
+// watching a cluster, and changes in the directory are sent to ReconcileIngress.
+func (s *Services) SetupStandalone(ctx context.Context, runnables Runnables) error {
+	s.legacylogger = initLogFactory(ctx)
+	s.log = logr.FromContextOrDiscard(ctx).WithName("services")
+	ctx = logr.NewContext(ctx, s.log)
+	svcmanifests, err := initSvcManifests(ctx, s.Config, s.ReconcileIngress)
+	if err != nil {
+		return err
+	}
+	s.Client = svcmanifests.cli
+	if err := s.setup(ctx); err != nil {
+		return err
+	}
+	svcmanifests.val = s.cache
+	if err := s.withManager(runnables); err != nil {
+		return err
+	}
+	return runnables.Add(svcmanifests)
+}
+
+type svcReconcileFnc func(ctx context.Context, changed *convtypes.ChangedObjects)
+
+func initSvcManifests(ctx context.Context, cfg *config.Config, reconcile svcReconcileFnc) (*svcManifests, error) {
+	objs, err := readManifests(cfg.Scheme, []string{cfg.ManifestsDir})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.WatchGateway {
+		configGatewayAPIFromObjects(cfg, objs)
+	}
+	cli, err := newMemClient(cfg.Scheme, objs)
+	if err != nil {
+		return nil, err
+	}
+	s := &svcManifests{
+		log:       logr.FromContextOrDiscard(ctx).WithName("manifests"),
+		cfg:       cfg,
+		cli:       cli,
+		objs:      manifestsByKey(objs),
+		reconcile: reconcile,
+	}
+	s.queue = utils.NewRateLimitingQueue(float32(cfg.RateLimitUpdate), s.sync)
+	s.log.Info("resources read from manifests", "count", len(s.objs))
+	return s, nil
+}
+
+type svcManifests struct {
+	ctx       context.Context
+	log       logr.Logger
+	cfg       *config.Config
+	cli       *memClient
+	val       IsValidResource
+	objs      map[string]client.Object
+	global    map[string]string
+	tcp       map[string]string
+	queue     utils.Queue
+	reconcile svcReconcileFnc
+}
+
+func (s *svcManifests) Start(ctx context.Context) error {
+	s.ctx = ctx
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := s.addWatches(watcher); err != nil {
+		return err
+	}
+
+	s.reconcile(ctx, s.initialChanges())
+	go s.queue.RunWithContext(ctx)
+
+	var wait <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Has(fsnotify.Create) {
+				// new subdirectories should also be watched
+				if err := s.addWatches(watcher); err != nil {
+					s.log.Error(err, "error watching manifests directory")
+				}
+			}
+			if wait == nil {
+				wait = time.After(s.cfg.WaitBeforeUpdate)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			s.log.Error(err, "error watching manifests directory")
+		case <-wait:
+			wait = nil
+			s.queue.Notify()
+		}
+	}
+}
+
+// addWatches watches the manifests directory and all its subdirectories,
+// since fsnotify does not watch directories recursively.
+func (s *svcManifests) addWatches(watcher *fsnotify.Watcher) error {
+	return filepath.WalkDir(s.cfg.ManifestsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return watcher.Add(path)
+	})
+}
+
+// initialChanges returns the changes of the very first reconciliation,
+// all the resources are read and the configmaps are assigned as new ones.
+func (s *svcManifests) initialChanges() *convtypes.ChangedObjects {
+	changed := &convtypes.ChangedObjects{
+		NeedFullSync: true,
+		Links:        convtypes.TrackingLinks{},
+	}
+	for _, obj := range s.objs {
+		if cm, ok := obj.(*api.ConfigMap); ok {
+			switch cm.Namespace + "/" + cm.Name {
+			case s.cfg.ConfigMapName:
+				changed.GlobalConfigMapDataNew = cm.Data
+			case s.cfg.TCPConfigMapName:
+				changed.TCPConfigMapDataNew = cm.Data
+			}
+		}
+	}
+	s.global = changed.GlobalConfigMapDataNew
+	s.tcp = changed.TCPConfigMapDataNew
+	return changed
+}
+
+func (s *svcManifests) sync(item interface{}) {
+	objs, err := readManifests(s.cfg.Scheme, []string{s.cfg.ManifestsDir})
+	if err != nil {
+		s.log.Error(err, "error reading manifests, keeping the current configuration")
+		return
+	}
+	changed := s.applyChanges(manifestsByKey(objs))
+	if len(changed.Objects) == 0 {
+		s.log.V(1).Info("no resource changed in the manifests directory")
+		return
+	}
+	s.reconcile(s.ctx, changed)
+}
+
+// applyChanges updates the client with the resources read from the manifests,
+// and returns what changed since the last update, in the same way the
+// resource watchers of a controller connected to the API server would do.
+func (s *svcManifests) applyChanges(objs map[string]client.Object) *convtypes.ChangedObjects {
+	changed := &convtypes.ChangedObjects{
+		GlobalConfigMapDataCur: s.global,
+		TCPConfigMapDataCur:    s.tcp,
+		Links:                  convtypes.TrackingLinks{},
+	}
+	for _, key := range sortedKeys(objs) {
+		obj := objs[key]
+		old, found := s.objs[key]
+		if found && reflect.DeepEqual(old, obj) {
+			continue
+		}
+		if err := s.cli.set(obj); err != nil {
+			s.log.Error(err, "error updating resource", "resource", key)
+			continue
+		}
+		if found {
+			s.notify(changed, "update", old, obj)
+		} else {
+			s.notify(changed, "add", nil, obj)
+		}
+		s.objs[key] = obj
+	}
+	for _, key := range sortedKeys(s.objs) {
+		if _, found := objs[key]; found {
+			continue
+		}
+		old := s.objs[key]
+		if err := s.cli.remove(old); err != nil {
+			s.log.Error(err, "error removing resource", "resource", key)
+			continue
+		}
+		s.notify(changed, "del", old, nil)
+		delete(s.objs, key)
+	}
+	if changed.GlobalConfigMapDataNew != nil {
+		s.global = changed.GlobalConfigMapDataNew
+	}
+	if changed.TCPConfigMapDataNew != nil {
+		s.tcp = changed.TCPConfigMapDataNew
+	}
+	return changed
+}
+
+// notify adds a changed resource to changed, either old or new can be nil on
+// resources being added or removed.
+func (s *svcManifests) notify(changed *convtypes.ChangedObjects, ev string, old, new client.Object) {
+	obj := new
+	if obj == nil {
+		obj = old
+	}
+	name := obj.GetName()
+	var res convtypes.ResourceType
+	switch o := obj.(type) {
+	case *api.ConfigMap:
+		res = convtypes.ResourceConfigMap
+		data := o.Data
+		if new == nil {
+			// there is no configmap anymore, the default configuration should be used
+			data = map[string]string{}
+		}
+		switch o.Namespace + "/" + o.Name {
+		case s.cfg.ConfigMapName:
+			changed.GlobalConfigMapDataNew = data
+		case s.cfg.TCPConfigMapName:
+			changed.TCPConfigMapDataNew = data
+		}
+	case *api.Service:
+		res = convtypes.ResourceService
+	case *api.Endpoints:
+		res = convtypes.ResourceEndpoints
+	case *discoveryv1.EndpointSlice:
+		res = convtypes.ResourceEndpoints
+		if svcName := o.Labels["kubernetes.io/service-name"]; svcName != "" {
+			name = svcName
+		}
+	case *api.Secret:
+		res = convtypes.ResourceSecret
+	case *api.Pod:
+		res = convtypes.ResourcePod
+	case *networking.Ingress:
+		res = convtypes.ResourceIngress
+		if !s.notifyIngress(changed, old, new) {
+			return
+		}
+	case *networking.IngressClass:
+		res = convtypes.ResourceIngressClass
+	case *gatewayv1alpha2.Gateway, *gatewayv1beta1.Gateway, *gatewayv1.Gateway:
+		res = convtypes.ResourceGateway
+		changed.NeedFullSync = true
+	case *gatewayv1alpha2.GatewayClass, *gatewayv1beta1.GatewayClass, *gatewayv1.GatewayClass:
+		res = convtypes.ResourceGatewayClass
+		changed.NeedFullSync = true
+	case *gatewayv1alpha2.HTTPRoute, *gatewayv1beta1.HTTPRoute, *gatewayv1.HTTPRoute:
+		res = convtypes.ResourceHTTPRoute
+		changed.NeedFullSync = true
+	case *gatewayv1alpha2.TCPRoute:
+		res = convtypes.ResourceTCPRoute
+		changed.NeedFullSync = true
+	default:
+		return
+	}
+	if ns := obj.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	changed.Links[res] = appendDedup(changed.Links[res], name)
+	changed.Objects = appendDedup(changed.Objects, fmt.Sprintf("%s/%s:%s", ev, res, name))
+	s.log.V(1).Info("notify", "event", ev, "kind", reflect.TypeOf(obj), "namespace", obj.GetNamespace(), "name", obj.GetName())
+}
+
+// notifyIngress adds an ingress resource to the changed lists, taking into
+// account if the old and the new versions belong to this controller.
+func (s *svcManifests) notifyIngress(changed *convtypes.ChangedObjects, old, new client.Object) bool {
+	var oldIng, newIng *networking.Ingress
+	var oldValid, newValid bool
+	if old != nil {
+		oldIng = old.(*networking.Ingress)
+		oldValid = s.val.IsValidIngress(oldIng)
+	}
+	if new != nil {
+		newIng = new.(*networking.Ingress)
+		newValid = s.val.IsValidIngress(newIng)
+	}
+	if oldValid && newValid {
+		changed.IngressesUpd = append(changed.IngressesUpd, newIng)
+	} else if !oldValid && newValid {
+		changed.IngressesAdd = append(changed.IngressesAdd, newIng)
+	} else if oldValid && !newValid {
+		changed.IngressesDel = append(changed.IngressesDel, oldIng)
+	}
+	return oldValid || newValid
+}
+
+// manifestsByKey indexes resources by their group, version, kind, namespace
+// and name. A resource declared more than once has its last declaration used.
+func manifestsByKey(objs []client.Object) map[string]client.Object {
+	byKey := make(map[string]client.Object, len(objs))
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		key := fmt.Sprintf("%s/%s:%s/%s", gvk.GroupVersion(), gvk.Kind, obj.GetNamespace(), obj.GetName())
+		byKey[key] = obj
+	}
+	return byKey
+}
+
+func sortedKeys(objs map[string]client.Object) []string {
+	keys := make([]string, 0, len(objs))
+	for key := range objs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendDedup(slice []string, s string) []string {
+	for _, item := range slice {
+		if item == s {
+			return slice
+		}
+	}
+	return append(slice, s)
+}
@@ -0,0 +1,420 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+)
+
+func TestManifestsNotify(t *testing.T) {
+	svc1 := manifestSvc("default", "svc1", 8080)
+	cm := func(name string) *api.ConfigMap {
+		return &api.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ingress", Name: name},
+			Data:       map[string]string{"key": name},
+		}
+	}
+	eps := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "svc1-abcde",
+			Labels:    map[string]string{"kubernetes.io/service-name": "svc1"},
+		},
+	}
+	testCases := []struct {
+		ev       string
+		old, new client.Object
+		expected changedSummary
+	}{
+		// 0
+		{
+			ev:  "add",
+			new: svc1,
+			expected: changedSummary{
+				Objects: []string{"add/Service:default/svc1"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceService: {"default/svc1"}},
+			},
+		},
+		// 1
+		{
+			ev:  "del",
+			old: svc1,
+			expected: changedSummary{
+				Objects: []string{"del/Service:default/svc1"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceService: {"default/svc1"}},
+			},
+		},
+		// 2
+		{
+			ev:  "update",
+			new: eps,
+			old: eps,
+			expected: changedSummary{
+				Objects: []string{"update/Endpoints:default/svc1"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceEndpoints: {"default/svc1"}},
+			},
+		},
+		// 3
+		{
+			ev:  "add",
+			new: cm("global"),
+			expected: changedSummary{
+				Objects: []string{"add/ConfigMap:ingress/global"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceConfigMap: {"ingress/global"}},
+				Global:  map[string]string{"key": "global"},
+			},
+		},
+		// 4
+		{
+			ev:  "del",
+			old: cm("tcp"),
+			expected: changedSummary{
+				Objects: []string{"del/ConfigMap:ingress/tcp"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceConfigMap: {"ingress/tcp"}},
+				TCP:     map[string]string{},
+			},
+		},
+		// 5
+		{
+			ev:  "add",
+			new: cm("other"),
+			expected: changedSummary{
+				Objects: []string{"add/ConfigMap:ingress/other"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceConfigMap: {"ingress/other"}},
+			},
+		},
+		// 6
+		{
+			ev:  "add",
+			new: &networking.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "haproxy"}},
+			expected: changedSummary{
+				Objects: []string{"add/IngressClass:haproxy"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceIngressClass: {"haproxy"}},
+			},
+		},
+		// 7
+		{
+			ev:  "add",
+			new: manifestIng("default", "ing1", "haproxy"),
+			expected: changedSummary{
+				Objects: []string{"add/Ingress:default/ing1"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceIngress: {"default/ing1"}},
+				IngAdd:  []string{"default/ing1"},
+			},
+		},
+		// 8
+		{
+			ev:       "add",
+			new:      manifestIng("default", "ing1", "other"),
+			expected: changedSummary{},
+		},
+		// 9
+		{
+			ev:  "update",
+			old: &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route1"}},
+			new: &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route1"}},
+			expected: changedSummary{
+				Objects:  []string{"update/HTTPRoute:default/route1"},
+				Links:    convtypes.TrackingLinks{convtypes.ResourceHTTPRoute: {"default/route1"}},
+				FullSync: true,
+			},
+		},
+		// 10
+		{
+			ev:       "add",
+			new:      &api.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			expected: changedSummary{},
+		},
+	}
+	for i, test := range testCases {
+		s := setupManifests(t)
+		changed := &convtypes.ChangedObjects{Links: convtypes.TrackingLinks{}}
+		s.notify(changed, test.ev, test.old, test.new)
+		compareChanged(t, i, changed, test.expected)
+	}
+}
+
+func TestManifestsNotifyIngress(t *testing.T) {
+	valid := manifestIng("default", "ing1", "haproxy")
+	invalid := manifestIng("default", "ing1", "other")
+	testCases := []struct {
+		old, new *networking.Ingress
+		expected changedSummary
+		notify   bool
+	}{
+		// 0
+		{
+			new:      valid,
+			expected: changedSummary{IngAdd: []string{"default/ing1"}},
+			notify:   true,
+		},
+		// 1
+		{
+			old:      valid,
+			new:      valid,
+			expected: changedSummary{IngUpd: []string{"default/ing1"}},
+			notify:   true,
+		},
+		// 2
+		{
+			old:      valid,
+			expected: changedSummary{IngDel: []string{"default/ing1"}},
+			notify:   true,
+		},
+		// 3
+		{
+			old:      invalid,
+			new:      valid,
+			expected: changedSummary{IngAdd: []string{"default/ing1"}},
+			notify:   true,
+		},
+		// 4
+		{
+			old:      valid,
+			new:      invalid,
+			expected: changedSummary{IngDel: []string{"default/ing1"}},
+			notify:   true,
+		},
+		// 5
+		{
+			old: invalid,
+			new: invalid,
+		},
+		// 6
+		{
+			new: invalid,
+		},
+	}
+	for i, test := range testCases {
+		s := setupManifests(t)
+		changed := &convtypes.ChangedObjects{}
+		// typed nil pointers would be seen as a non nil client.Object
+		var old, new client.Object
+		if test.old != nil {
+			old = test.old
+		}
+		if test.new != nil {
+			new = test.new
+		}
+		notify := s.notifyIngress(changed, old, new)
+		if notify != test.notify {
+			t.Errorf("notify differs on %d - expected: %t - actual: %t", i, test.notify, notify)
+		}
+		compareChanged(t, i, changed, test.expected)
+	}
+}
+
+func TestManifestsApplyChanges(t *testing.T) {
+	cm := &api.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ingress", Name: "global"},
+		Data:       map[string]string{"ssl-redirect": "false"},
+	}
+	svc1 := manifestSvc("default", "svc1", 8080)
+	ing1 := manifestIng("default", "ing1", "haproxy")
+	ing2 := manifestIng("default", "ing2", "other")
+
+	testCases := []struct {
+		objs      []client.Object
+		expected  changedSummary
+		expGlobal map[string]string
+		expSvc1   int32
+	}{
+		// 0
+		{
+			objs:      []client.Object{cm, svc1, ing1},
+			expGlobal: cm.Data,
+			expSvc1:   8080,
+		},
+		// 1
+		{
+			objs: []client.Object{cm, manifestSvc("default", "svc1", 8000), ing1, ing2},
+			expected: changedSummary{
+				Objects: []string{"update/Service:default/svc1"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceService: {"default/svc1"}},
+			},
+			expGlobal: cm.Data,
+			expSvc1:   8000,
+		},
+		// 2
+		{
+			objs: []client.Object{manifestSvc("default", "svc1", 8000), manifestSvc("default", "svc2", 8080)},
+			expected: changedSummary{
+				Objects: []string{"add/Service:default/svc2", "del/Ingress:default/ing1", "del/ConfigMap:ingress/global"},
+				Links: convtypes.TrackingLinks{
+					convtypes.ResourceConfigMap: {"ingress/global"},
+					convtypes.ResourceIngress:   {"default/ing1"},
+					convtypes.ResourceService:   {"default/svc2"},
+				},
+				IngDel: []string{"default/ing1"},
+				Global: map[string]string{},
+			},
+			expGlobal: cm.Data,
+			expSvc1:   8000,
+		},
+		// 3
+		{
+			objs: []client.Object{
+				manifestSvc("default", "svc1", 8000),
+				manifestSvc("default", "svc2", 8080),
+				manifestIng("default", "ing2", "haproxy"),
+			},
+			expected: changedSummary{
+				Objects: []string{"add/Ingress:default/ing2"},
+				Links:   convtypes.TrackingLinks{convtypes.ResourceIngress: {"default/ing2"}},
+				IngAdd:  []string{"default/ing2"},
+			},
+			expGlobal: map[string]string{},
+			expSvc1:   8000,
+		},
+		// 4
+		{
+			objs: []client.Object{},
+			expected: changedSummary{
+				Objects: []string{"del/Ingress:default/ing2", "del/Service:default/svc1", "del/Service:default/svc2"},
+				Links: convtypes.TrackingLinks{
+					convtypes.ResourceIngress: {"default/ing2"},
+					convtypes.ResourceService: {"default/svc1", "default/svc2"},
+				},
+				IngDel: []string{"default/ing2"},
+			},
+			expGlobal: map[string]string{},
+		},
+	}
+
+	s := setupManifests(t, cm, svc1, ing1)
+	s.global = cm.Data
+	for i, test := range testCases {
+		changed := s.applyChanges(manifestsByKey(test.objs))
+		if !reflect.DeepEqual(changed.GlobalConfigMapDataCur, test.expGlobal) {
+			t.Errorf("current global config differs on %d - expected: %v - actual: %v", i, test.expGlobal, changed.GlobalConfigMapDataCur)
+		}
+		compareChanged(t, i, changed, test.expected)
+		svc := &api.Service{}
+		err := s.cli.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "svc1"}, svc)
+		var port int32
+		if err == nil {
+			port = svc.Spec.Ports[0].Port
+		}
+		if port != test.expSvc1 {
+			t.Errorf("svc1 port differs on %d - expected: %d - actual: %d", i, test.expSvc1, port)
+		}
+		if len(s.objs) != len(test.objs) {
+			t.Errorf("tracked objects differ on %d - expected: %d - actual: %d", i, len(test.objs), len(s.objs))
+		}
+	}
+}
+
+type validIngressClass struct {
+	IsValidResource
+}
+
+func (validIngressClass) IsValidIngress(ing *networking.Ingress) bool {
+	return ing.Spec.IngressClassName != nil && *ing.Spec.IngressClassName == "haproxy"
+}
+
+type changedSummary struct {
+	Objects                []string
+	Links                  convtypes.TrackingLinks
+	IngAdd, IngUpd, IngDel []string
+	Global, TCP            map[string]string
+	FullSync               bool
+}
+
+func compareChanged(t *testing.T, i int, changed *convtypes.ChangedObjects, expected changedSummary) {
+	names := func(ings []*networking.Ingress) []string {
+		var out []string
+		for _, ing := range ings {
+			out = append(out, ing.Namespace+"/"+ing.Name)
+		}
+		return out
+	}
+	actual := changedSummary{
+		Objects:  changed.Objects,
+		Links:    changed.Links,
+		IngAdd:   names(changed.IngressesAdd),
+		IngUpd:   names(changed.IngressesUpd),
+		IngDel:   names(changed.IngressesDel),
+		Global:   changed.GlobalConfigMapDataNew,
+		TCP:      changed.TCPConfigMapDataNew,
+		FullSync: changed.NeedFullSync,
+	}
+	if len(actual.Links) == 0 {
+		actual.Links = nil
+	}
+	if len(expected.Links) == 0 {
+		expected.Links = nil
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("changed objects differ on %d\nexpected: %+v\n  actual: %+v", i, expected, actual)
+	}
+}
+
+func manifestSvc(namespace, name string, port int32) *api.Service {
+	return &api.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       api.ServiceSpec{Ports: []api.ServicePort{{Port: port}}},
+	}
+}
+
+func manifestIng(namespace, name, class string) *networking.Ingress {
+	return &networking.Ingress{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       networking.IngressSpec{IngressClassName: &class},
+	}
+}
+
+func setupManifests(t *testing.T, objs ...client.Object) *svcManifests {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = gatewayv1.Install(scheme)
+	cli, err := newMemClient(scheme, objs)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	return &svcManifests{
+		ctx: context.Background(),
+		log: logr.Discard(),
+		cfg: &config.Config{
+			ConfigMapName:    "ingress/global",
+			TCPConfigMapName: "ingress/tcp",
+			Scheme:           scheme,
+		},
+		cli:  cli,
+		val:  validIngressClass{},
+		objs: manifestsByKey(objs),
+	}
+}
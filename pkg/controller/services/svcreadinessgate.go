@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	convutils "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/utils"
+)
+
+// readinessGateAnnotationPrefix prefixes the annotations that each controller
+// replica adds to a pod, named after the replica and valued with its UID, once
+// its haproxy reports the pod as an UP server.
+const readinessGateAnnotationPrefix = "server-up.haproxy-ingress.github.io/"
+
+const readinessGatePeriod = 2 * time.Second
+
+type svcTargetsUpFnc func() (map[string]bool, error)
+
+func initSvcReadinessGate(ctx context.Context, cfg *config.Config, cli client.Client, targetsUp svcTargetsUpFnc) *svcReadinessGate {
+	return &svcReadinessGate{
+		log:       logr.FromContextOrDiscard(ctx).WithName("readinessgate"),
+		cfg:       cfg,
+		cli:       cli,
+		targetsUp: targetsUp,
+		period:    readinessGatePeriod,
+	}
+}
+
+type svcReadinessGate struct {
+	log       logr.Logger
+	cfg       *config.Config
+	cli       client.Client
+	targetsUp svcTargetsUpFnc
+	period    time.Duration
+}
+
+func (s *svcReadinessGate) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, s.update, s.period)
+	return nil
+}
+
+func (s *svcReadinessGate) update(ctx context.Context) {
+	targets, err := s.targetsUp()
+	if err != nil {
+		s.log.V(1).Info("cannot read servers state", "error", err.Error())
+		return
+	}
+	var replicas map[string]controllerReplica
+	for target, up := range targets {
+		if !up {
+			continue
+		}
+		namespace, name, _ := strings.Cut(target, "/")
+		pod := api.Pod{}
+		if err := s.cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pod); err != nil {
+			continue
+		}
+		if !convutils.ReadinessGatePending(&pod) {
+			continue
+		}
+		if replicas == nil {
+			if replicas, err = s.readReplicas(ctx); err != nil {
+				s.log.Error(err, "cannot list controller pods")
+				return
+			}
+		}
+		if err := s.patchAnnotations(ctx, &pod, s.syncAnnotations(&pod, replicas)); err != nil {
+			s.log.Error(err, "cannot annotate pod", "namespace", namespace, "name", name)
+			continue
+		}
+		var ready, missing int
+		for replica, r := range replicas {
+			if !r.ready {
+				continue
+			}
+			ready++
+			if pod.Annotations[readinessGateAnnotationPrefix+replica] != string(r.uid) {
+				missing++
+			}
+		}
+		if missing > 0 {
+			s.log.V(1).Info("pod is UP on some controller replicas", "namespace", namespace, "name", name, "missing", missing)
+			continue
+		}
+		message := fmt.Sprintf("server is UP on %d haproxy-ingress replica(s)", ready)
+		if err := s.patchCondition(ctx, &pod, message); err != nil {
+			s.log.Error(err, "cannot update pod readiness gate", "namespace", namespace, "name", name)
+			continue
+		}
+		s.log.Info("pod readiness gate updated", "namespace", namespace, "name", name)
+		// the annotations are only used while the gate is pending
+		if err := s.patchAnnotations(ctx, &pod, s.removeAnnotations(&pod)); err != nil {
+			s.log.Error(err, "cannot remove pod annotations", "namespace", namespace, "name", name)
+		}
+	}
+}
+
+// controllerReplica is a running controller pod. Its UID is the value of
+// the annotation it adds to the pods, so the annotation of a former pod
+// with the same name, e.g. from a StatefulSet, is not taken into account.
+type controllerReplica struct {
+	uid   types.UID
+	ready bool
+}
+
+// readReplicas returns the running controller replicas, indexed by the pod
+// name. The ready ones, including the current one, should agree on a server
+// UP before updating the gate.
+func (s *svcReadinessGate) readReplicas(ctx context.Context) (map[string]controllerReplica, error) {
+	podList, err := listControllerPods(ctx, s.cli, s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	replicas := make(map[string]controllerReplica, len(podList))
+	for i := range podList {
+		pod := &podList[i]
+		if pod.Name == s.cfg.PodName {
+			replicas[pod.Name] = controllerReplica{uid: pod.UID, ready: true}
+		} else if pod.DeletionTimestamp == nil {
+			replicas[pod.Name] = controllerReplica{uid: pod.UID, ready: isPodReady(pod)}
+		}
+	}
+	if _, found := replicas[s.cfg.PodName]; !found {
+		return nil, fmt.Errorf("controller pod '%s' not found", s.cfg.PodName)
+	}
+	return replicas, nil
+}
+
+// syncAnnotations returns the annotation changes of a pod waiting for the
+// gate: the annotation of the current replica is added, and the ones of
+// replicas that are not running anymore are removed.
+func (s *svcReadinessGate) syncAnnotations(pod *api.Pod, replicas map[string]controllerReplica) map[string]*string {
+	changes := map[string]*string{}
+	for key, value := range pod.Annotations {
+		replica, found := strings.CutPrefix(key, readinessGateAnnotationPrefix)
+		if !found {
+			continue
+		}
+		if r, running := replicas[replica]; !running || value != string(r.uid) {
+			changes[key] = nil
+		}
+	}
+	uid := string(replicas[s.cfg.PodName].uid)
+	if annotation := readinessGateAnnotationPrefix + s.cfg.PodName; pod.Annotations[annotation] != uid {
+		changes[annotation] = &uid
+	}
+	return changes
+}
+
+// removeAnnotations returns the removal of all the replica annotations of a pod.
+func (s *svcReadinessGate) removeAnnotations(pod *api.Pod) map[string]*string {
+	changes := map[string]*string{}
+	for key := range pod.Annotations {
+		if strings.HasPrefix(key, readinessGateAnnotationPrefix) {
+			changes[key] = nil
+		}
+	}
+	return changes
+}
+
+func isPodReady(pod *api.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == api.PodReady {
+			return cond.Status == api.ConditionTrue
+		}
+	}
+	return false
+}
+
+// patchAnnotations applies annotation changes to a pod, a nil value removes
+// the annotation. It is a no-op if there is nothing to change.
+func (s *svcReadinessGate) patchAnnotations(ctx context.Context, pod *api.Pod, annotations map[string]*string) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.cli.Patch(ctx, pod, client.RawPatch(types.MergePatchType, data))
+}
+
+func (s *svcReadinessGate) patchCondition(ctx context.Context, pod *api.Pod, message string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []api.PodCondition{{
+				Type:               convutils.ReadinessGateServerUp,
+				Status:             api.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "ServerUp",
+				Message:            message,
+			}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	// strategic merge patch merges conditions by type, preserving the other ones
+	return s.cli.Status().Patch(ctx, pod, client.RawPatch(types.StrategicMergePatchType, data))
+}
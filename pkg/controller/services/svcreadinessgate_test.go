@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	convutils "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/utils"
+)
+
+func TestReadinessGateUpdate(t *testing.T) {
+	ctrlPod := func(name string, ready bool) *api.Pod {
+		status := api.ConditionFalse
+		if ready {
+			status = api.ConditionTrue
+		}
+		return &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ingress",
+				Name:      name,
+				UID:       types.UID("uid-" + name),
+				Labels:    map[string]string{"app": "haproxy-ingress"},
+			},
+			Status: api.PodStatus{Conditions: []api.PodCondition{{Type: api.PodReady, Status: status}}},
+		}
+	}
+	appPod := func(name string, gate bool, annotations map[string]string) *api.Pod {
+		pod := &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+			Status:     api.PodStatus{Conditions: []api.PodCondition{{Type: api.ContainersReady, Status: api.ConditionTrue}}},
+		}
+		if gate {
+			pod.Spec.ReadinessGates = []api.PodReadinessGate{{ConditionType: convutils.ReadinessGateServerUp}}
+		}
+		return pod
+	}
+	annotate := func(pod, replica, uid string) func(c *readinessGateConfig) {
+		return func(c *readinessGateConfig) {
+			obj := &api.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: pod}}
+			patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s%s":"%s"}}}`, readinessGateAnnotationPrefix, replica, uid)
+			if err := c.cli.Patch(context.Background(), obj, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+				t.Fatalf("error annotating %s: %v", pod, err)
+			}
+		}
+	}
+	remove := func(name string) func(c *readinessGateConfig) {
+		return func(c *readinessGateConfig) {
+			if err := c.cli.Delete(context.Background(), ctrlPod(name, true)); err != nil {
+				t.Fatalf("error removing %s: %v", name, err)
+			}
+		}
+	}
+
+	// steps run in sequence on the same pods, each one
+	// starting from the pods changed by the former one
+	allUp := map[string]bool{"default/app1": true, "default/app2": true, "default/app3": true, "default/app4": false}
+	testCases := []struct {
+		do       func(c *readinessGateConfig)
+		targets  map[string]bool
+		err      error
+		expected map[string]string
+	}{
+		// 0
+		{
+			targets: allUp,
+			expected: map[string]string{
+				"app1": "ann=[ctrl-0=uid-ctrl-0] cond=",
+				"app2": "ann=[ctrl-0=uid-ctrl-0] cond=",
+				"app3": "ann=[] cond=",
+				"app4": "ann=[] cond=",
+			},
+		},
+		// 1
+		{
+			do:      annotate("app1", "ctrl-1", "uid-ctrl-1"),
+			targets: allUp,
+			expected: map[string]string{
+				"app1": "ann=[] cond=True/server is UP on 2 haproxy-ingress replica(s)",
+				"app2": "ann=[ctrl-0=uid-ctrl-0] cond=",
+				"app3": "ann=[] cond=",
+				"app4": "ann=[] cond=",
+			},
+		},
+		// 2
+		{
+			do:      remove("ctrl-1"),
+			err:     fmt.Errorf("haproxy is not running"),
+			targets: allUp,
+			expected: map[string]string{
+				"app1": "ann=[] cond=True/server is UP on 2 haproxy-ingress replica(s)",
+				"app2": "ann=[ctrl-0=uid-ctrl-0] cond=",
+				"app3": "ann=[] cond=",
+				"app4": "ann=[] cond=",
+			},
+		},
+		// 3
+		{
+			targets: allUp,
+			expected: map[string]string{
+				"app1": "ann=[] cond=True/server is UP on 2 haproxy-ingress replica(s)",
+				"app2": "ann=[] cond=True/server is UP on 1 haproxy-ingress replica(s)",
+				"app3": "ann=[] cond=",
+				"app4": "ann=[] cond=",
+			},
+		},
+	}
+
+	c := setupReadinessGate(t,
+		ctrlPod("ctrl-0", true),
+		ctrlPod("ctrl-1", true),
+		ctrlPod("ctrl-2", false),
+		appPod("app1", true, nil),
+		// stale annotations: ctrl-1 was replaced by a pod with the same name, ctrl-9 is gone
+		appPod("app2", true, map[string]string{
+			readinessGateAnnotationPrefix + "ctrl-1": "uid-old",
+			readinessGateAnnotationPrefix + "ctrl-9": "uid-ctrl-9",
+			"other":                                  "value",
+		}),
+		appPod("app3", false, nil),
+		appPod("app4", true, nil),
+	)
+	for i, test := range testCases {
+		if test.do != nil {
+			test.do(c)
+		}
+		c.targets, c.err = test.targets, test.err
+		c.gate.update(context.Background())
+		c.comparePods(i, test.expected)
+	}
+}
+
+func TestReadinessGateMissingController(t *testing.T) {
+	pod := &api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app1"},
+		Spec:       api.PodSpec{ReadinessGates: []api.PodReadinessGate{{ConditionType: convutils.ReadinessGateServerUp}}},
+		Status:     api.PodStatus{Conditions: []api.PodCondition{{Type: api.ContainersReady, Status: api.ConditionTrue}}},
+	}
+	c := setupReadinessGate(t, pod)
+	if _, err := c.gate.readReplicas(context.Background()); err == nil {
+		t.Errorf("expected error reading replicas without the controller pod")
+	}
+	// the pod should not be annotated if the replicas cannot be read
+	c.targets = map[string]bool{"default/app1": true}
+	c.gate.update(context.Background())
+	c.comparePods(0, map[string]string{"app1": "ann=[] cond="})
+}
+
+type readinessGateConfig struct {
+	t       *testing.T
+	cli     client.Client
+	gate    *svcReadinessGate
+	targets map[string]bool
+	err     error
+}
+
+func setupReadinessGate(t *testing.T, objs ...client.Object) *readinessGateConfig {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&api.Pod{}).Build()
+	c := &readinessGateConfig{
+		t:   t,
+		cli: cli,
+	}
+	c.gate = &svcReadinessGate{
+		log: logr.Discard(),
+		cfg: &config.Config{PodName: "ctrl-0", PodNamespace: "ingress"},
+		cli: cli,
+		targetsUp: func() (map[string]bool, error) {
+			return c.targets, c.err
+		},
+	}
+	return c
+}
+
+func (c *readinessGateConfig) comparePods(id int, expected map[string]string) {
+	podList := api.PodList{}
+	if err := c.cli.List(context.Background(), &podList, client.InNamespace("default")); err != nil {
+		c.t.Fatalf("error listing pods on %d: %v", id, err)
+	}
+	actual := map[string]string{}
+	for _, pod := range podList.Items {
+		var anns []string
+		for key, value := range pod.Annotations {
+			if replica, found := strings.CutPrefix(key, readinessGateAnnotationPrefix); found {
+				anns = append(anns, replica+"="+value)
+			}
+		}
+		sort.Strings(anns)
+		var cond string
+		for _, c := range pod.Status.Conditions {
+			if c.Type == convutils.ReadinessGateServerUp {
+				cond = string(c.Status) + "/" + c.Message
+			}
+		}
+		actual[pod.Name] = fmt.Sprintf("ann=%v cond=%s", anns, cond)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		c.t.Errorf("pods differ on %d\nexpected: %v\n  actual: %v", id, expected, actual)
+	}
+}
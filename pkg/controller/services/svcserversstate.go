@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+)
+
+const eventsReasonServerEjected = "ServerEjected"
+
+type svcServersStateFnc func() []*haproxy.EjectedServer
+
+func initSvcServersState(cfg *config.Config, events *svcEvents, check svcServersStateFnc) *svcServersState {
+	return &svcServersState{
+		events: events,
+		check:  check,
+		period: cfg.StatsCollectSrvPeriod,
+	}
+}
+
+type svcServersState struct {
+	events *svcEvents
+	check  svcServersStateFnc
+	period time.Duration
+}
+
+func (s *svcServersState) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, s.update, s.period)
+	return nil
+}
+
+// update checks the state of the servers, and records a Warning event on the
+// Service of the backends with passive health checks whose servers went down.
+func (s *svcServersState) update(ctx context.Context) {
+	ejected := s.check()
+	if s.events == nil {
+		return
+	}
+	for _, server := range ejected {
+		ref := server.Service.ObjectReference()
+		message := fmt.Sprintf("server '%s' (%s) of backend '%s' is down", server.Server, server.Endpoint, server.Backend)
+		s.events.event(ref, api.EventTypeWarning, eventsReasonServerEjected, message)
+	}
+}
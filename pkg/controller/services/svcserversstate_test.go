@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+)
+
+func TestServersStateUpdate(t *testing.T) {
+	svc1 := &haproxy.BackendSource{Kind: "Service", Namespace: "default", Name: "svc1"}
+	missing := &haproxy.BackendSource{Kind: "Service", Namespace: "default", Name: "missing"}
+	ejected := func(server, endpoint string, svc *haproxy.BackendSource) *haproxy.EjectedServer {
+		return &haproxy.EjectedServer{Backend: "default_svc1_8080", Server: server, Endpoint: endpoint, Service: svc}
+	}
+
+	testCases := []struct {
+		ejected   []*haproxy.EjectedServer
+		notLeader bool
+		expEvents []string
+	}{
+		// 0
+		{},
+		// 1
+		{
+			ejected: []*haproxy.EjectedServer{
+				ejected("srv001", "172.17.0.11:8080", svc1),
+				ejected("srv002", "172.17.0.12:8080", svc1),
+			},
+			expEvents: []string{
+				"Warning ServerEjected server 'srv001' (172.17.0.11:8080) of backend 'default_svc1_8080' is down involvedObject{kind=Service,apiVersion=v1}",
+				"Warning ServerEjected server 'srv002' (172.17.0.12:8080) of backend 'default_svc1_8080' is down involvedObject{kind=Service,apiVersion=v1}",
+			},
+		},
+		// 2
+		{
+			ejected: []*haproxy.EjectedServer{
+				ejected("srv001", "172.17.0.11:8080", missing),
+			},
+		},
+		// 3
+		{
+			ejected: []*haproxy.EjectedServer{
+				ejected("srv003", "172.17.0.13:8080", svc1),
+			},
+			notLeader: true,
+		},
+	}
+
+	for i, test := range testCases {
+		c := setupEvents(t, 0, !test.notLeader)
+		svc := &svcServersState{
+			events: c.events,
+			check: func() []*haproxy.EjectedServer {
+				return test.ejected
+			},
+		}
+		svc.update(context.Background())
+		c.compareEvents(i, test.expEvents)
+	}
+}
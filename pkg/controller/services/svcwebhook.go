@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	networking "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
+)
+
+// WebhookValidatePath is the path of the validating admission webhook
+const WebhookValidatePath = "/validate"
+
+type svcValidateFnc func(obj client.Object) (errs, warnings []string, err error)
+
+func initSvcWebhook(ctx context.Context, cfg *config.Config, validate svcValidateFnc) *svcWebhook {
+	if cfg.WebhookPort == 0 {
+		return nil
+	}
+	s := &svcWebhook{
+		log:      logr.FromContextOrDiscard(ctx).WithName("webhook"),
+		port:     cfg.WebhookPort,
+		decoder:  admission.NewDecoder(cfg.Scheme),
+		validate: validate,
+	}
+	s.server = webhook.NewServer(webhook.Options{
+		Port:    cfg.WebhookPort,
+		CertDir: cfg.WebhookCertDir,
+	})
+	s.server.Register(WebhookValidatePath, &webhook.Admission{Handler: s})
+	return s
+}
+
+type svcWebhook struct {
+	log      logr.Logger
+	port     int
+	decoder  admission.Decoder
+	server   webhook.Server
+	validate svcValidateFnc
+}
+
+func (s *svcWebhook) Start(ctx context.Context) error {
+	s.log.Info("starting validating admission webhook", "port", s.port)
+	return s.server.Start(ctx)
+}
+
+func (s *svcWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete {
+		return admission.Allowed("")
+	}
+	obj := s.newObject(req.Kind.Group, req.Kind.Version, req.Kind.Kind)
+	if obj == nil {
+		return admission.Allowed(fmt.Sprintf("unsupported resource: %s", req.Kind.String()))
+	}
+	if err := s.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	log := s.log.WithValues("kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name)
+	errs, warnings, err := s.validate(obj)
+	if err != nil {
+		log.Error(err, "error validating resource")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(errs) > 0 {
+		log.Info("resource rejected", "errors", errs, "warnings", warnings)
+		return admission.Denied(strings.Join(errs, "; ")).WithWarnings(warnings...)
+	}
+	log.V(1).Info("resource accepted", "warnings", warnings)
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+func (s *svcWebhook) newObject(group, version, kind string) client.Object {
+	switch {
+	case group == networking.GroupName && kind == "Ingress":
+		return &networking.Ingress{}
+	case group == networking.GroupName && kind == "IngressClass":
+		return &networking.IngressClass{}
+	case group == gatewayv1.GroupName && version == gatewayv1.GroupVersion.Version && kind == "HTTPRoute":
+		return &gatewayv1.HTTPRoute{}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestWebhookHandle(t *testing.T) {
+	ingress := metav1.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+	ingressClass := metav1.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}
+	httpRouteV1 := metav1.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	httpRouteB1 := metav1.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "HTTPRoute"}
+	service := metav1.GroupVersionKind{Group: "", Version: "v1", Kind: "Service"}
+	ingressRaw := `{"apiVersion":"networking.k8s.io/v1","kind":"Ingress","metadata":{"name":"ing1","namespace":"default"}}`
+	ingressClassRaw := `{"apiVersion":"networking.k8s.io/v1","kind":"IngressClass","metadata":{"name":"haproxy"}}`
+	httpRouteRaw := `{"apiVersion":"gateway.networking.k8s.io/v1","kind":"HTTPRoute","metadata":{"name":"route1","namespace":"default"}}`
+
+	type validation struct {
+		errs, warnings []string
+		err            error
+	}
+	testCases := []struct {
+		operation   admissionv1.Operation
+		kind        metav1.GroupVersionKind
+		raw         string
+		validation  validation
+		expValidate string
+		expAllowed  bool
+		expCode     int32
+		expMessage  string
+		expWarnings []string
+	}{
+		// 0
+		{
+			operation:   admissionv1.Create,
+			kind:        ingress,
+			raw:         ingressRaw,
+			expValidate: "*v1.Ingress default/ing1",
+			expAllowed:  true,
+			expCode:     200,
+		},
+		// 1
+		{
+			operation:   admissionv1.Update,
+			kind:        ingress,
+			raw:         ingressRaw,
+			validation:  validation{warnings: []string{"service not found"}},
+			expValidate: "*v1.Ingress default/ing1",
+			expAllowed:  true,
+			expCode:     200,
+			expWarnings: []string{"service not found"},
+		},
+		// 2
+		{
+			operation:   admissionv1.Create,
+			kind:        ingress,
+			raw:         ingressRaw,
+			validation:  validation{errs: []string{"invalid value 1", "invalid value 2"}, warnings: []string{"secret not found"}},
+			expValidate: "*v1.Ingress default/ing1",
+			expCode:     403,
+			expMessage:  "invalid value 1; invalid value 2",
+			expWarnings: []string{"secret not found"},
+		},
+		// 3
+		{
+			operation:   admissionv1.Create,
+			kind:        ingress,
+			raw:         ingressRaw,
+			validation:  validation{err: fmt.Errorf("error reading global ConfigMap")},
+			expValidate: "*v1.Ingress default/ing1",
+			expCode:     500,
+			expMessage:  "error reading global ConfigMap",
+		},
+		// 4
+		{
+			operation:   admissionv1.Create,
+			kind:        ingressClass,
+			raw:         ingressClassRaw,
+			validation:  validation{errs: []string{"unsupported Parameters' Kind"}},
+			expValidate: "*v1.IngressClass /haproxy",
+			expCode:     403,
+			expMessage:  "unsupported Parameters' Kind",
+		},
+		// 5
+		{
+			operation:   admissionv1.Create,
+			kind:        httpRouteV1,
+			raw:         httpRouteRaw,
+			expValidate: "*v1.HTTPRoute default/route1",
+			expAllowed:  true,
+			expCode:     200,
+		},
+		// 6
+		{
+			operation:  admissionv1.Create,
+			kind:       httpRouteB1,
+			raw:        httpRouteRaw,
+			validation: validation{errs: []string{"should not be called"}},
+			expAllowed: true,
+			expCode:    200,
+			expMessage: "unsupported resource: gateway.networking.k8s.io/v1beta1, Kind=HTTPRoute",
+		},
+		// 7
+		{
+			operation:  admissionv1.Create,
+			kind:       service,
+			raw:        `{"apiVersion":"v1","kind":"Service","metadata":{"name":"svc1","namespace":"default"}}`,
+			validation: validation{errs: []string{"should not be called"}},
+			expAllowed: true,
+			expCode:    200,
+			expMessage: "unsupported resource: /v1, Kind=Service",
+		},
+		// 8
+		{
+			operation:  admissionv1.Delete,
+			kind:       ingress,
+			validation: validation{errs: []string{"should not be called"}},
+			expAllowed: true,
+			expCode:    200,
+		},
+		// 9
+		{
+			operation:  admissionv1.Create,
+			kind:       ingress,
+			raw:        `{"apiVersion":"networking.k8s.io/v1","kind":"Ingress","metadata":`,
+			validation: validation{errs: []string{"should not be called"}},
+			expCode:    400,
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = gatewayv1.Install(scheme)
+	for i, test := range testCases {
+		var validated string
+		s := &svcWebhook{
+			decoder: admission.NewDecoder(scheme),
+			validate: func(obj client.Object) (errs, warnings []string, err error) {
+				validated = fmt.Sprintf("%T %s/%s", obj, obj.GetNamespace(), obj.GetName())
+				return test.validation.errs, test.validation.warnings, test.validation.err
+			},
+		}
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: test.operation,
+			Kind:      test.kind,
+		}}
+		req.Object.Raw = []byte(test.raw)
+		rsp := s.Handle(context.Background(), req)
+		if validated != test.expValidate {
+			t.Errorf("validated object differs on %d - expected: '%s' - actual: '%s'", i, test.expValidate, validated)
+		}
+		if rsp.Allowed != test.expAllowed {
+			t.Errorf("allowed differs on %d - expected: %t - actual: %t", i, test.expAllowed, rsp.Allowed)
+		}
+		var code int32
+		var message string
+		if rsp.Result != nil {
+			code = rsp.Result.Code
+			message = rsp.Result.Message
+		}
+		if code != test.expCode {
+			t.Errorf("code differs on %d - expected: %d - actual: %d", i, test.expCode, code)
+		}
+		if test.expCode != 400 && message != test.expMessage {
+			t.Errorf("message differs on %d - expected: '%s' - actual: '%s'", i, test.expMessage, message)
+		}
+		if !reflect.DeepEqual(rsp.Warnings, test.expWarnings) {
+			t.Errorf("warnings differ on %d - expected: %v - actual: %v", i, test.expWarnings, rsp.Warnings)
+		}
+	}
+}
@@ -74,7 +74,7 @@ func (c *tcpSvcConverter) Sync() {
 	for k, v := range tcpservices {
 		publicport, err := strconv.Atoi(k)
 		if err != nil {
-			c.logger.Warn("skipping invalid public listening port of TCP service: %s", k)
+			convtypes.Reject(c.logger, "skipping invalid public listening port of TCP service: %s", k)
 			continue
 		}
 		svc := c.parseService(v)
@@ -120,7 +120,7 @@ func (c *tcpSvcConverter) Sync() {
 			} else if regexValidTime.MatchString(svc.checkInt) {
 				checkInterval = svc.checkInt
 			} else {
-				c.logger.Warn(
+				convtypes.Reject(c.logger,
 					"using default check interval '%s' due to an invalid time config on TCP service %d: %s",
 					checkInterval, publicport, svc.checkInt)
 			}
@@ -215,6 +215,19 @@ func (s *source) String() string {
 	return fmt.Sprintf("%s '%s/%s'", s.kind, s.namespace, s.name)
 }
 
+func (s *source) ObjectReference() *api.ObjectReference {
+	if s == nil || s.obj == nil {
+		return nil
+	}
+	return &api.ObjectReference{
+		APIVersion: s.obj.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+		Kind:       s.kind,
+		Namespace:  s.namespace,
+		Name:       s.name,
+		UID:        s.obj.GetUID(),
+	}
+}
+
 func newSource(obj client.Object) source {
 	return source{
 		obj:       obj,
@@ -282,7 +295,7 @@ func (c *converter) syncRoute(routeSource *source, parentRefs []gatewayv1.Parent
 			parentKind = *parentRef.Kind
 		}
 		if parentGroup != gatewayGroup || parentKind != gatewayKind {
-			c.logger.Warn("ignoring unsupported Group/Kind reference on %s: %s/%s",
+			convtypes.Reject(c.logger, "ignoring unsupported Group/Kind reference on %s: %s/%s",
 				routeSource, parentGroup, parentKind)
 			continue
 		}
@@ -453,7 +466,16 @@ func (c *converter) createBackend(routeSource *source, index string, backendRefs
 			c.logger.Warn("skipping service '%s' on %s: port '%s' not found", back.Name, routeSource, portStr)
 			continue
 		}
-		epready, _, err := convutils.CreateEndpoints(c.cache, svc, svcport, c.options.EnableEPSlices)
+		epready, epnotready, err := convutils.CreateEndpoints(c.cache, svc, svcport, c.options.EnableEPSlices)
+		if err == nil && c.options.PodReadinessGate {
+			for _, addr := range epnotready {
+				if addr.TargetRef != "" {
+					c.tracker.TrackRefName([]convtypes.TrackingRef{{Context: convtypes.ResourcePod, UniqueName: addr.TargetRef}},
+						convtypes.ResourceGateway, "gw")
+				}
+			}
+			epready = append(epready, convutils.GatedEndpoints(c.cache, epnotready)...)
+		}
 		if err != nil {
 			c.logger.Warn("skipping service '%s' on %s: %v", back.Name, routeSource, err)
 			continue
@@ -529,7 +551,14 @@ func (c *converter) createHTTPHosts(routeSource *source, hostnames []gatewayv1.H
 			if hstr == "" || hstr == "*" {
 				hstr = hatypes.DefaultHost
 			}
-			h := c.haproxy.Hosts().AcquireHost(hstr)
+			h, err := convutils.AcquireHostOwnership(c.options.DynamicConfig, c.haproxy.Hosts(), hstr, routeSource.namespace, routeSource.obj.GetCreationTimestamp().Time)
+			if err != nil {
+				c.tracker.TrackRefName([]convtypes.TrackingRef{
+					{Context: convtypes.ResourceHAHostname, UniqueName: hstr},
+				}, convtypes.ResourceGateway, "gw")
+				c.logger.Warn("skipping hostname '%s' on %s: %v", hstr, routeSource, err)
+				continue
+			}
 			pathlink := hatypes.CreateHostPathLink(hstr, path, haMatch)
 			var haheaders hatypes.HTTPHeaderMatch
 			for _, header := range match.Headers {
@@ -542,11 +571,11 @@ func (c *converter) createHTTPHosts(routeSource *source, hostnames []gatewayv1.H
 			pathlink.WithHeadersMatch(haheaders)
 			if h.FindPathWithLink(pathlink) != nil {
 				if backend.ModeTCP && h.SSLPassthrough() {
-					c.logger.Warn("skipping redeclared ssl-passthrough root path on %s", routeSource)
+					convtypes.Reject(c.logger, "skipping redeclared ssl-passthrough root path on %s", routeSource)
 					continue
 				}
 				if !backend.ModeTCP && !h.SSLPassthrough() {
-					c.logger.Warn("skipping redeclared path '%s' type '%s' on %s", path, haMatch, routeSource)
+					convtypes.Reject(c.logger, "skipping redeclared path '%s' type '%s' on %s", path, haMatch, routeSource)
 					continue
 				}
 			}
@@ -555,6 +584,7 @@ func (c *converter) createHTTPHosts(routeSource *source, hostnames []gatewayv1.H
 			}, convtypes.ResourceGateway, "gw")
 			h.TLS.UseDefaultCrt = false
 			h.AddLink(backend, pathlink)
+			h.FindPathWithLink(pathlink).Source = routeSource.String()
 			c.handlePassthrough(path, h, backend, routeSource)
 			hosts = append(hosts, h)
 			pathLinks = append(pathLinks, pathlink)
@@ -572,7 +602,7 @@ func (c *converter) createTCPService(port gatewayv1.PortNumber, backend *hatypes
 	backend.ModeTCP = true
 	_, tcphost := c.haproxy.TCPServices().AcquireTCPService(hostname)
 	if !tcphost.Backend.IsEmpty() {
-		c.logger.Warn("skipping redeclared TCPService '%s'", hostname)
+		convtypes.Reject(c.logger, "skipping redeclared TCPService '%s'", hostname)
 		return nil
 	}
 	c.tracker.TrackNames(convtypes.ResourceHAHostname, hostname, convtypes.ResourceGateway, "gw")
@@ -605,7 +635,7 @@ func (c *converter) handlePassthrough(path string, h *hatypes.Host, b *hatypes.B
 					h.HTTPPassthroughBackend = b.ID
 				}
 			} else {
-				c.logger.Warn("skipping redeclared http root path on %s", routeSource)
+				convtypes.Reject(c.logger, "skipping redeclared http root path on %s", routeSource)
 			}
 			// and
 			// 2. remove it from the target HTTPS configuration
@@ -658,7 +688,7 @@ func (c *converter) applyCertRef(source *gatewaySource, listener *gatewayv1.List
 	}
 	for _, host := range hosts {
 		if host.TLS.TLSHash != "" && host.TLS.TLSHash != crtFile.SHA1Hash {
-			c.logger.Warn("skipping certificate reference on %s listener '%s' for hostname '%s': a TLS certificate was already assigned",
+			convtypes.Reject(c.logger, "skipping certificate reference on %s listener '%s' for hostname '%s': a TLS certificate was already assigned",
 				source, listener.Name, host.Hostname)
 			continue
 		}
@@ -294,6 +294,81 @@ WARN skipping redeclared path '/' type 'prefix' on HTTPRoute 'default/web2'
   - ip: 172.17.0.12
     port: 8080
     weight: 128
+`,
+		},
+		{
+			id: "host-ownership-1",
+			config: func(c *testConfig) {
+				c.dynconfig.HostOwnership = true
+				g := c.createGateway1("ns1/web", "l1")
+				r1 := c.createHTTPRoute2("ns1/web1", "ns1/web", "echoserver1:8080", "/app1")
+				r2 := c.createHTTPRoute2("ns2/web2", "ns1/web", "echoserver2:8080", "/app2")
+				c.createService1("ns1/echoserver1", "8080", "172.17.0.11")
+				c.createService1("ns2/echoserver2", "8080", "172.17.0.12")
+				r1.Spec.Hostnames = []gatewayv1.Hostname{"host1.local"}
+				r2.Spec.Hostnames = []gatewayv1.Hostname{"host1.local"}
+				all := gatewayv1.NamespacesFromAll
+				g.Spec.Listeners[0].AllowedRoutes.Namespaces.From = &all
+			},
+			expLogging: `
+WARN skipping hostname 'host1.local' on HTTPRoute 'ns2/web2': hostname 'host1.local' is owned by namespace 'ns1'
+`,
+			expHosts: `
+- hostname: host1.local
+  paths:
+  - path: /app1
+    match: prefix
+    backend: ns1_web1__rule0
+`,
+			expBackends: `
+- id: ns1_web1__rule0
+  endpoints:
+  - ip: 172.17.0.11
+    port: 8080
+    weight: 128
+- id: ns2_web2__rule0
+  endpoints:
+  - ip: 172.17.0.12
+    port: 8080
+    weight: 128
+`,
+		},
+		{
+			id: "host-ownership-delegation-1",
+			config: func(c *testConfig) {
+				c.dynconfig.HostOwnership = true
+				c.dynconfig.HostOwnershipDelegation = map[string][]string{"local": {"ns2"}}
+				g := c.createGateway1("ns1/web", "l1")
+				r1 := c.createHTTPRoute2("ns1/web1", "ns1/web", "echoserver1:8080", "/app1")
+				r2 := c.createHTTPRoute2("ns2/web2", "ns1/web", "echoserver2:8080", "/app2")
+				c.createService1("ns1/echoserver1", "8080", "172.17.0.11")
+				c.createService1("ns2/echoserver2", "8080", "172.17.0.12")
+				r1.Spec.Hostnames = []gatewayv1.Hostname{"host1.local"}
+				r2.Spec.Hostnames = []gatewayv1.Hostname{"host1.local"}
+				all := gatewayv1.NamespacesFromAll
+				g.Spec.Listeners[0].AllowedRoutes.Namespaces.From = &all
+			},
+			expLogging: `
+WARN skipping hostname 'host1.local' on HTTPRoute 'ns1/web1': hostname 'host1.local' is not delegated to namespace 'ns1'
+`,
+			expHosts: `
+- hostname: host1.local
+  paths:
+  - path: /app2
+    match: prefix
+    backend: ns2_web2__rule0
+`,
+			expBackends: `
+- id: ns1_web1__rule0
+  endpoints:
+  - ip: 172.17.0.11
+    port: 8080
+    weight: 128
+- id: ns2_web2__rule0
+  endpoints:
+  - ip: 172.17.0.12
+    port: 8080
+    weight: 128
 `,
 		},
 	})
@@ -983,22 +1058,24 @@ func runTestSync(t *testing.T, testCases []testCaseSync) {
 }
 
 type testConfig struct {
-	t       *testing.T
-	cache   *conv_helper.CacheMock
-	logger  *types_helper.LoggerMock
-	tracker convtypes.Tracker
-	hconfig haproxy.Config
+	t         *testing.T
+	cache     *conv_helper.CacheMock
+	logger    *types_helper.LoggerMock
+	tracker   convtypes.Tracker
+	hconfig   haproxy.Config
+	dynconfig *convtypes.DynamicConfig
 }
 
 func setup(t *testing.T) *testConfig {
 	logger := types_helper.NewLoggerMock(t)
 	tracker := tracker.NewTracker()
 	c := &testConfig{
-		t:       t,
-		hconfig: haproxy.CreateInstance(logger, haproxy.InstanceOptions{}).Config(),
-		cache:   conv_helper.NewCacheMock(tracker),
-		logger:  logger,
-		tracker: tracker,
+		t:         t,
+		hconfig:   haproxy.CreateInstance(logger, haproxy.InstanceOptions{}).Config(),
+		cache:     conv_helper.NewCacheMock(tracker),
+		logger:    logger,
+		tracker:   tracker,
+		dynconfig: &convtypes.DynamicConfig{},
 	}
 	t.Cleanup(func() {
 		c.logger.CompareLogging("")
@@ -1017,6 +1094,7 @@ func (c *testConfig) createConverter() gateway.Config {
 			Cache:         c.cache,
 			Logger:        c.logger,
 			Tracker:       c.tracker,
+			DynamicConfig: c.dynconfig,
 			HasTCPRouteA2: true,
 		},
 		c.hconfig,
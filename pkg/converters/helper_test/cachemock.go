@@ -20,6 +20,7 @@ import (
 	"crypto/sha1"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"time"
 
@@ -58,6 +59,7 @@ type CacheMock struct {
 	ConfigMapList map[string]*api.ConfigMap
 	TermPodList   map[string][]*api.Pod
 	PodList       map[string]*api.Pod
+	CtrlPodList   []*api.Pod
 	SecretTLSPath map[string]string
 	SecretCAPath  map[string]string
 	SecretCRLPath map[string]string
@@ -200,6 +202,18 @@ func (c *CacheMock) GetNamespace(name string) (*api.Namespace, error) {
 	return nil, fmt.Errorf("namespace not found: %s", name)
 }
 
+// GetNamespaceList ...
+func (c *CacheMock) GetNamespaceList() ([]*api.Namespace, error) {
+	nsList := make([]*api.Namespace, 0, len(c.NsList))
+	for _, ns := range c.NsList {
+		nsList = append(nsList, ns)
+	}
+	sort.Slice(nsList, func(i, j int) bool {
+		return nsList[i].Name < nsList[j].Name
+	})
+	return nsList, nil
+}
+
 // GetTerminatingPods ...
 func (c *CacheMock) GetTerminatingPods(service *api.Service, track []convtypes.TrackingRef) ([]*api.Pod, error) {
 	serviceName := service.Namespace + "/" + service.Name
@@ -222,6 +236,14 @@ func (c *CacheMock) GetPodNamespace() string {
 	return "ingress-controller"
 }
 
+// GetControllerPodList ...
+func (c *CacheMock) GetControllerPodList() ([]*api.Pod, error) {
+	if c.CtrlPodList == nil {
+		return nil, fmt.Errorf("controller pods not found")
+	}
+	return c.CtrlPodList, nil
+}
+
 // GetTLSSecretPath ...
 func (c *CacheMock) GetTLSSecretPath(defaultNamespace, secretName string, track []convtypes.TrackingRef) (convtypes.CrtFile, error) {
 	fullname := c.buildResourceName(defaultNamespace, secretName)
@@ -284,6 +306,30 @@ func (c *CacheMock) GetPasswdSecretContent(defaultNamespace, secretName string,
 	return nil, fmt.Errorf("secret not found: '%s'", fullname)
 }
 
+// GetJWTSecretContent ...
+func (c *CacheMock) GetJWTSecretContent(defaultNamespace, secretName string, track []convtypes.TrackingRef) ([]byte, error) {
+	fullname := c.buildResourceName(defaultNamespace, secretName)
+	c.tracker.TrackRefName(track, convtypes.ResourceSecret, fullname)
+	if content, found := c.SecretContent[fullname]; found {
+		keyName := "jwt.key"
+		if val, found := content[keyName]; found {
+			return val, nil
+		}
+		return nil, fmt.Errorf("secret '%s' does not have file/key '%s'", fullname, keyName)
+	}
+	return nil, fmt.Errorf("secret not found: '%s'", fullname)
+}
+
+// GetAPIKeySecretContent ...
+func (c *CacheMock) GetAPIKeySecretContent(defaultNamespace, secretName string, track []convtypes.TrackingRef) (map[string][]byte, error) {
+	fullname := c.buildResourceName(defaultNamespace, secretName)
+	c.tracker.TrackRefName(track, convtypes.ResourceSecret, fullname)
+	if content, found := c.SecretContent[fullname]; found {
+		return content, nil
+	}
+	return nil, fmt.Errorf("secret not found: '%s'", fullname)
+}
+
 // UpdateStatus ...
 func (c *CacheMock) UpdateStatus(client.Object) {}
 
@@ -29,6 +29,7 @@ type (
 	backendMock struct {
 		ID               string
 		Endpoints        []endpointMock    `yaml:",omitempty"`
+		Fallback         []endpointMock    `yaml:",omitempty"`
 		Paths            []backendPathMock `yaml:",omitempty"`
 		BalanceAlgorithm string            `yaml:",omitempty"`
 		MaxConnServer    int               `yaml:",omitempty"`
@@ -101,6 +102,10 @@ func marshalBackends(weight bool, habackends ...*hatypes.Backend) []backendMock
 			}
 			endpoints = append(endpoints, endpoint)
 		}
+		var fallback []endpointMock
+		for _, e := range b.Fallback {
+			fallback = append(fallback, endpointMock{IP: e.IP, Port: e.Port})
+		}
 		var paths []backendPathMock
 		for _, p := range b.Paths {
 			if p.MaxBodySize > 0 {
@@ -110,6 +115,7 @@ func marshalBackends(weight bool, habackends ...*hatypes.Backend) []backendMock
 		backends = append(backends, backendMock{
 			ID:               b.ID,
 			Endpoints:        endpoints,
+			Fallback:         fallback,
 			Paths:            paths,
 			BalanceAlgorithm: b.BalanceAlgorithm,
 			MaxConnServer:    b.Server.MaxConn,
@@ -17,12 +17,24 @@ limitations under the License.
 package annotations
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
 	ingutils "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/utils"
@@ -52,7 +64,7 @@ func (c *updater) buildBackendAffinity(d *backData) {
 		strategyName = strategy.Value
 	default:
 		if strategy.Source != nil {
-			c.logger.Warn("invalid affinity cookie strategy '%s' on %v, using 'insert' instead", strategy.Value, strategy.Source)
+			convtypes.Reject(c.logger, "invalid affinity cookie strategy '%s' on %v, using 'insert' instead", strategy.Value, strategy.Source)
 		}
 		strategyName = "insert"
 	}
@@ -88,7 +100,7 @@ func (c *updater) buildBackendAffinity(d *backData) {
 	case "server-name":
 		d.backend.EpCookieStrategy = hatypes.EpCookieName
 	default:
-		c.logger.Warn("invalid session-cookie-value-strategy '%s' on %s, using 'server-name' instead", cookieStrategy.Value, cookieStrategy.Source)
+		convtypes.Reject(c.logger, "invalid session-cookie-value-strategy '%s' on %s, using 'server-name' instead", cookieStrategy.Value, cookieStrategy.Source)
 		fallthrough
 	case "":
 		d.backend.EpCookieStrategy = hatypes.EpCookieName
@@ -142,7 +154,7 @@ func (c *updater) setAuthExternal(config ConfigValueGetter, auth *hatypes.AuthEx
 		} else {
 			var err error
 			if ipList, err = lookupHost(urlHost); err != nil {
-				c.logger.Warn("ignoring auth URL with an invalid domain on %s: %v", url.Source.String(), err)
+				convtypes.Reject(c.logger, "ignoring auth URL with an invalid domain on %s: %v", url.Source.String(), err)
 				return
 			}
 			hostname = urlHost
@@ -189,7 +201,7 @@ func (c *updater) setAuthExternal(config ConfigValueGetter, auth *hatypes.AuthEx
 			return
 		}
 	default:
-		c.logger.Warn("ignoring auth URL with an invalid protocol on %s: %s", url.Source.String(), urlProto)
+		convtypes.Reject(c.logger, "ignoring auth URL with an invalid protocol on %s: %s", url.Source.String(), urlProto)
 		return
 	}
 	// TODO track
@@ -209,14 +221,14 @@ func (c *updater) setAuthExternal(config ConfigValueGetter, auth *hatypes.AuthEx
 	m := config.Get(ingtypes.BackAuthMethod)
 	method := m.Value
 	if !validMethodRegex.MatchString(method) {
-		c.logger.Warn("invalid request method '%s' on %s, using GET instead", method, m.Source.String())
+		convtypes.Reject(c.logger, "invalid request method '%s' on %s, using GET instead", method, m.Source.String())
 		method = "GET"
 	}
 
 	s := config.Get(ingtypes.BackAuthSignin)
 	signin := s.Value
 	if signin != "" && !validURLRegex.MatchString(signin) {
-		c.logger.Warn("ignoring invalid sign-in URL on %s: %s", s.Source.String(), signin)
+		convtypes.Reject(c.logger, "ignoring invalid sign-in URL on %s: %s", s.Source.String(), signin)
 		signin = ""
 	}
 
@@ -325,6 +337,312 @@ func (c *updater) buildBackendAuthHTTP(d *backData) {
 	}
 }
 
+var (
+	apiKeyRegex           = regexp.MustCompile(`^[!$%&()*+,./0-9:<=>?@A-Z\[\]^_a-z{|}~-]+$`)
+	apiKeyHeaderRegex     = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	apiKeyQueryParamRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+func (c *updater) buildBackendAuthAPIKey(d *backData) {
+	for _, path := range d.backend.Paths {
+		config := d.mapper.GetConfig(path.Link)
+		apiKeySecret := config.Get(ingtypes.BackAuthAPIKeySecret)
+		if apiKeySecret.Value == "" {
+			continue
+		}
+		header := config.Get(ingtypes.BackAuthAPIKeyHeader)
+		query := config.Get(ingtypes.BackAuthAPIKeyQuery)
+		labelHeader := config.Get(ingtypes.BackAuthAPIKeyLabelHeader)
+		if header.Value != "" && !apiKeyHeaderRegex.MatchString(header.Value) {
+			convtypes.Reject(c.logger, "ignoring API key authentication on %v: invalid header name: %s", header.Source, header.Value)
+			continue
+		}
+		if query.Value != "" && !apiKeyQueryParamRegex.MatchString(query.Value) {
+			convtypes.Reject(c.logger, "ignoring API key authentication on %v: invalid query parameter: %s", query.Source, query.Value)
+			continue
+		}
+		if labelHeader.Value != "" && !apiKeyHeaderRegex.MatchString(labelHeader.Value) {
+			convtypes.Reject(c.logger, "ignoring API key authentication on %v: invalid label header name: %s", labelHeader.Source, labelHeader.Value)
+			continue
+		}
+		headerName := header.Value
+		if headerName == "" && query.Value == "" {
+			headerName = "X-Api-Key"
+		}
+
+		// starting here requests should be denied if the configuration fails,
+		// an API key map without keys is used
+		var names []string
+		var secrets []string
+		added := map[string]bool{}
+		for _, secretName := range utils.Split(apiKeySecret.Value, ",") {
+			fullName := secretName
+			if !strings.Contains(fullName, "/") {
+				fullName = apiKeySecret.Source.Namespace + "/" + fullName
+			}
+			name := strings.Replace(fullName, "/", "_", 1)
+			if added[name] {
+				continue
+			}
+			added[name] = true
+			names = append(names, name)
+			secrets = append(secrets, secretName)
+		}
+		sort.Strings(names)
+		mapName := strings.Join(names, "__")
+		apiKeys := d.backend.FindAPIKeyMap(mapName)
+		if apiKeys == nil {
+			apiKeys = &hatypes.APIKeyMap{
+				Name: mapName,
+				Keys: c.readAPIKeys(d, apiKeySecret.Source, secrets),
+			}
+			d.backend.APIKeys = append(d.backend.APIKeys, apiKeys)
+		}
+		path.AuthAPIKey = hatypes.AuthAPIKey{
+			Header:      headerName,
+			LabelHeader: labelHeader.Value,
+			MapName:     mapName,
+			Query:       query.Value,
+		}
+	}
+}
+
+// readAPIKeys reads the API keys of a list of secrets, every entry of a
+// secret is an API key, and the name of the entry is used as its label.
+func (c *updater) readAPIKeys(d *backData, source *Source, secrets []string) map[string]string {
+	keys := map[string]string{}
+	for _, secretName := range secrets {
+		content, err := c.cache.GetAPIKeySecretContent(
+			source.Namespace,
+			secretName,
+			[]convtypes.TrackingRef{{Context: convtypes.ResourceHABackend, UniqueName: d.backend.ID}},
+		)
+		if err != nil {
+			c.logger.Error("error reading API keys on %v: %v", source, err)
+			continue
+		}
+		labels := make([]string, 0, len(content))
+		for label := range content {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			key := strings.TrimSpace(string(content[label]))
+			if !apiKeyRegex.MatchString(key) {
+				convtypes.Reject(c.logger, "ignoring invalid API key '%s' of secret '%s' on %v", label, secretName, source)
+				continue
+			}
+			if otherLabel, found := keys[key]; found {
+				convtypes.Reject(c.logger, "ignoring API key '%s' of secret '%s' on %v: key already used by '%s'", label, secretName, source, otherLabel)
+				continue
+			}
+			keys[key] = label
+		}
+	}
+	if len(keys) == 0 {
+		c.logger.Warn("API key list on %v is empty, all requests will be denied", source)
+	}
+	return keys
+}
+
+var (
+	jwtAlgorithmRegex = regexp.MustCompile(`^(RS|PS|ES)(256|384|512)$`)
+	jwtClaimRegex     = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+	jwtHeaderRegex    = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	jwtValueRegex     = regexp.MustCompile(`^[^\s"'#{}\\]+$`)
+)
+
+func (c *updater) buildBackendAuthJWT(d *backData) {
+	for _, path := range d.backend.Paths {
+		config := d.mapper.GetConfig(path.Link)
+		jwtSecret := config.Get(ingtypes.BackAuthJWTSecret)
+		if jwtSecret.Value == "" {
+			continue
+		}
+		algorithm := config.Get(ingtypes.BackAuthJWTAlgorithm)
+		if algorithm.Value != "" && !jwtAlgorithmRegex.MatchString(algorithm.Value) {
+			convtypes.Reject(c.logger, "ignoring JWT authentication on %v: unsupported algorithm: %s", algorithm.Source, algorithm.Value)
+			continue
+		}
+		issuer := config.Get(ingtypes.BackAuthJWTIssuer)
+		if issuer.Value != "" && !jwtValueRegex.MatchString(issuer.Value) {
+			convtypes.Reject(c.logger, "ignoring JWT authentication on %v: invalid issuer: %s", issuer.Source, issuer.Value)
+			continue
+		}
+		audience := config.Get(ingtypes.BackAuthJWTAudience)
+		if audience.Value != "" && !jwtValueRegex.MatchString(audience.Value) {
+			convtypes.Reject(c.logger, "ignoring JWT authentication on %v: invalid audience: %s", audience.Source, audience.Value)
+			continue
+		}
+
+		// starting here requests should be denied if the configuration fails
+		keyb, err := c.cache.GetJWTSecretContent(
+			jwtSecret.Source.Namespace,
+			jwtSecret.Value,
+			[]convtypes.TrackingRef{{Context: convtypes.ResourceHABackend, UniqueName: d.backend.ID}},
+		)
+		if err != nil {
+			c.logger.Error("error reading JWT keys on %v: %v", jwtSecret.Source, err)
+			path.AuthJWT.AlwaysDeny = true
+			continue
+		}
+		keys, errs := extractJWTKeys(keyb, algorithm.Value)
+		for _, err := range errs {
+			c.logger.Warn("ignoring JWT key on %v: %v", jwtSecret.Source, err)
+		}
+		if len(keys) == 0 {
+			c.logger.Warn("JWT key list on %v is empty, all requests will be denied", jwtSecret.Source)
+			path.AuthJWT.AlwaysDeny = true
+			continue
+		}
+		var claimHeaders []hatypes.JWTClaimHeader
+		claimHeadersCfg := config.Get(ingtypes.BackAuthJWTClaimHeaders)
+		for _, claimHeader := range utils.Split(claimHeadersCfg.Value, ",") {
+			claim, header, _ := strings.Cut(claimHeader, ":")
+			if !jwtClaimRegex.MatchString(claim) || !jwtHeaderRegex.MatchString(header) {
+				convtypes.Reject(c.logger, "ignoring invalid JWT claim header on %v: %s", claimHeadersCfg.Source, claimHeader)
+				continue
+			}
+			claimHeaders = append(claimHeaders, hatypes.JWTClaimHeader{
+				Claim:  claim,
+				Header: header,
+			})
+		}
+		path.AuthJWT = hatypes.AuthJWT{
+			Audience:     audience.Value,
+			ClaimHeaders: claimHeaders,
+			Issuer:       issuer.Value,
+			Keys:         keys,
+		}
+	}
+}
+
+// extractJWTKeys reads the public keys used to verify JWT signatures. content
+// is either a JSON Web Key Set or a list of PEM encoded public keys or
+// certificates. algorithm is used on keys that does not declare one.
+func extractJWTKeys(content []byte, algorithm string) ([]hatypes.JWTKey, []error) {
+	type jwk struct {
+		Alg string `json:"alg"`
+		Crv string `json:"crv"`
+		E   string `json:"e"`
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		Use string `json:"use"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	var keys []hatypes.JWTKey
+	var errs []error
+	addKey := func(name, kid, alg string, pub crypto.PublicKey) {
+		var defaultAlg string
+		switch k := pub.(type) {
+		case *rsa.PublicKey:
+			defaultAlg = "RS256"
+		case *ecdsa.PublicKey:
+			defaultAlg = "ES" + strconv.Itoa(map[int]int{256: 256, 384: 384, 521: 512}[k.Curve.Params().BitSize])
+		default:
+			errs = append(errs, fmt.Errorf("unsupported public key type: %T", pub))
+			return
+		}
+		if alg == "" {
+			alg = algorithm
+		}
+		if alg == "" {
+			alg = defaultAlg
+		}
+		if !jwtAlgorithmRegex.MatchString(alg) || (alg[:2] == "ES") != (defaultAlg[:2] == "ES") {
+			errs = append(errs, fmt.Errorf("unsupported algorithm '%s' of key '%s'", alg, name))
+			return
+		}
+		if kid != "" && !jwtValueRegex.MatchString(kid) {
+			errs = append(errs, fmt.Errorf("invalid key ID of key '%s'", name))
+			return
+		}
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		keys = append(keys, hatypes.JWTKey{
+			Algorithm: alg,
+			ID:        kid,
+			Key:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		})
+	}
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		var jwks struct {
+			Keys []jwk `json:"keys"`
+		}
+		if err := json.Unmarshal(trimmed, &jwks); err != nil {
+			return nil, []error{fmt.Errorf("error parsing JWKS: %w", err)}
+		}
+		decode := func(value string) *big.Int {
+			b, err := base64.RawURLEncoding.DecodeString(value)
+			if err != nil || len(b) == 0 {
+				return nil
+			}
+			return new(big.Int).SetBytes(b)
+		}
+		for i, key := range jwks.Keys {
+			if key.Use != "" && key.Use != "sig" {
+				continue
+			}
+			name := key.Kid
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			switch key.Kty {
+			case "RSA":
+				n, e := decode(key.N), decode(key.E)
+				if n == nil || e == nil || !e.IsInt64() {
+					errs = append(errs, fmt.Errorf("invalid RSA key '%s'", name))
+					continue
+				}
+				addKey(name, key.Kid, key.Alg, &rsa.PublicKey{N: n, E: int(e.Int64())})
+			case "EC":
+				curve := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}[key.Crv]
+				x, y := decode(key.X), decode(key.Y)
+				if curve == nil || x == nil || y == nil || !curve.IsOnCurve(x, y) {
+					errs = append(errs, fmt.Errorf("invalid EC key '%s'", name))
+					continue
+				}
+				addKey(name, key.Kid, key.Alg, &ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+			default:
+				errs = append(errs, fmt.Errorf("unsupported key type '%s' of key '%s'", key.Kty, name))
+			}
+		}
+		return keys, errs
+	}
+	for i, rest := 1, content; ; i++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		name := fmt.Sprintf("#%d", i)
+		switch block.Type {
+		case "PUBLIC KEY":
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error parsing public key '%s': %w", name, err))
+				continue
+			}
+			addKey(name, "", "", pub)
+		case "CERTIFICATE":
+			crt, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error parsing certificate '%s': %w", name, err))
+				continue
+			}
+			addKey(name, "", "", crt.PublicKey)
+		default:
+			errs = append(errs, fmt.Errorf("unsupported PEM block '%s'", block.Type))
+		}
+	}
+	return keys, errs
+}
+
 func extractUserlist(source, secret, users string) ([]hatypes.User, []error) {
 	var userlist []hatypes.User
 	var err []error
@@ -394,11 +712,11 @@ func (c *updater) buildBackendBlueGreenBalance(d *backData) {
 			return
 		}
 		if w < 0 {
-			c.logger.Warn("invalid weight '%d' on %v, using '0' instead", w, balance.Source)
+			convtypes.Reject(c.logger, "invalid weight '%d' on %v, using '0' instead", w, balance.Source)
 			w = 0
 		}
 		if w > 256 {
-			c.logger.Warn("invalid weight '%d' on %v, using '256' instead", w, balance.Source)
+			convtypes.Reject(c.logger, "invalid weight '%d' on %v, using '256' instead", w, balance.Source)
 			w = 256
 		}
 		dw := &deployWeight{
@@ -449,7 +767,7 @@ func (c *updater) buildBackendBlueGreenBalance(d *backData) {
 		// no need to rebalance
 		return
 	} else if mode.Source != nil && mode.Value != "deploy" {
-		c.logger.Warn("unsupported blue/green mode '%s' on %s, falling back to 'deploy'", mode.Value, mode.Source)
+		convtypes.Reject(c.logger, "unsupported blue/green mode '%s' on %s, falling back to 'deploy'", mode.Value, mode.Source)
 	}
 	// mode == deploy, need to recalc based on the number of replicas
 	cl := make([]*convutils.WeightCluster, len(deployWeights))
@@ -529,13 +847,122 @@ func (c *updater) buildBackendBodySize(d *backData) {
 		}
 		value, err := utils.SizeSuffixToInt64(bodysize.Value)
 		if err != nil {
-			c.logger.Warn("ignoring invalid body size on %v: %s", bodysize.Source, bodysize.Value)
+			convtypes.Reject(c.logger, "ignoring invalid body size on %v: %s", bodysize.Source, bodysize.Value)
 			continue
 		}
 		path.MaxBodySize = value
 	}
 }
 
+var compressionTypeRegex = regexp.MustCompile(`^[A-Za-z0-9.+-]+/[A-Za-z0-9.+*-]+$`)
+
+func (c *updater) buildBackendCompression(d *backData) {
+	for _, path := range d.backend.Paths {
+		config := d.mapper.GetConfig(path.Link)
+		path.Compression.Enabled = config.Get(ingtypes.BackCompressionEnable).Bool()
+	}
+	if !d.backend.HasCompression() {
+		return
+	}
+	minSizeCfg := d.mapper.Get(ingtypes.BackCompressionMinSize)
+	minSize := minSizeCfg.Int()
+	if minSizeCfg.Value != "" && minSize <= 0 {
+		convtypes.Reject(c.logger, "ignoring invalid compression min size on %v: %s", minSizeCfg.Source, minSizeCfg.Value)
+		minSize = 0
+	} else if minSize > 0 && !c.options.HAProxyVersion.AtLeast(3, 2) {
+		// `compression minsize-res` is refused by older versions
+		c.logger.Warn("ignoring compression min size on %v: needs haproxy 3.2 or newer, found %s", minSizeCfg.Source, c.options.HAProxyVersion)
+		minSize = 0
+	}
+	var algorithms, types []string
+	algoCfg := d.mapper.Get(ingtypes.BackCompressionAlgo)
+	for _, algo := range utils.Split(algoCfg.Value, ",") {
+		switch algo {
+		case "gzip", "deflate", "raw-deflate":
+			algorithms = append(algorithms, algo)
+		default:
+			convtypes.Reject(c.logger, "ignoring unsupported compression algorithm on %v: %s", algoCfg.Source, algo)
+		}
+	}
+	if len(algorithms) == 0 {
+		algorithms = []string{"gzip"}
+	}
+	typesCfg := d.mapper.Get(ingtypes.BackCompressionTypes)
+	for _, mimeType := range utils.Split(typesCfg.Value, ",") {
+		if !compressionTypeRegex.MatchString(mimeType) {
+			convtypes.Reject(c.logger, "ignoring invalid compression type on %v: %s", typesCfg.Source, mimeType)
+			continue
+		}
+		types = append(types, mimeType)
+	}
+	d.backend.Compression = hatypes.BackendCompression{
+		Algorithms: algorithms,
+		MinSize:    minSize,
+		Offload:    d.mapper.Get(ingtypes.BackCompressionOffload).Bool(),
+		Types:      types,
+	}
+}
+
+// cacheVaryHeaders has the request headers that HAProxy's cache can
+// use to store distinct objects, indexed by their lower case name.
+var cacheVaryHeaders = map[string]string{
+	"accept-encoding": "Accept-Encoding",
+	"origin":          "Origin",
+	"referer":         "Referer",
+}
+
+func (c *updater) buildBackendCache(d *backData) {
+	for _, path := range d.backend.Paths {
+		config := d.mapper.GetConfig(path.Link)
+		if !config.Get(ingtypes.BackCacheEnable).Bool() {
+			continue
+		}
+		var bypass, vary []string
+		bypassCfg := config.Get(ingtypes.BackCacheBypass)
+		for _, rule := range utils.Split(bypassCfg.Value, ",") {
+			expr, err := cacheBypassExpr(rule)
+			if err != nil {
+				c.logger.Warn("ignoring cache bypass rule on %v: %v", bypassCfg.Source, err)
+				continue
+			}
+			bypass = append(bypass, expr)
+		}
+		varyCfg := config.Get(ingtypes.BackCacheVary)
+		for _, header := range utils.Split(varyCfg.Value, ",") {
+			name, found := cacheVaryHeaders[strings.ToLower(header)]
+			if !found {
+				convtypes.Reject(c.logger, "ignoring unsupported cache vary header on %v: %s", varyCfg.Source, header)
+				continue
+			}
+			vary = append(vary, name)
+		}
+		path.Cache = hatypes.Cache{
+			Enabled: true,
+			Bypass:  bypass,
+			Vary:    vary,
+		}
+	}
+}
+
+// cacheBypassExpr converts a cache bypass rule to the HAProxy
+// sample expression that reads its header, cookie or query param.
+func cacheBypassExpr(rule string) (string, error) {
+	fetches := map[string]string{
+		"header": "req.hdr",
+		"cookie": "req.cook",
+		"query":  "url_param",
+	}
+	source, name, _ := strings.Cut(rule, ":")
+	fetch, found := fetches[source]
+	if !found {
+		return "", fmt.Errorf("unsupported rule: %s", rule)
+	}
+	if !rateLimitKeyNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid %s name: %s", source, name)
+	}
+	return fetch + "(" + name + ")", nil
+}
+
 func (c *updater) buildBackendCors(d *backData) {
 	for _, path := range d.backend.Paths {
 		config := d.mapper.GetConfig(path.Link)
@@ -575,12 +1002,12 @@ func (c *updater) buildBackendCustomConfig(d *backData) {
 			continue
 		}
 		if keyword == "*" {
-			c.logger.Warn("skipping configuration snippet on %s: custom configuration is disabled", source)
+			convtypes.Reject(c.logger, "skipping configuration snippet on %s: custom configuration is disabled", source)
 			return
 		}
 		for _, line := range lines {
 			if firstToken(line) == keyword {
-				c.logger.Warn("skipping configuration snippet on %s: keyword '%s' not allowed", source, keyword)
+				convtypes.Reject(c.logger, "skipping configuration snippet on %s: keyword '%s' not allowed", source, keyword)
 				return
 			}
 		}
@@ -654,6 +1081,104 @@ func (c *updater) buildBackendHealthCheck(d *backData) {
 	d.backend.HealthCheck.Port = d.mapper.Get(ingtypes.BackHealthCheckPort).Int()
 	d.backend.HealthCheck.RiseCount = d.mapper.Get(ingtypes.BackHealthCheckRiseCount).Int()
 	d.backend.HealthCheck.URI = d.mapper.Get(ingtypes.BackHealthCheckURI).Value
+	c.buildBackendHealthCheckHTTP(d)
+	observe := d.mapper.Get(ingtypes.BackHealthCheckObserve)
+	switch observe.Value {
+	case "":
+		return
+	case "layer4", "layer7":
+		d.backend.HealthCheck.Observe = observe.Value
+	default:
+		convtypes.Reject(c.logger, "ignoring invalid health check observe mode on %s: %s", observe.Source, observe.Value)
+		return
+	}
+	errorLimit := d.mapper.Get(ingtypes.BackHealthCheckErrorLimit)
+	if limit := errorLimit.Int(); limit > 0 {
+		d.backend.HealthCheck.ErrorLimit = limit
+	} else if errorLimit.Value != "" {
+		convtypes.Reject(c.logger, "ignoring invalid health check error limit on %s: %s", errorLimit.Source, errorLimit.Value)
+	}
+	onError := d.mapper.Get(ingtypes.BackHealthCheckOnError)
+	switch onError.Value {
+	case "":
+	case "fastinter", "fail-check", "sudden-death", "mark-down":
+		d.backend.HealthCheck.OnError = onError.Value
+	default:
+		convtypes.Reject(c.logger, "ignoring invalid health check on-error action on %s: %s", onError.Source, onError.Value)
+	}
+}
+
+var (
+	healthCheckMethodRegex = regexp.MustCompile(`^[A-Z]+$`)
+	healthCheckStatusRegex = regexp.MustCompile(`^[1-5][0-9]{2}(-[1-5][0-9]{2})?(,[1-5][0-9]{2}(-[1-5][0-9]{2})?)*$`)
+	healthCheckHostRegex   = regexp.MustCompile(`^[a-zA-Z0-9.:_-]+$`)
+)
+
+func (c *updater) buildBackendHealthCheckHTTP(d *backData) {
+	hc := &d.backend.HealthCheck
+	protocol := d.mapper.Get(ingtypes.BackHealthCheckProtocol)
+	switch protocol.Value {
+	case "", "http":
+	case "grpc":
+		switch strings.ToLower(d.mapper.Get(ingtypes.BackBackendProtocol).Value) {
+		case "h2", "h2-ssl", "grpc", "grpcs":
+			hc.Protocol = "grpc"
+			// the grpc health checking protocol has its own request and response
+			return
+		default:
+			c.logger.Warn("ignoring grpc health check on %s due to backend protocol not h2", protocol.Source)
+		}
+	default:
+		convtypes.Reject(c.logger, "ignoring invalid health check protocol on %s: %s", protocol.Source, protocol.Value)
+	}
+	if method := d.mapper.Get(ingtypes.BackHealthCheckMethod); method.Value != "" {
+		if value := strings.ToUpper(method.Value); healthCheckMethodRegex.MatchString(value) {
+			hc.Method = value
+		} else {
+			convtypes.Reject(c.logger, "ignoring invalid health check method on %s: %s", method.Source, method.Value)
+		}
+	}
+	if host := d.mapper.Get(ingtypes.BackHealthCheckHost); host.Value != "" {
+		if healthCheckHostRegex.MatchString(host.Value) {
+			hc.Host = host.Value
+		} else {
+			convtypes.Reject(c.logger, "ignoring invalid health check host on %s: %s", host.Source, host.Value)
+		}
+	}
+	if headers := d.mapper.Get(ingtypes.BackHealthCheckHeaders); headers.Value != "" {
+		for _, header := range utils.LineToSlice(headers.Value) {
+			name, value, err := utils.SplitHeaderNameValue(header)
+			if err != nil {
+				c.logger.Warn("ignoring health check header on %s: %v", headers.Source, err)
+				continue
+			}
+			if name == "" {
+				continue
+			}
+			if strings.EqualFold(name, "host") {
+				c.logger.Warn("ignoring host header on %s, use health-check-host instead", headers.Source)
+				continue
+			}
+			hc.Headers = append(hc.Headers, &hatypes.BackendHeader{
+				Name:  name,
+				Value: value,
+			})
+		}
+	}
+	if status := d.mapper.Get(ingtypes.BackHealthCheckExpStatus); status.Value != "" {
+		if value := strings.ReplaceAll(status.Value, " ", ""); healthCheckStatusRegex.MatchString(value) {
+			hc.ExpectStatus = value
+		} else {
+			convtypes.Reject(c.logger, "ignoring invalid health check expected status on %s: %s", status.Source, status.Value)
+		}
+	}
+	if body := d.mapper.Get(ingtypes.BackHealthCheckExpBody); body.Value != "" {
+		if _, err := regexp.Compile(body.Value); err == nil && !strings.ContainsAny(body.Value, "\r\n") {
+			hc.ExpectBody = body.Value
+		} else {
+			convtypes.Reject(c.logger, "ignoring invalid health check expected body on %s: %s", body.Source, body.Value)
+		}
+	}
 }
 
 func (c *updater) buildBackendHeaders(d *backData) {
@@ -696,6 +1221,85 @@ func (c *updater) buildBackendLimit(d *backData) {
 	d.backend.Limit.Whitelist = c.splitCIDR(d.mapper.Get(ingtypes.BackLimitWhitelist))
 }
 
+var (
+	rateLimitKeyNameRegex  = regexp.MustCompile(`^[A-Za-z0-9!#$%&'*+.^_|~-]+$`)
+	rateLimitJWTClaimRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+)
+
+func (c *updater) buildBackendRateLimit(d *backData) {
+	for _, path := range d.backend.Paths {
+		config := d.mapper.GetConfig(path.Link)
+		requests := config.Get(ingtypes.BackLimitRequests)
+		if requests.Int() <= 0 {
+			if requests.Value != "" && requests.Value != "0" {
+				convtypes.Reject(c.logger, "ignoring invalid request limit on %v: %s", requests.Source, requests.Value)
+			}
+			continue
+		}
+		window := c.validateTime(config.Get(ingtypes.BackLimitRequestsWindow))
+		if window == "" {
+			continue
+		}
+		keyCfg := config.Get(ingtypes.BackLimitRequestsKey)
+		keyType, key, err := rateLimitKey(keyCfg.Value)
+		if err != nil {
+			c.logger.Warn("ignoring request limit on %v: %v", keyCfg.Source, err)
+			continue
+		}
+		retryAfter := timeToSeconds(window)
+		if retry := config.Get(ingtypes.BackLimitRequestsRetry); retry.Value != "" {
+			if retry.Int() > 0 {
+				retryAfter = retry.Int()
+			} else {
+				convtypes.Reject(c.logger, "ignoring invalid retry after on %v: %s", retry.Source, retry.Value)
+			}
+		}
+		path.RateLimit = hatypes.RateLimit{
+			Requests:   requests.Int(),
+			Window:     window,
+			Key:        key,
+			KeyType:    keyType,
+			Exempt:     c.splitCIDR(config.Get(ingtypes.BackLimitRequestsExempt)),
+			RetryAfter: retryAfter,
+		}
+	}
+}
+
+// rateLimitKey converts the key of a request limit to the stick table type
+// and the HAProxy sample expression that reads the key from a request.
+func rateLimitKey(key string) (keyType, expr string, err error) {
+	if key == "src" {
+		return "ip", "src", nil
+	}
+	source, name, _ := strings.Cut(key, ":")
+	switch source {
+	case "header", "cookie":
+		if !rateLimitKeyNameRegex.MatchString(name) {
+			return "", "", fmt.Errorf("invalid %s name: %s", source, name)
+		}
+		if source == "header" {
+			return "string", "req.hdr(" + name + ")", nil
+		}
+		return "string", "req.cook(" + name + ")", nil
+	case "jwt":
+		if !rateLimitJWTClaimRegex.MatchString(name) {
+			return "", "", fmt.Errorf("invalid JWT claim: %s", name)
+		}
+		return "string", "http_auth_bearer,jwt_payload_query('$." + name + "')", nil
+	}
+	return "", "", fmt.Errorf("unsupported key: %s", key)
+}
+
+// timeToSeconds converts a valid HAProxy time to seconds, rounding up.
+func timeToSeconds(value string) int {
+	if strings.HasSuffix(value, "d") {
+		days, _ := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		return days * 86400
+	}
+	duration, _ := time.ParseDuration(value)
+	return int((duration + time.Second - 1) / time.Second)
+}
+
 func (c *updater) buildBackendOAuth(d *backData) {
 	for _, path := range d.backend.Paths {
 		config := d.mapper.GetConfig(path.Link)
@@ -709,7 +1313,7 @@ func (c *updater) buildBackendOAuth(d *backData) {
 		path.AuthExternal.AlwaysDeny = true
 
 		if oauth.Value != "oauth2_proxy" && oauth.Value != "oauth2-proxy" {
-			c.logger.Warn("ignoring invalid oauth implementation '%s' on %v", oauth, oauth.Source)
+			convtypes.Reject(c.logger, "ignoring invalid oauth implementation '%s' on %v", oauth, oauth.Source)
 			continue
 		}
 		external := c.haproxy.Global().External
@@ -741,7 +1345,7 @@ func (c *updater) buildBackendOAuth(d *backData) {
 				continue
 			}
 			if !authHeaderRegex.MatchString(header) {
-				c.logger.Warn("invalid header format '%s' on %v", header, h.Source)
+				convtypes.Reject(c.logger, "invalid header format '%s' on %v", header, h.Source)
 				continue
 			}
 			h := strings.Split(header, ":")
@@ -792,7 +1396,7 @@ func (c *updater) buildBackendProtocol(d *backData) {
 		protocol = "h2"
 		secure = true
 	default:
-		c.logger.Warn("ignoring invalid backend protocol on %v: %s", proto.Source, proto.Value)
+		convtypes.Reject(c.logger, "ignoring invalid backend protocol on %v: %s", proto.Source, proto.Value)
 		return
 	}
 	if protocol == "h2" && !c.haproxy.Global().UseHTX {
@@ -834,7 +1438,7 @@ func (c *updater) buildBackendProtocol(d *backData) {
 			if validDomainRegex.MatchString(sni.Value) {
 				d.backend.Server.SNI = fmt.Sprintf("str(%s)", sni.Value)
 			} else {
-				c.logger.Warn("skipping invalid domain (SNI) on %v: %s", sni.Source, sni.Value)
+				convtypes.Reject(c.logger, "skipping invalid domain (SNI) on %v: %s", sni.Source, sni.Value)
 			}
 		}
 	}
@@ -842,7 +1446,7 @@ func (c *updater) buildBackendProtocol(d *backData) {
 		if validDomainRegex.MatchString(host.Value) {
 			d.backend.Server.VerifyHost = host.Value
 		} else {
-			c.logger.Warn("skipping invalid domain (verify-hostname) on %v: %s", host.Source, host.Value)
+			convtypes.Reject(c.logger, "skipping invalid domain (verify-hostname) on %v: %s", host.Source, host.Value)
 		}
 	}
 	if ca := d.mapper.Get(ingtypes.BackSecureVerifyCASecret); ca.Value != "" {
@@ -881,7 +1485,16 @@ func (c *updater) buildBackendProxyProtocol(d *backData) {
 	case "v2-ssl-cn":
 		d.backend.Server.SendProxy = "send-proxy-v2-ssl-cn"
 	default:
-		c.logger.Warn("ignoring invalid proxy protocol version on %v: %s", cfg.Source, cfg.Value)
+		convtypes.Reject(c.logger, "ignoring invalid proxy protocol version on %v: %s", cfg.Source, cfg.Value)
+	}
+}
+
+func (c *updater) buildBackendRetry(d *backData) {
+	d.backend.Retry = hatypes.BackendRetry{
+		Redispatch:     d.mapper.Get(ingtypes.BackRetryRedispatch).Value,
+		Retries:        d.mapper.Get(ingtypes.BackRetries).Value,
+		RetryOn:        utils.Split(d.mapper.Get(ingtypes.BackRetryOn).Value, ","),
+		TimeoutConnect: d.mapper.Get(ingtypes.BackRetryTimeoutConnect).Value,
 	}
 }
 
@@ -908,7 +1521,7 @@ func (c *updater) buildBackendServerNaming(d *backData) {
 	// Only warning here. d.backend.EpNaming should be updated before backend.AcquireEndpoint()
 	naming := d.mapper.Get(ingtypes.BackBackendServerNaming)
 	if !epNamingRegex.MatchString(naming.Value) {
-		c.logger.Warn("ignoring invalid naming type '%s' on %s, using 'seq' instead", naming.Value, naming.Source)
+		convtypes.Reject(c.logger, "ignoring invalid naming type '%s' on %s, using 'seq' instead", naming.Value, naming.Source)
 	}
 }
 
@@ -963,7 +1576,7 @@ func (c *updater) buildBackendSSL(d *backData) {
 	if sha2bitsVal == 0 || sha2bitsVal == 224 || sha2bitsVal == 256 || sha2bitsVal == 384 || sha2bitsVal == 512 {
 		d.backend.TLS.Sha2Bits = sha2bitsVal
 	} else if sha2bits.Source != nil {
-		c.logger.Warn("ignoring SHA-2 fingerprint on %s due to an invalid number of bits: %d", sha2bits.Source, sha2bitsVal)
+		convtypes.Reject(c.logger, "ignoring SHA-2 fingerprint on %s due to an invalid number of bits: %d", sha2bits.Source, sha2bitsVal)
 	}
 	if cfg := d.mapper.Get(ingtypes.BackSSLCiphersBackend); cfg.Source != nil {
 		d.backend.Server.Ciphers = cfg.Value
@@ -1012,6 +1625,17 @@ func (c *updater) buildBackendTimeout(d *backData) {
 	if cfg := d.mapper.Get(ingtypes.BackTimeoutTunnel); cfg.Source != nil {
 		d.backend.Timeout.Tunnel = c.validateTime(cfg)
 	}
+	if retryConnect := d.backend.Retry.TimeoutConnect; retryConnect != "" {
+		// haproxy waits min(timeout connect, 1s) before a retry to the same server,
+		// so the delay before a retry is controlled by the connect timeout
+		connect := d.mapper.Get(ingtypes.BackTimeoutConnect)
+		retryConnectDuration, _ := time.ParseDuration(retryConnect)
+		connectDuration, err := time.ParseDuration(connect.Value)
+		if err == nil && connectDuration > retryConnectDuration {
+			c.logger.Warn("timeout connect of backend '%s' lowered from '%s' to '%s' due to retry-timeout-connect", d.backend.ID, connect.Value, retryConnect)
+		}
+		d.backend.Timeout.Connect = retryConnect
+	}
 }
 
 func (c *updater) buildBackendWAF(d *backData) {
@@ -1023,13 +1647,13 @@ func (c *updater) buildBackendWAF(d *backData) {
 			continue
 		}
 		if module != "modsecurity" {
-			c.logger.Warn("ignoring invalid WAF module on %s: %s", waf.Source, module)
+			convtypes.Reject(c.logger, "ignoring invalid WAF module on %s: %s", waf.Source, module)
 			continue
 		}
 		wafMode := config.Get(ingtypes.BackWAFMode)
 		mode := wafMode.Value
 		if mode != "" && mode != "deny" && mode != "detect" {
-			c.logger.Warn("ignoring invalid WAF mode '%s' on %s, using 'deny' instead", mode, wafMode.Source)
+			convtypes.Reject(c.logger, "ignoring invalid WAF mode '%s' on %s, using 'deny' instead", mode, wafMode.Source)
 			mode = "deny"
 		}
 		path.WAF.Module = module
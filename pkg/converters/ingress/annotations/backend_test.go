@@ -17,7 +17,16 @@ limitations under the License.
 package annotations
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
@@ -29,6 +38,7 @@ import (
 	conv_helper "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/helper_test"
 	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
 	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
 )
 
 func TestAffinity(t *testing.T) {
@@ -595,6 +605,135 @@ func TestAuthExternal(t *testing.T) {
 	}
 }
 
+func TestAuthAPIKey(t *testing.T) {
+	testCase := []struct {
+		paths      []string
+		ann        map[string]map[string]string
+		secrets    conv_helper.SecretContent
+		expAPIKeys []*hatypes.APIKeyMap
+		expConfig  map[string]hatypes.AuthAPIKey
+		expLogging string
+	}{
+		// 0
+		{
+			ann: map[string]map[string]string{},
+		},
+		// 1
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthAPIKeySecret: "keys",
+				},
+			},
+			expAPIKeys: []*hatypes.APIKeyMap{{Name: "default_keys", Keys: map[string]string{}}},
+			expConfig: map[string]hatypes.AuthAPIKey{
+				"/": {Header: "X-Api-Key", MapName: "default_keys"},
+			},
+			expLogging: `
+ERROR error reading API keys on ingress 'default/ing1': secret not found: 'default/keys'
+WARN API key list on ingress 'default/ing1' is empty, all requests will be denied`,
+		},
+		// 2
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthAPIKeySecret: "keys",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/keys": {
+				"team-a": []byte("key1\n"),
+				"team-b": []byte("key 2"),
+				"team-c": []byte("key1"),
+				"team-d": []byte("key3"),
+			}},
+			expAPIKeys: []*hatypes.APIKeyMap{{Name: "default_keys", Keys: map[string]string{
+				"key1": "team-a",
+				"key3": "team-d",
+			}}},
+			expConfig: map[string]hatypes.AuthAPIKey{
+				"/": {Header: "X-Api-Key", MapName: "default_keys"},
+			},
+			expLogging: `
+WARN ignoring invalid API key 'team-b' of secret 'keys' on ingress 'default/ing1'
+WARN ignoring API key 'team-c' of secret 'keys' on ingress 'default/ing1': key already used by 'team-a'`,
+		},
+		// 3
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthAPIKeySecret: "keys",
+					ingtypes.BackAuthAPIKeyHeader: "X Key",
+				},
+			},
+			expLogging: `
+WARN ignoring API key authentication on ingress 'default/ing1': invalid header name: X Key`,
+		},
+		// 4
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthAPIKeySecret: "keys",
+					ingtypes.BackAuthAPIKeyQuery:  "key&x",
+				},
+			},
+			expLogging: `
+WARN ignoring API key authentication on ingress 'default/ing1': invalid query parameter: key&x`,
+		},
+		// 5
+		{
+			paths: []string{"/", "/api", "/app"},
+			ann: map[string]map[string]string{
+				"/api": {
+					ingtypes.BackAuthAPIKeySecret:      "keys2,ns2/keys1,keys2",
+					ingtypes.BackAuthAPIKeyQuery:       "api_key",
+					ingtypes.BackAuthAPIKeyLabelHeader: "X-Client",
+				},
+				"/app": {
+					ingtypes.BackAuthAPIKeySecret: "ns2/keys1,keys2",
+					ingtypes.BackAuthAPIKeyHeader: "X-Key",
+					ingtypes.BackAuthAPIKeyQuery:  "api_key",
+				},
+			},
+			secrets: conv_helper.SecretContent{
+				"ns2/keys1":     {"app1": []byte("key1")},
+				"default/keys2": {"app2": []byte("key2")},
+			},
+			expAPIKeys: []*hatypes.APIKeyMap{{Name: "default_keys2__ns2_keys1", Keys: map[string]string{
+				"key1": "app1",
+				"key2": "app2",
+			}}},
+			expConfig: map[string]hatypes.AuthAPIKey{
+				"/":    {},
+				"/api": {LabelHeader: "X-Client", MapName: "default_keys2__ns2_keys1", Query: "api_key"},
+				"/app": {Header: "X-Key", MapName: "default_keys2__ns2_keys1", Query: "api_key"},
+			},
+		},
+	}
+
+	source := &Source{
+		Namespace: "default",
+		Name:      "ing1",
+		Type:      "ingress",
+	}
+	for i, test := range testCase {
+		c := setup(t)
+		u := c.createUpdater()
+		c.cache.SecretContent = test.secrets
+		d := c.createBackendMappingData("default/app", source, nil, test.ann, test.paths)
+		u.buildBackendAuthAPIKey(d)
+		c.compareObjects("api keys", i, d.backend.APIKeys, test.expAPIKeys)
+		if test.expConfig != nil {
+			actual := map[string]hatypes.AuthAPIKey{}
+			for _, path := range d.backend.Paths {
+				actual[path.Path()] = path.AuthAPIKey
+			}
+			c.compareObjects("auth api key", i, actual, test.expConfig)
+		}
+		c.logger.CompareLogging(test.expLogging)
+		c.teardown()
+	}
+}
+
 func TestAuthHTTP(t *testing.T) {
 	testCase := []struct {
 		paths        []string
@@ -756,6 +895,205 @@ usr2::clearpwd2`)}},
 	}
 }
 
+func TestAuthJWT(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pubPEM := func(pub crypto.PublicKey) string {
+		der, _ := x509.MarshalPKIXPublicKey(pub)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+	b64 := func(b []byte) string {
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	rsaPEM := pubPEM(&rsaKey.PublicKey)
+	ecPEM := pubPEM(&ecKey.PublicKey)
+	jwks := fmt.Sprintf(`{"keys":[
+{"kty":"RSA","kid":"k1","alg":"RS512","n":"%s","e":"%s"},
+{"kty":"EC","kid":"k2","crv":"P-256","x":"%s","y":"%s"},
+{"kty":"RSA","kid":"k3","use":"enc","n":"%s","e":"%s"},
+{"kty":"oct","kid":"k4","k":"c2VjcmV0"}
+]}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()),
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()))
+
+	testCase := []struct {
+		paths      []string
+		ann        map[string]map[string]string
+		secrets    conv_helper.SecretContent
+		expConfig  map[string]hatypes.AuthJWT
+		expLogging string
+	}{
+		// 0
+		{
+			ann: map[string]map[string]string{},
+		},
+		// 1
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret: "jwt",
+				},
+			},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {AlwaysDeny: true},
+			},
+			expLogging: "ERROR error reading JWT keys on ingress 'default/ing1': secret not found: 'default/jwt'",
+		},
+		// 2
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret: "jwt",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/jwt": {"jwt.key": []byte(rsaPEM)}},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {Keys: []hatypes.JWTKey{{Algorithm: "RS256", Key: rsaPEM}}},
+			},
+		},
+		// 3
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret:    "jwt",
+					ingtypes.BackAuthJWTAlgorithm: "PS384",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/jwt": {"jwt.key": []byte(rsaPEM + ecPEM)}},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {Keys: []hatypes.JWTKey{{Algorithm: "PS384", Key: rsaPEM}}},
+			},
+			expLogging: `
+WARN ignoring JWT key on ingress 'default/ing1': unsupported algorithm 'PS384' of key '#2'`,
+		},
+		// 4
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret: "jwt",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/jwt": {"jwt.key": []byte(rsaPEM + ecPEM)}},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {Keys: []hatypes.JWTKey{
+					{Algorithm: "RS256", Key: rsaPEM},
+					{Algorithm: "ES256", Key: ecPEM},
+				}},
+			},
+		},
+		// 5
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret: "jwt",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/jwt": {"jwt.key": []byte(jwks)}},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {Keys: []hatypes.JWTKey{
+					{Algorithm: "RS512", ID: "k1", Key: rsaPEM},
+					{Algorithm: "ES256", ID: "k2", Key: ecPEM},
+				}},
+			},
+			expLogging: `
+WARN ignoring JWT key on ingress 'default/ing1': unsupported key type 'oct' of key 'k4'`,
+		},
+		// 6
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret: "jwt",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/jwt": {"jwt.key": []byte("invalid")}},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {AlwaysDeny: true},
+			},
+			expLogging: `
+WARN JWT key list on ingress 'default/ing1' is empty, all requests will be denied`,
+		},
+		// 7
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret:    "jwt",
+					ingtypes.BackAuthJWTAlgorithm: "HS256",
+				},
+			},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {},
+			},
+			expLogging: `
+WARN ignoring JWT authentication on ingress 'default/ing1': unsupported algorithm: HS256`,
+		},
+		// 8
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackAuthJWTSecret: "jwt",
+					ingtypes.BackAuthJWTIssuer: "https://issuer.local/ a",
+				},
+			},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {},
+			},
+			expLogging: `
+WARN ignoring JWT authentication on ingress 'default/ing1': invalid issuer: https://issuer.local/ a`,
+		},
+		// 9
+		{
+			paths: []string{"/", "/api"},
+			ann: map[string]map[string]string{
+				"/api": {
+					ingtypes.BackAuthJWTSecret:       "jwt",
+					ingtypes.BackAuthJWTIssuer:       "https://issuer.local/",
+					ingtypes.BackAuthJWTAudience:     "api",
+					ingtypes.BackAuthJWTClaimHeaders: "sub:X-User, realm.role:X-Role, email, name:X User",
+				},
+			},
+			secrets: conv_helper.SecretContent{"default/jwt": {"jwt.key": []byte(ecPEM)}},
+			expConfig: map[string]hatypes.AuthJWT{
+				"/": {},
+				"/api": {
+					Audience: "api",
+					ClaimHeaders: []hatypes.JWTClaimHeader{
+						{Claim: "sub", Header: "X-User"},
+						{Claim: "realm.role", Header: "X-Role"},
+					},
+					Issuer: "https://issuer.local/",
+					Keys:   []hatypes.JWTKey{{Algorithm: "ES256", Key: ecPEM}},
+				},
+			},
+			expLogging: `
+WARN ignoring invalid JWT claim header on ingress 'default/ing1': email
+WARN ignoring invalid JWT claim header on ingress 'default/ing1': name:X User`,
+		},
+	}
+
+	source := &Source{
+		Namespace: "default",
+		Name:      "ing1",
+		Type:      "ingress",
+	}
+	for i, test := range testCase {
+		c := setup(t)
+		u := c.createUpdater()
+		c.cache.SecretContent = test.secrets
+		d := c.createBackendMappingData("default/app", source, nil, test.ann, test.paths)
+		u.buildBackendAuthJWT(d)
+		if test.expConfig != nil {
+			actual := map[string]hatypes.AuthJWT{}
+			for _, path := range d.backend.Paths {
+				actual[path.Path()] = path.AuthJWT
+			}
+			c.compareObjects("auth jwt", i, actual, test.expConfig)
+		}
+		c.logger.CompareLogging(test.expLogging)
+		c.teardown()
+	}
+}
+
 func TestBlueGreen(t *testing.T) {
 	buildPod := func(labels string) *api.Pod {
 		l := make(map[string]string)
@@ -1257,17 +1595,17 @@ const (
 
 var corsDefaultOrigin = []string{"*"}
 
-func TestCors(t *testing.T) {
+func TestCache(t *testing.T) {
 	testCases := []struct {
-		paths    []string
 		ann      map[string]map[string]string
-		expected map[string]hatypes.Cors
+		paths    []string
+		expected map[string]hatypes.Cache
 		logging  string
 	}{
 		// 0
 		{
 			paths: []string{"/"},
-			expected: map[string]hatypes.Cors{
+			expected: map[string]hatypes.Cache{
 				"/": {},
 			},
 		},
@@ -1275,41 +1613,30 @@ func TestCors(t *testing.T) {
 		{
 			ann: map[string]map[string]string{
 				"/": {
-					ingtypes.BackCorsEnable: "true",
+					ingtypes.BackCacheEnable: "false",
+					ingtypes.BackCacheVary:   "Origin",
 				},
+				"/api": {ingtypes.BackCacheEnable: "true"},
 			},
-			expected: map[string]hatypes.Cors{
-				"/": {
-					Enabled:          true,
-					AllowCredentials: false,
-					AllowHeaders:     corsDefaultHeaders,
-					AllowMethods:     corsDefaultMethods,
-					AllowOrigin:      corsDefaultOrigin,
-					ExposeHeaders:    "",
-					MaxAge:           corsDefaultMaxAge,
-				},
+			expected: map[string]hatypes.Cache{
+				"/":    {},
+				"/api": {Enabled: true},
 			},
 		},
 		// 2
 		{
 			ann: map[string]map[string]string{
 				"/": {
-					ingtypes.BackCorsEnable: "false",
-				},
-				"/sub": {
-					ingtypes.BackCorsEnable: "true",
+					ingtypes.BackCacheEnable: "true",
+					ingtypes.BackCacheBypass: "header:Authorization, cookie:session,query:nocache",
+					ingtypes.BackCacheVary:   "accept-encoding,Origin",
 				},
 			},
-			expected: map[string]hatypes.Cors{
-				"/": {},
-				"/sub": {
-					Enabled:          true,
-					AllowCredentials: false,
-					AllowHeaders:     corsDefaultHeaders,
-					AllowMethods:     corsDefaultMethods,
-					AllowOrigin:      corsDefaultOrigin,
-					ExposeHeaders:    "",
-					MaxAge:           corsDefaultMaxAge,
+			expected: map[string]hatypes.Cache{
+				"/": {
+					Enabled: true,
+					Bypass:  []string{"req.hdr(Authorization)", "req.cook(session)", "url_param(nocache)"},
+					Vary:    []string{"Accept-Encoding", "Origin"},
 				},
 			},
 		},
@@ -1317,23 +1644,274 @@ func TestCors(t *testing.T) {
 		{
 			ann: map[string]map[string]string{
 				"/": {
-					ingtypes.BackCorsEnable:      "true",
-					ingtypes.BackCorsAllowOrigin: "http://d1.local,https://d2.local,https://d3.local",
+					ingtypes.BackCacheEnable: "true",
+					ingtypes.BackCacheBypass: "header:X Bypass,src,query:nocache",
+					ingtypes.BackCacheVary:   "User-Agent,Referer",
 				},
 			},
-			expected: map[string]hatypes.Cors{
+			expected: map[string]hatypes.Cache{
 				"/": {
-					Enabled:          true,
-					AllowCredentials: false,
-					AllowHeaders:     corsDefaultHeaders,
-					AllowMethods:     corsDefaultMethods,
-					AllowOrigin:      []string{"http://d1.local", "https://d2.local", "https://d3.local"},
-					ExposeHeaders:    "",
-					MaxAge:           corsDefaultMaxAge,
+					Enabled: true,
+					Bypass:  []string{"url_param(nocache)"},
+					Vary:    []string{"Referer"},
 				},
 			},
+			logging: `
+WARN ignoring cache bypass rule on ingress 'default/ing1': invalid header name: X Bypass
+WARN ignoring cache bypass rule on ingress 'default/ing1': unsupported rule: src
+WARN ignoring unsupported cache vary header on ingress 'default/ing1': User-Agent`,
 		},
-		// 4
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendMappingData("default/app", source, map[string]string{}, test.ann, test.paths)
+		c.createUpdater().buildBackendCache(d)
+		actual := map[string]hatypes.Cache{}
+		for _, path := range d.backend.Paths {
+			actual[path.Path()] = path.Cache
+		}
+		c.compareObjects("cache", i, actual, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
+func TestCompression(t *testing.T) {
+	defaults := map[string]string{
+		ingtypes.BackCompressionAlgo:  "gzip",
+		ingtypes.BackCompressionTypes: "text/html,application/json",
+	}
+	testCases := []struct {
+		ann         map[string]map[string]string
+		paths       []string
+		version     utils.HAProxyVersion
+		expected    map[string]hatypes.Compression
+		expectedCfg hatypes.BackendCompression
+		logging     string
+	}{
+		// 0
+		{
+			paths: []string{"/"},
+			expected: map[string]hatypes.Compression{
+				"/": {},
+			},
+		},
+		// 1
+		{
+			ann: map[string]map[string]string{
+				"/":    {ingtypes.BackCompressionEnable: "true"},
+				"/api": {ingtypes.BackCompressionEnable: "true"},
+			},
+			expected: map[string]hatypes.Compression{
+				"/":    {Enabled: true},
+				"/api": {Enabled: true},
+			},
+			expectedCfg: hatypes.BackendCompression{
+				Algorithms: []string{"gzip"},
+				Types:      []string{"text/html", "application/json"},
+			},
+		},
+		// 2
+		{
+			ann: map[string]map[string]string{
+				"/": {ingtypes.BackCompressionEnable: "true"},
+			},
+			paths: []string{"/api"},
+			expected: map[string]hatypes.Compression{
+				"/":    {Enabled: true},
+				"/api": {},
+			},
+			expectedCfg: hatypes.BackendCompression{
+				Algorithms: []string{"gzip"},
+				Types:      []string{"text/html", "application/json"},
+			},
+		},
+		// 3
+		{
+			ann: map[string]map[string]string{
+				"/":    {ingtypes.BackCompressionEnable: "true"},
+				"/api": {ingtypes.BackCompressionEnable: "false"},
+			},
+			expected: map[string]hatypes.Compression{
+				"/":    {Enabled: true},
+				"/api": {},
+			},
+			expectedCfg: hatypes.BackendCompression{
+				Algorithms: []string{"gzip"},
+				Types:      []string{"text/html", "application/json"},
+			},
+		},
+		// 4
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCompressionEnable:  "true",
+					ingtypes.BackCompressionMinSize: "1024",
+					ingtypes.BackCompressionAlgo:    "deflate,gzip",
+					ingtypes.BackCompressionTypes:   "text/*",
+					ingtypes.BackCompressionOffload: "true",
+				},
+			},
+			version: utils.HAProxyVersion{Major: 3, Minor: 2},
+			expected: map[string]hatypes.Compression{
+				"/": {Enabled: true},
+			},
+			expectedCfg: hatypes.BackendCompression{
+				Algorithms: []string{"deflate", "gzip"},
+				MinSize:    1024,
+				Offload:    true,
+				Types:      []string{"text/*"},
+			},
+		},
+		// 5
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCompressionEnable:  "true",
+					ingtypes.BackCompressionMinSize: "-1",
+					ingtypes.BackCompressionAlgo:    "br",
+					ingtypes.BackCompressionTypes:   "text/html,json",
+				},
+			},
+			expected: map[string]hatypes.Compression{
+				"/": {Enabled: true},
+			},
+			expectedCfg: hatypes.BackendCompression{
+				Algorithms: []string{"gzip"},
+				Types:      []string{"text/html"},
+			},
+			logging: `
+WARN ignoring invalid compression min size on ingress 'default/ing1': -1
+WARN ignoring unsupported compression algorithm on ingress 'default/ing1': br
+WARN ignoring invalid compression type on ingress 'default/ing1': json`,
+		},
+		// 6
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCompressionMinSize: "1024",
+					ingtypes.BackCompressionOffload: "true",
+				},
+			},
+			expected: map[string]hatypes.Compression{
+				"/": {},
+			},
+		},
+		// 7
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCompressionEnable:  "true",
+					ingtypes.BackCompressionMinSize: "1024",
+				},
+			},
+			version: utils.HAProxyVersion{Major: 3, Minor: 1},
+			expected: map[string]hatypes.Compression{
+				"/": {Enabled: true},
+			},
+			expectedCfg: hatypes.BackendCompression{
+				Algorithms: []string{"gzip"},
+				Types:      []string{"text/html", "application/json"},
+			},
+			logging: `WARN ignoring compression min size on ingress 'default/ing1': needs haproxy 3.2 or newer, found 3.1`,
+		},
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendMappingData("default/app", source, defaults, test.ann, test.paths)
+		u := c.createUpdater()
+		u.options.HAProxyVersion = test.version
+		u.buildBackendCompression(d)
+		actual := map[string]hatypes.Compression{}
+		for _, path := range d.backend.Paths {
+			actual[path.Path()] = path.Compression
+		}
+		c.compareObjects("compression", i, actual, test.expected)
+		c.compareObjects("compression config", i, d.backend.Compression, test.expectedCfg)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
+func TestCors(t *testing.T) {
+	testCases := []struct {
+		paths    []string
+		ann      map[string]map[string]string
+		expected map[string]hatypes.Cors
+		logging  string
+	}{
+		// 0
+		{
+			paths: []string{"/"},
+			expected: map[string]hatypes.Cors{
+				"/": {},
+			},
+		},
+		// 1
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCorsEnable: "true",
+				},
+			},
+			expected: map[string]hatypes.Cors{
+				"/": {
+					Enabled:          true,
+					AllowCredentials: false,
+					AllowHeaders:     corsDefaultHeaders,
+					AllowMethods:     corsDefaultMethods,
+					AllowOrigin:      corsDefaultOrigin,
+					ExposeHeaders:    "",
+					MaxAge:           corsDefaultMaxAge,
+				},
+			},
+		},
+		// 2
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCorsEnable: "false",
+				},
+				"/sub": {
+					ingtypes.BackCorsEnable: "true",
+				},
+			},
+			expected: map[string]hatypes.Cors{
+				"/": {},
+				"/sub": {
+					Enabled:          true,
+					AllowCredentials: false,
+					AllowHeaders:     corsDefaultHeaders,
+					AllowMethods:     corsDefaultMethods,
+					AllowOrigin:      corsDefaultOrigin,
+					ExposeHeaders:    "",
+					MaxAge:           corsDefaultMaxAge,
+				},
+			},
+		},
+		// 3
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackCorsEnable:      "true",
+					ingtypes.BackCorsAllowOrigin: "http://d1.local,https://d2.local,https://d3.local",
+				},
+			},
+			expected: map[string]hatypes.Cors{
+				"/": {
+					Enabled:          true,
+					AllowCredentials: false,
+					AllowHeaders:     corsDefaultHeaders,
+					AllowMethods:     corsDefaultMethods,
+					AllowOrigin:      []string{"http://d1.local", "https://d2.local", "https://d3.local"},
+					ExposeHeaders:    "",
+					MaxAge:           corsDefaultMaxAge,
+				},
+			},
+		},
+		// 4
 		{
 			ann: map[string]map[string]string{
 				"/": {
@@ -1669,6 +2247,163 @@ host: %[service].%[namespace].svc.cluster.local
 	}
 }
 
+func TestHealthCheck(t *testing.T) {
+	testCases := []struct {
+		ann      map[string]string
+		expected hatypes.HealthCheck
+		logging  string
+	}{
+		// 0
+		{},
+		// 1
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckInterval: "2s",
+				ingtypes.BackHealthCheckURI:      "/check",
+			},
+			expected: hatypes.HealthCheck{
+				Interval: "2s",
+				URI:      "/check",
+			},
+		},
+		// 2
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckErrorLimit: "5",
+				ingtypes.BackHealthCheckOnError:    "mark-down",
+			},
+		},
+		// 3
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckObserve:    "layer7",
+				ingtypes.BackHealthCheckErrorLimit: "5",
+				ingtypes.BackHealthCheckOnError:    "sudden-death",
+			},
+			expected: hatypes.HealthCheck{
+				ErrorLimit: 5,
+				Observe:    "layer7",
+				OnError:    "sudden-death",
+			},
+		},
+		// 4
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckObserve: "layer5",
+			},
+			logging: `WARN ignoring invalid health check observe mode on ingress 'default/ing1': layer5`,
+		},
+		// 5
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckObserve:    "layer4",
+				ingtypes.BackHealthCheckErrorLimit: "0",
+				ingtypes.BackHealthCheckOnError:    "eject",
+			},
+			expected: hatypes.HealthCheck{
+				Observe: "layer4",
+			},
+			logging: `
+WARN ignoring invalid health check error limit on ingress 'default/ing1': 0
+WARN ignoring invalid health check on-error action on ingress 'default/ing1': eject`,
+		},
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendData("default/app", source, test.ann, map[string]string{})
+		c.createUpdater().buildBackendHealthCheck(d)
+		c.compareObjects("health check", i, d.backend.HealthCheck, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
+func TestHealthCheckHTTP(t *testing.T) {
+	testCases := []struct {
+		ann      map[string]string
+		expected hatypes.HealthCheck
+		logging  string
+	}{
+		// 0
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckURI:       "/check",
+				ingtypes.BackHealthCheckMethod:    "head",
+				ingtypes.BackHealthCheckHost:      "app.local",
+				ingtypes.BackHealthCheckHeaders:   "X-Check: value 1\nX-Other: value2",
+				ingtypes.BackHealthCheckExpStatus: "200-299, 401",
+				ingtypes.BackHealthCheckExpBody:   "^(ok|up)$",
+			},
+			expected: hatypes.HealthCheck{
+				ExpectBody:   "^(ok|up)$",
+				ExpectStatus: "200-299,401",
+				Headers: []*hatypes.BackendHeader{
+					{Name: "X-Check", Value: "value 1"},
+					{Name: "X-Other", Value: "value2"},
+				},
+				Host:   "app.local",
+				Method: "HEAD",
+				URI:    "/check",
+			},
+		},
+		// 1
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckMethod:    "GET /",
+				ingtypes.BackHealthCheckHost:      "app local",
+				ingtypes.BackHealthCheckHeaders:   "Host: app.local",
+				ingtypes.BackHealthCheckExpStatus: "2xx",
+				ingtypes.BackHealthCheckExpBody:   "(ok",
+			},
+			logging: `
+WARN ignoring invalid health check method on ingress 'default/ing1': GET /
+WARN ignoring invalid health check host on ingress 'default/ing1': app local
+WARN ignoring host header on ingress 'default/ing1', use health-check-host instead
+WARN ignoring invalid health check expected status on ingress 'default/ing1': 2xx
+WARN ignoring invalid health check expected body on ingress 'default/ing1': (ok`,
+		},
+		// 2
+		{
+			ann: map[string]string{
+				ingtypes.BackBackendProtocol:      "grpc",
+				ingtypes.BackHealthCheckProtocol:  "grpc",
+				ingtypes.BackHealthCheckExpStatus: "200",
+			},
+			expected: hatypes.HealthCheck{
+				Protocol: "grpc",
+			},
+		},
+		// 3
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckProtocol:  "grpc",
+				ingtypes.BackHealthCheckExpStatus: "200",
+			},
+			expected: hatypes.HealthCheck{
+				ExpectStatus: "200",
+			},
+			logging: `WARN ignoring grpc health check on ingress 'default/ing1' due to backend protocol not h2`,
+		},
+		// 4
+		{
+			ann: map[string]string{
+				ingtypes.BackHealthCheckProtocol: "tcp",
+			},
+			logging: `WARN ignoring invalid health check protocol on ingress 'default/ing1': tcp`,
+		},
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendData("default/app", source, test.ann, map[string]string{})
+		c.createUpdater().buildBackendHealthCheck(d)
+		c.compareObjects("health check", i, d.backend.HealthCheck, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
 func TestHSTS(t *testing.T) {
 	testCases := []struct {
 		paths      []string
@@ -2058,6 +2793,336 @@ WARN oauth2_proxy on ingress 'default/ing1' needs Lua json module, install lua-j
 	}
 }
 
+func TestRateLimit(t *testing.T) {
+	defaults := map[string]string{
+		ingtypes.BackLimitRequestsKey:    "src",
+		ingtypes.BackLimitRequestsWindow: "1s",
+	}
+	testCases := []struct {
+		ann      map[string]map[string]string
+		paths    []string
+		expected map[string]hatypes.RateLimit
+		logging  string
+	}{
+		// 0
+		{
+			paths: []string{"/"},
+			expected: map[string]hatypes.RateLimit{
+				"/": {},
+			},
+		},
+		// 1
+		{
+			ann: map[string]map[string]string{
+				"/": {ingtypes.BackLimitRequests: "100"},
+			},
+			expected: map[string]hatypes.RateLimit{
+				"/": {Requests: 100, Window: "1s", Key: "src", KeyType: "ip", RetryAfter: 1},
+			},
+		},
+		// 2
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackLimitRequests:       "100",
+					ingtypes.BackLimitRequestsWindow: "1500ms",
+					ingtypes.BackLimitRequestsKey:    "header:X-Api-Key",
+					ingtypes.BackLimitRequestsExempt: "10.0.0.0/8,192.168.1.1",
+				},
+				"/api": {
+					ingtypes.BackLimitRequests:       "1000",
+					ingtypes.BackLimitRequestsWindow: "1m",
+					ingtypes.BackLimitRequestsKey:    "cookie:session",
+					ingtypes.BackLimitRequestsRetry:  "30",
+				},
+				"/app": {
+					ingtypes.BackLimitRequests:    "10",
+					ingtypes.BackLimitRequestsKey: "jwt:client.id",
+				},
+			},
+			expected: map[string]hatypes.RateLimit{
+				"/":    {Requests: 100, Window: "1500ms", Key: "req.hdr(X-Api-Key)", KeyType: "string", Exempt: []string{"10.0.0.0/8", "192.168.1.1"}, RetryAfter: 2},
+				"/api": {Requests: 1000, Window: "1m", Key: "req.cook(session)", KeyType: "string", RetryAfter: 30},
+				"/app": {Requests: 10, Window: "1s", Key: "http_auth_bearer,jwt_payload_query('$.client.id')", KeyType: "string", RetryAfter: 1},
+			},
+		},
+		// 3
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackLimitRequests:    "100",
+					ingtypes.BackLimitRequestsKey: "header:X Api",
+				},
+				"/api": {
+					ingtypes.BackLimitRequests:    "100",
+					ingtypes.BackLimitRequestsKey: "param:key",
+				},
+				"/app": {
+					ingtypes.BackLimitRequests: "none",
+				},
+			},
+			expected: map[string]hatypes.RateLimit{
+				"/":    {},
+				"/api": {},
+				"/app": {},
+			},
+			logging: `
+WARN ignoring request limit on ingress 'default/ing1': invalid header name: X Api
+WARN ignoring request limit on ingress 'default/ing1': unsupported key: param:key
+WARN ignoring invalid request limit on ingress 'default/ing1': none`,
+		},
+		// 4
+		{
+			ann: map[string]map[string]string{
+				"/": {
+					ingtypes.BackLimitRequests:       "100",
+					ingtypes.BackLimitRequestsWindow: "1d",
+					ingtypes.BackLimitRequestsRetry:  "-1",
+				},
+				"/api": {
+					ingtypes.BackLimitRequests:       "100",
+					ingtypes.BackLimitRequestsWindow: "10x",
+				},
+			},
+			expected: map[string]hatypes.RateLimit{
+				"/":    {Requests: 100, Window: "1d", Key: "src", KeyType: "ip", RetryAfter: 86400},
+				"/api": {},
+			},
+			logging: `
+WARN ignoring invalid retry after on ingress 'default/ing1': -1
+WARN ignoring invalid time format on ingress 'default/ing1': 10x`,
+		},
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendMappingData("default/app", source, defaults, test.ann, test.paths)
+		c.createUpdater().buildBackendRateLimit(d)
+		actual := map[string]hatypes.RateLimit{}
+		for _, path := range d.backend.Paths {
+			actual[path.Path()] = path.RateLimit
+		}
+		c.compareObjects("rate limit", i, actual, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
+func TestRetry(t *testing.T) {
+	testCases := []struct {
+		defaults map[string]string
+		ann      map[string]string
+		expected hatypes.BackendRetry
+		logging  string
+	}{
+		// 0
+		{},
+		// 1
+		{
+			ann: map[string]string{
+				ingtypes.BackRetries:         "3",
+				ingtypes.BackRetryOn:         "conn-failure, 503,response-timeout",
+				ingtypes.BackRetryRedispatch: "true",
+			},
+			expected: hatypes.BackendRetry{
+				Redispatch: "true",
+				Retries:    "3",
+				RetryOn:    []string{"conn-failure", "503", "response-timeout"},
+			},
+		},
+		// 2
+		{
+			ann: map[string]string{
+				ingtypes.BackRetries:         "0",
+				ingtypes.BackRetryOn:         "none",
+				ingtypes.BackRetryRedispatch: "-2",
+			},
+			expected: hatypes.BackendRetry{
+				Redispatch: "-2",
+				Retries:    "0",
+				RetryOn:    []string{"none"},
+			},
+		},
+		// 3
+		{
+			ann: map[string]string{
+				ingtypes.BackRetries: "-1",
+			},
+			logging: `
+WARN ignoring invalid retries on ingress 'default/ing1': -1`,
+		},
+		// 4
+		{
+			ann: map[string]string{
+				ingtypes.BackRetryOn: "none,503",
+			},
+			logging: `
+WARN ignoring invalid retry-on condition on ingress 'default/ing1': none`,
+		},
+		// 5
+		{
+			defaults: map[string]string{
+				ingtypes.BackRetries:         "2",
+				ingtypes.BackRetryOn:         "0rtt-rejected,all-retryable-errors",
+				ingtypes.BackRetryRedispatch: "false",
+			},
+			expected: hatypes.BackendRetry{
+				Redispatch: "false",
+				Retries:    "2",
+				RetryOn:    []string{"0rtt-rejected", "all-retryable-errors"},
+			},
+		},
+		// 6
+		{
+			defaults: map[string]string{
+				ingtypes.BackRetries: "2",
+				ingtypes.BackRetryOn: "503",
+			},
+			ann: map[string]string{
+				ingtypes.BackRetryOn: "conn-failure",
+			},
+			expected: hatypes.BackendRetry{
+				Retries: "2",
+				RetryOn: []string{"conn-failure"},
+			},
+		},
+		// 7
+		{
+			ann: map[string]string{
+				ingtypes.BackRetryOn: "conn-failure,400",
+			},
+			logging: `
+WARN ignoring invalid retry-on condition on ingress 'default/ing1': 400`,
+		},
+		// 8
+		{
+			ann: map[string]string{
+				ingtypes.BackRetryRedispatch: "0",
+			},
+			logging: `
+WARN ignoring invalid retry redispatch on ingress 'default/ing1': 0`,
+		},
+		// 9
+		{
+			ann: map[string]string{
+				ingtypes.BackRetries:             "3",
+				ingtypes.BackRetryTimeoutConnect: "200ms",
+			},
+			expected: hatypes.BackendRetry{
+				Retries:        "3",
+				TimeoutConnect: "200ms",
+			},
+		},
+		// 10
+		{
+			ann: map[string]string{
+				ingtypes.BackRetryTimeoutConnect: "2s",
+			},
+			logging: `
+WARN ignoring invalid retry timeout connect on ingress 'default/ing1': 2s`,
+		},
+		// 11
+		{
+			ann: map[string]string{
+				ingtypes.BackRetryTimeoutConnect: "0ms",
+			},
+			logging: `
+WARN ignoring invalid retry timeout connect on ingress 'default/ing1': 0ms`,
+		},
+		// 12
+		{
+			ann: map[string]string{
+				ingtypes.BackRetryTimeoutConnect: "100",
+			},
+			logging: `
+WARN ignoring invalid retry timeout connect on ingress 'default/ing1': 100`,
+		},
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendData("default/app", source, test.ann, test.defaults)
+		c.createUpdater().buildBackendRetry(d)
+		c.compareObjects("retry", i, d.backend.Retry, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
+func TestRetryTimeoutConnect(t *testing.T) {
+	testCases := []struct {
+		defaults map[string]string
+		ann      map[string]string
+		expected string
+		logging  string
+	}{
+		// 0
+		{
+			defaults: map[string]string{
+				ingtypes.BackTimeoutConnect: "5s",
+			},
+		},
+		// 1
+		{
+			defaults: map[string]string{
+				ingtypes.BackTimeoutConnect: "5s",
+			},
+			ann: map[string]string{
+				ingtypes.BackRetryTimeoutConnect: "200ms",
+			},
+			expected: "200ms",
+			logging: `
+WARN timeout connect of backend 'default_app_8080' lowered from '5s' to '200ms' due to retry-timeout-connect`,
+		},
+		// 2
+		{
+			defaults: map[string]string{
+				ingtypes.BackTimeoutConnect:      "5s",
+				ingtypes.BackRetryTimeoutConnect: "500ms",
+			},
+			ann: map[string]string{
+				ingtypes.BackTimeoutConnect: "2s",
+			},
+			expected: "500ms",
+			logging: `
+WARN timeout connect of backend 'default_app_8080' lowered from '2s' to '500ms' due to retry-timeout-connect`,
+		},
+		// 3
+		{
+			defaults: map[string]string{
+				ingtypes.BackTimeoutConnect: "5s",
+			},
+			ann: map[string]string{
+				ingtypes.BackTimeoutConnect:      "200ms",
+				ingtypes.BackRetryTimeoutConnect: "200ms",
+			},
+			expected: "200ms",
+		},
+		// 4
+		{
+			defaults: map[string]string{
+				ingtypes.BackTimeoutConnect: "5s",
+			},
+			ann: map[string]string{
+				ingtypes.BackTimeoutConnect:      "100ms",
+				ingtypes.BackRetryTimeoutConnect: "1s",
+			},
+			expected: "1s",
+		},
+	}
+	source := &Source{Namespace: "default", Name: "ing1", Type: "ingress"}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createBackendData("default/app", source, test.ann, test.defaults)
+		u := c.createUpdater()
+		u.buildBackendRetry(d)
+		u.buildBackendTimeout(d)
+		c.compareObjects("timeout connect", i, d.backend.Timeout.Connect, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
 func TestRewriteURL(t *testing.T) {
 	testCases := []struct {
 		source   Source
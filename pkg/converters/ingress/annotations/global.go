@@ -24,10 +24,15 @@ import (
 	"time"
 
 	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
 	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
 )
 
+// HostDelegationLabel is the namespace label whose value is a domain delegated
+// to the namespace, see the host-ownership-delegation global config key.
+const HostDelegationLabel = "haproxy-ingress.github.io/host-delegation"
+
 func (c *updater) buildGlobalAcme(d *globalData) {
 	endpoint := d.mapper.Get(ingtypes.GlobalAcmeEndpoint).Value
 	if endpoint == "" {
@@ -60,7 +65,7 @@ func (c *updater) buildGlobalAuthProxy(d *globalData) {
 	proxy := authProxyRegex.FindStringSubmatch(proxystr)
 	authproxy := &c.haproxy.Frontend().AuthProxy
 	if len(proxy) < 4 {
-		c.logger.Warn("invalid auth proxy configuration: %s", proxystr)
+		convtypes.Reject(c.logger, "invalid auth proxy configuration: %s", proxystr)
 		// start>end ensures that trying to create a frontend bind will fail
 		authproxy.RangeStart = 0
 		authproxy.RangeEnd = -1
@@ -90,13 +95,43 @@ func (c *updater) buildGlobalBind(d *globalData) {
 	}
 }
 
+func (c *updater) buildGlobalCache(d *globalData) {
+	// Enabled and PurgeMapFile are updated by the haproxy instance,
+	// since they depend on the backends and on the maps dir
+	size := d.mapper.Get(ingtypes.GlobalCacheSize)
+	totalMaxSize := size.Int()
+	if totalMaxSize <= 0 || totalMaxSize > 4095 {
+		if size.Value != "" {
+			convtypes.Reject(c.logger, "ignoring invalid cache size, using 64MB: %s", size.Value)
+		}
+		totalMaxSize = 64
+	}
+	objSize := d.mapper.Get(ingtypes.GlobalCacheMaxObjectSize)
+	maxObjectSize := objSize.Int()
+	if maxObjectSize < 0 || maxObjectSize > totalMaxSize*1024*1024/2 {
+		convtypes.Reject(c.logger, "ignoring invalid cache max object size, should be lower than half of the cache size: %s", objSize.Value)
+		maxObjectSize = 0
+	}
+	maxAge := 60
+	if age := c.validateTime(d.mapper.Get(ingtypes.GlobalCacheMaxAge)); age != "" {
+		if seconds := timeToSeconds(age); seconds > 0 {
+			maxAge = seconds
+		} else {
+			c.logger.Warn("ignoring cache max age lower than one second, using 60s: %s", age)
+		}
+	}
+	d.global.Cache.MaxAge = maxAge
+	d.global.Cache.MaxObjectSize = maxObjectSize
+	d.global.Cache.TotalMaxSize = totalMaxSize
+}
+
 func (c *updater) buildGlobalCloseSessions(d *globalData) {
 	durationCfg := d.mapper.Get(ingtypes.GlobalCloseSessionsDuration).Value
 	if durationCfg == "" {
 		return
 	}
 	if !c.options.TrackInstances {
-		c.logger.Warn("ignoring close-sessions-duration config: tracking old instances is disabled")
+		convtypes.Reject(c.logger, "ignoring close-sessions-duration config: tracking old instances is disabled")
 		return
 	}
 	timeoutCfg := d.mapper.Get(ingtypes.GlobalTimeoutStop).Value
@@ -106,7 +141,7 @@ func (c *updater) buildGlobalCloseSessions(d *globalData) {
 	}
 	timeout, err := time.ParseDuration(timeoutCfg)
 	if err != nil {
-		c.logger.Warn("ignoring close-sessions-duration due to invalid timeout-stop config: %v", err)
+		convtypes.Reject(c.logger, "ignoring close-sessions-duration due to invalid timeout-stop config: %v", err)
 		return
 	}
 	var duration time.Duration
@@ -125,7 +160,7 @@ func (c *updater) buildGlobalCloseSessions(d *globalData) {
 			}
 		}
 		if err != nil {
-			c.logger.Warn("ignoring invalid close-sessions-duration config: %v", err)
+			convtypes.Reject(c.logger, "ignoring invalid close-sessions-duration config: %v", err)
 			return
 		}
 	}
@@ -149,7 +184,7 @@ func (c *updater) buildGlobalPathTypeOrder(d *globalData) {
 			order[i] = match
 			delete(matchTypes, match)
 		} else {
-			c.logger.Warn("invalid or duplicated path type '%s', using default order %v", matchStr, hatypes.DefaultMatchOrder)
+			convtypes.Reject(c.logger, "invalid or duplicated path type '%s', using default order %v", matchStr, hatypes.DefaultMatchOrder)
 			return
 		}
 	}
@@ -163,7 +198,7 @@ func (c *updater) buildGlobalPathTypeOrder(d *globalData) {
 func (c *updater) buildGlobalProc(d *globalData) {
 	balance := d.mapper.Get(ingtypes.GlobalNbprocBalance).Int()
 	if balance < 1 {
-		c.logger.Warn("invalid value of nbproc-balance configmap option (%v), using 1", balance)
+		convtypes.Reject(c.logger, "invalid value of nbproc-balance configmap option (%v), using 1", balance)
 		balance = 1
 	}
 	if balance > 1 {
@@ -174,7 +209,7 @@ func (c *updater) buildGlobalProc(d *globalData) {
 	}
 	ssl := d.mapper.Get(ingtypes.GlobalNbprocSSL).Int()
 	if ssl < 0 {
-		c.logger.Warn("invalid value of nbproc-ssl configmap option (%v), using 0", ssl)
+		convtypes.Reject(c.logger, "invalid value of nbproc-ssl configmap option (%v), using 0", ssl)
 		ssl = 0
 	}
 	if ssl > 0 {
@@ -184,7 +219,7 @@ func (c *updater) buildGlobalProc(d *globalData) {
 	procs := balance + ssl
 	threads := d.mapper.Get(ingtypes.GlobalNbthread).Int()
 	if threads < 0 {
-		c.logger.Warn("ignoring invalid value of nbthread: %d", threads)
+		convtypes.Reject(c.logger, "ignoring invalid value of nbthread: %d", threads)
 		threads = 0
 	}
 	bindprocBalance := "1"
@@ -222,6 +257,17 @@ func (c *updater) buildGlobalProc(d *globalData) {
 	d.global.Procs.CPUMap = cpumap
 }
 
+func (c *updater) buildGlobalPeers(d *globalData) {
+	// peer servers are updated by the converter on every sync,
+	// since they depend on the controller pods and not on the config
+	port := d.mapper.Get(ingtypes.GlobalPeersPort).Int()
+	if port < 0 || port > 65535 {
+		convtypes.Reject(c.logger, "ignoring invalid peers port: %d", port)
+		return
+	}
+	d.global.Peers.Port = port
+}
+
 func (c *updater) buildGlobalStats(d *globalData) {
 	// healthz
 	d.global.Healthz.BindIP = d.mapper.Get(ingtypes.GlobalBindIPAddrHealthz).Value
@@ -286,7 +332,7 @@ func (c *updater) buildSecurity(d *globalData) {
 		if username == "" {
 			username, groupname = haproxy, haproxy
 		} else if username != haproxy || groupname != haproxy {
-			c.logger.Warn("username and groupname are already defined as '%s' and '%s', ignoring '%s' config", username, groupname, ingtypes.GlobalUseHAProxyUser)
+			convtypes.Reject(c.logger, "username and groupname are already defined as '%s' and '%s', ignoring '%s' config", username, groupname, ingtypes.GlobalUseHAProxyUser)
 		}
 	}
 	d.global.Security.Username = username
@@ -401,12 +447,80 @@ func (c *updater) buildGlobalDynamic(d *globalData) {
 		staticSecrets || c.validateAllowDeny(d, ingtypes.GlobalCrossNamespaceSecretsCA)
 	c.options.DynamicConfig.CrossNamespaceSecretCertificate =
 		staticSecrets || c.validateAllowDeny(d, ingtypes.GlobalCrossNamespaceSecretsCrt)
+	c.options.DynamicConfig.CrossNamespaceSecretJWT =
+		staticSecrets || c.validateAllowDeny(d, ingtypes.GlobalCrossNamespaceSecretsJWT)
 	c.options.DynamicConfig.CrossNamespaceSecretPasswd =
 		staticSecrets || c.validateAllowDeny(d, ingtypes.GlobalCrossNamespaceSecretsPasswd)
 
 	// Services
 	c.options.DynamicConfig.CrossNamespaceServices =
 		c.validateAllowDeny(d, ingtypes.GlobalCrossNamespaceServices)
+
+	// Hostname ownership
+	c.options.DynamicConfig.HostOwnership = d.mapper.Get(ingtypes.GlobalHostOwnership).Bool()
+	c.options.DynamicConfig.HostOwnershipDelegation = c.readHostDelegation(d)
+
+	// Namespace annotation policies
+	c.options.DynamicConfig.NamespaceAnnotationsAllow = c.readNameListMap(d, ingtypes.GlobalNamespaceAnnotationsAllow)
+	c.options.DynamicConfig.NamespaceAnnotationsDeny = c.readNameListMap(d, ingtypes.GlobalNamespaceAnnotationsDeny)
+}
+
+// readHostDelegation reads the domains delegated by the global config, and by
+// the HostDelegationLabel of the namespaces if namespace labels are watched.
+func (c *updater) readHostDelegation(d *globalData) map[string][]string {
+	var delegation map[string][]string
+	delegate := func(domain string, namespaces ...string) {
+		if delegation == nil {
+			delegation = map[string][]string{}
+		}
+		domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+		delegation[domain] = append(delegation[domain], namespaces...)
+	}
+	for domain, namespaces := range c.readNameListMap(d, ingtypes.GlobalHostOwnershipDelegation) {
+		delegate(domain, namespaces...)
+	}
+	if !c.options.NamespaceLabels || !c.options.DynamicConfig.HostOwnership {
+		return delegation
+	}
+	nsList, err := c.cache.GetNamespaceList()
+	if err != nil {
+		c.logger.Warn("ignoring host delegation of namespace labels: %v", err)
+		return delegation
+	}
+	for _, ns := range nsList {
+		if domain := ns.Labels[HostDelegationLabel]; domain != "" {
+			delegate(domain, ns.Name)
+		}
+	}
+	return delegation
+}
+
+// readNameListMap reads a multiline config key, one `name=item1,item2,...` per line.
+func (c *updater) readNameListMap(d *globalData, key string) map[string][]string {
+	config := d.mapper.Get(key).Value
+	if config == "" {
+		return nil
+	}
+	nameList := map[string][]string{}
+	for _, line := range utils.LineToSlice(config) {
+		if line == "" {
+			continue
+		}
+		lineData := strings.Split(line, "=")
+		name := strings.TrimSpace(lineData[0])
+		if len(lineData) != 2 || name == "" {
+			c.logger.Warn("ignoring misconfigured line on global '%s': %s", key, line)
+			continue
+		}
+		var items []string
+		for _, item := range strings.Split(lineData[1], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		nameList[name] = items
+	}
+	return nameList
 }
 
 var forwardRegex = regexp.MustCompile(`^(add|update|ignore|ifmissing)$`)
@@ -418,7 +532,7 @@ func (c *updater) buildGlobalForwardFor(d *globalData) {
 	validateString := func(regex *regexp.Regexp, key, defaultValue string) string {
 		value := d.mapper.Get(key).Value
 		if !regex.MatchString(value) {
-			c.logger.Warn("Invalid %s value option on ConfigMap: '%s'. Using '%s' instead", key, value, defaultValue)
+			convtypes.Reject(c.logger, "Invalid %s value option on ConfigMap: '%s'. Using '%s' instead", key, value, defaultValue)
 			return defaultValue
 		}
 		return value
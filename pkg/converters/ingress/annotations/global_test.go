@@ -23,6 +23,9 @@ import (
 	"testing"
 	"time"
 
+	api "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
 	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
 	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
@@ -152,6 +155,57 @@ func TestBind(t *testing.T) {
 	}
 }
 
+func TestGlobalCache(t *testing.T) {
+	testCases := []struct {
+		ann      map[string]string
+		expected hatypes.CacheConfig
+		logging  string
+	}{
+		// 0
+		{
+			ann:      map[string]string{},
+			expected: hatypes.CacheConfig{TotalMaxSize: 64, MaxAge: 60},
+		},
+		// 1
+		{
+			ann: map[string]string{
+				ingtypes.GlobalCacheSize:          "256",
+				ingtypes.GlobalCacheMaxObjectSize: "1048576",
+				ingtypes.GlobalCacheMaxAge:        "10m",
+			},
+			expected: hatypes.CacheConfig{TotalMaxSize: 256, MaxObjectSize: 1048576, MaxAge: 600},
+		},
+		// 2
+		{
+			ann: map[string]string{
+				ingtypes.GlobalCacheSize:          "5000",
+				ingtypes.GlobalCacheMaxObjectSize: "40000000",
+				ingtypes.GlobalCacheMaxAge:        "1x",
+			},
+			expected: hatypes.CacheConfig{TotalMaxSize: 64, MaxAge: 60},
+			logging: `
+WARN ignoring invalid cache size, using 64MB: 5000
+WARN ignoring invalid cache max object size, should be lower than half of the cache size: 40000000
+WARN ignoring invalid time format on global/default config: 1x`,
+		},
+		// 3
+		{
+			ann: map[string]string{
+				ingtypes.GlobalCacheMaxAge: "500ms",
+			},
+			expected: hatypes.CacheConfig{TotalMaxSize: 64, MaxAge: 1},
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createGlobalData(test.ann)
+		c.createUpdater().buildGlobalCache(d)
+		c.compareObjects("cache", i, d.global.Cache, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
 func TestCloseSessions(t *testing.T) {
 	testCases := []struct {
 		annDuration string
@@ -473,6 +527,7 @@ func TestDynamic(t *testing.T) {
 	testCases := []struct {
 		config        map[string]string
 		staticSecrets bool
+		nsLabels      bool
 		expected      convtypes.DynamicConfig
 		logging       string
 	}{
@@ -513,12 +568,14 @@ WARN ignoring invalid value 'fail' on global 'cross-namespace-secrets-crt', usin
 			config: map[string]string{
 				ingtypes.GlobalCrossNamespaceSecretsCA:     "allow",
 				ingtypes.GlobalCrossNamespaceSecretsCrt:    "allow",
+				ingtypes.GlobalCrossNamespaceSecretsJWT:    "allow",
 				ingtypes.GlobalCrossNamespaceSecretsPasswd: "allow",
 				ingtypes.GlobalCrossNamespaceServices:      "allow",
 			},
 			expected: convtypes.DynamicConfig{
 				CrossNamespaceSecretCA:          true,
 				CrossNamespaceSecretCertificate: true,
+				CrossNamespaceSecretJWT:         true,
 				CrossNamespaceSecretPasswd:      true,
 				CrossNamespaceServices:          true,
 			},
@@ -532,16 +589,116 @@ WARN ignoring invalid value 'fail' on global 'cross-namespace-secrets-crt', usin
 			expected: convtypes.DynamicConfig{
 				CrossNamespaceSecretCA:          true,
 				CrossNamespaceSecretCertificate: true,
+				CrossNamespaceSecretJWT:         true,
 				CrossNamespaceSecretPasswd:      true,
 				StaticCrossNamespaceSecrets:     true,
 			},
 		},
+		// 5
+		{
+			config: map[string]string{
+				ingtypes.GlobalHostOwnership: "true",
+			},
+			expected: convtypes.DynamicConfig{
+				HostOwnership: true,
+			},
+		},
+		// 6
+		{
+			config: map[string]string{
+				ingtypes.GlobalHostOwnership: "true",
+				ingtypes.GlobalHostOwnershipDelegation: `
+*.App.local=team1
+api.app.local=team2, team3
+invalid
+=team4
+`,
+			},
+			expected: convtypes.DynamicConfig{
+				HostOwnership: true,
+				HostOwnershipDelegation: map[string][]string{
+					"app.local":     {"team1"},
+					"api.app.local": {"team2", "team3"},
+				},
+			},
+			logging: `
+WARN ignoring misconfigured line on global 'host-ownership-delegation': invalid
+WARN ignoring misconfigured line on global 'host-ownership-delegation': =team4
+`,
+		},
+		// 7
+		{
+			config: map[string]string{
+				ingtypes.GlobalHostOwnership: "true",
+				ingtypes.GlobalHostOwnershipDelegation: `
+app.local=team1
+`,
+			},
+			expected: convtypes.DynamicConfig{
+				HostOwnership: true,
+				HostOwnershipDelegation: map[string][]string{
+					"app.local": {"team1"},
+				},
+			},
+		},
+		// 8
+		{
+			config: map[string]string{
+				ingtypes.GlobalHostOwnership: "true",
+				ingtypes.GlobalHostOwnershipDelegation: `
+app.local=team1
+`,
+			},
+			nsLabels: true,
+			expected: convtypes.DynamicConfig{
+				HostOwnership: true,
+				HostOwnershipDelegation: map[string][]string{
+					"app.local":     {"team1", "team2"},
+					"api.app.local": {"team3"},
+				},
+			},
+		},
+		// 9
+		{
+			config: map[string]string{
+				ingtypes.GlobalHostOwnership: "false",
+			},
+			nsLabels: true,
+			expected: convtypes.DynamicConfig{},
+		},
+		// 10
+		{
+			config: map[string]string{
+				ingtypes.GlobalNamespaceAnnotationsAllow: `
+restricted=path-type,timeout-*
+`,
+				ingtypes.GlobalNamespaceAnnotationsDeny: `
+tenants=config-*,auth-tls-*, ssl-passthrough
+restricted=config-backend
+`,
+			},
+			expected: convtypes.DynamicConfig{
+				NamespaceAnnotationsAllow: map[string][]string{
+					"restricted": {"path-type", "timeout-*"},
+				},
+				NamespaceAnnotationsDeny: map[string][]string{
+					"tenants":    {"config-*", "auth-tls-*", "ssl-passthrough"},
+					"restricted": {"config-backend"},
+				},
+			},
+		},
 	}
 	for i, test := range testCases {
 		c := setup(t)
 		d := c.createGlobalData(test.config)
 		u := c.createUpdater()
 		u.options.DynamicConfig.StaticCrossNamespaceSecrets = test.staticSecrets
+		u.options.NamespaceLabels = test.nsLabels
+		c.cache.NsList = map[string]*api.Namespace{
+			"default": {ObjectMeta: meta.ObjectMeta{Name: "default"}},
+			"team2":   {ObjectMeta: meta.ObjectMeta{Name: "team2", Labels: map[string]string{HostDelegationLabel: "App.local"}}},
+			"team3":   {ObjectMeta: meta.ObjectMeta{Name: "team3", Labels: map[string]string{HostDelegationLabel: "api.app.local"}}},
+		}
 		u.buildGlobalDynamic(d)
 		if !reflect.DeepEqual(*u.options.DynamicConfig, test.expected) {
 			c.compareObjects("dynamic", i, *u.options.DynamicConfig, test.expected)
@@ -791,6 +948,39 @@ func TestPathTypeOrder(t *testing.T) {
 	}
 }
 
+func TestPeers(t *testing.T) {
+	testCases := []struct {
+		port     string
+		expected int
+		logging  string
+	}{
+		// 0
+		{
+			port:     "",
+			expected: 0,
+		},
+		// 1
+		{
+			port:     "10000",
+			expected: 10000,
+		},
+		// 2
+		{
+			port:     "70000",
+			expected: 0,
+			logging:  "WARN ignoring invalid peers port: 70000",
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		d := c.createGlobalData(map[string]string{ingtypes.GlobalPeersPort: test.port})
+		c.createUpdater().buildGlobalPeers(d)
+		c.compareObjects("peers port", i, d.global.Peers.Port, test.expected)
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
 func TestSecurity(t *testing.T) {
 	testCases := []struct {
 		ann      map[string]string
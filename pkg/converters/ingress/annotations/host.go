@@ -93,7 +93,7 @@ func (c *updater) buildHostCertSigner(d *hostData) {
 		return
 	}
 	if signer.Value != "acme" {
-		c.logger.Warn("ignoring invalid cert-signer on %v: %s", signer.Source, signer.Value)
+		convtypes.Reject(c.logger, "ignoring invalid cert-signer on %v: %s", signer.Source, signer.Value)
 		return
 	}
 	acmeData := c.haproxy.AcmeData()
@@ -108,14 +108,14 @@ func (c *updater) buildHostRedirect(d *hostData) {
 	// TODO need a host<->host tracking if a target is found
 	redir := d.mapper.Get(ingtypes.HostRedirectFrom)
 	if target := c.haproxy.Hosts().FindTargetRedirect(redir.Value, false); target != nil {
-		c.logger.Warn("ignoring redirect from '%s' on %v, it's already targeting to '%s'",
+		convtypes.Reject(c.logger, "ignoring redirect from '%s' on %v, it's already targeting to '%s'",
 			redir.Value, redir.Source, target.Hostname)
 	} else if len(d.host.Paths) > 0 {
 		d.host.Redirect.RedirectHost = redir.Value
 	}
 	redirRegex := d.mapper.Get(ingtypes.HostRedirectFromRegex)
 	if target := c.haproxy.Hosts().FindTargetRedirect(redirRegex.Value, true); target != nil {
-		c.logger.Warn("ignoring regex redirect from '%s' on %v, it's already targeting to '%s'",
+		convtypes.Reject(c.logger, "ignoring regex redirect from '%s' on %v, it's already targeting to '%s'",
 			redirRegex.Value, redirRegex.Source, target.Hostname)
 	} else if len(d.host.Paths) > 0 {
 		d.host.Redirect.RedirectHostRegex = redirRegex.Value
@@ -18,9 +18,13 @@ package annotations
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
 	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
 	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
@@ -34,6 +38,7 @@ type ConfigValueGetter interface {
 type MapBuilder struct {
 	logger      types.Logger
 	annDefaults map[string]string
+	policy      *NamespacePolicy
 }
 
 // Mapper ...
@@ -76,6 +81,12 @@ func NewMapBuilder(logger types.Logger, annDefaults map[string]string) *MapBuild
 	}
 }
 
+// WithNamespacePolicy ...
+func (b *MapBuilder) WithNamespacePolicy(policy *NamespacePolicy) *MapBuilder {
+	b.policy = policy
+	return b
+}
+
 // NewMapper ...
 func (b *MapBuilder) NewMapper() *Mapper {
 	return &Mapper{
@@ -135,8 +146,22 @@ func (c *Mapper) addAnnotation(source *Source, path *hatypes.PathLink, key, valu
 
 // AddAnnotations ...
 func (c *Mapper) AddAnnotations(source *Source, path *hatypes.PathLink, ann map[string]string) (conflicts []string) {
+	return c.addAnnotations(source, path, ann, true)
+}
+
+// AddTrustedAnnotations works like AddAnnotations, but does not apply the
+// namespace annotations policy. Used on configurations managed by the
+// cluster admin, like IngressClass Parameters.
+func (c *Mapper) AddTrustedAnnotations(source *Source, path *hatypes.PathLink, ann map[string]string) (conflicts []string) {
+	return c.addAnnotations(source, path, ann, false)
+}
+
+func (c *Mapper) addAnnotations(source *Source, path *hatypes.PathLink, ann map[string]string, restricted bool) (conflicts []string) {
 	conflicts = make([]string, 0, len(ann))
 	for key, value := range ann {
+		if restricted && !c.policy.Allowed(source, key) {
+			continue
+		}
 		if conflict := c.addAnnotation(source, path, key, value); conflict {
 			conflicts = append(conflicts, key)
 		}
@@ -156,6 +181,29 @@ func (c *Mapper) findPathConfig(key string) ([]*PathConfig, bool) {
 	return nil, false
 }
 
+// ConfigSources lists the keys assigned to the mapper, the resource that
+// declared each value and the path it applies to. Keys that use the global
+// or the default value are not listed.
+func (c *Mapper) ConfigSources() []*hatypes.ConfigSource {
+	keys := make([]string, 0, len(c.configByKey))
+	for key := range c.configByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sources []*hatypes.ConfigSource
+	for _, key := range keys {
+		for _, config := range c.configByKey[key] {
+			sources = append(sources, &hatypes.ConfigSource{
+				Key:    key,
+				Value:  config.value.Value,
+				Source: config.value.Source.String(),
+				Path:   config.path,
+			})
+		}
+	}
+	return sources
+}
+
 // GetConfig ...
 func (c *Mapper) GetConfig(path *hatypes.PathLink) *KeyConfig {
 	if config, found := c.configByPath[path.Hash()]; found {
@@ -253,6 +301,28 @@ func (m *PathConfig) String() string {
 	return fmt.Sprintf("%+v", *m)
 }
 
+// ObjectReference ...
+func (s *Source) ObjectReference() *api.ObjectReference {
+	if s == nil || s.Namespace == "" || s.Name == "" {
+		return nil
+	}
+	var apiVersion string
+	switch s.Type {
+	case convtypes.ResourceIngress:
+		apiVersion = networking.SchemeGroupVersion.String()
+	case convtypes.ResourceService:
+		apiVersion = api.SchemeGroupVersion.String()
+	default:
+		return nil
+	}
+	return &api.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       string(s.Type),
+		Namespace:  s.Namespace,
+		Name:       s.Name,
+	}
+}
+
 // String ...
 func (s *Source) String() string {
 	if s == nil {
@@ -20,6 +20,9 @@ import (
 	"reflect"
 	"testing"
 
+	api "k8s.io/api/core/v1"
+
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
 	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
 )
 
@@ -296,3 +299,65 @@ func TestGetDefault(t *testing.T) {
 		c.teardown()
 	}
 }
+
+func TestConfigSources(t *testing.T) {
+	pathRoot := hatypes.CreateHostPathLink("domain.local", "/", hatypes.MatchBegin)
+	pathURL := hatypes.CreateHostPathLink("domain.local", "/url", hatypes.MatchBegin)
+	c := setup(t)
+	defer c.teardown()
+	mapper := NewMapBuilder(c.logger, map[string]string{"timeout-server": "50s"}).NewMapper()
+	mapper.AddAnnotations(srcing2, pathURL, map[string]string{"timeout-server": "30s"})
+	mapper.AddAnnotations(srcing1, pathRoot, map[string]string{"timeout-server": "10s", "balance": "leastconn"})
+	expected := []*hatypes.ConfigSource{
+		{Key: "balance", Value: "leastconn", Source: "ingress 'default/ing1'", Path: pathRoot},
+		{Key: "timeout-server", Value: "30s", Source: "ingress 'default/ing2'", Path: pathURL},
+		{Key: "timeout-server", Value: "10s", Source: "ingress 'default/ing1'", Path: pathRoot},
+	}
+	if sources := mapper.ConfigSources(); !reflect.DeepEqual(sources, expected) {
+		t.Errorf("expected %+v but was %+v", expected, sources)
+	}
+}
+
+func TestSourceObjectReference(t *testing.T) {
+	testCases := []struct {
+		source   *Source
+		expected *api.ObjectReference
+	}{
+		// 0
+		{},
+		// 1
+		{
+			source: &Source{Namespace: "default", Name: "ing1", Type: convtypes.ResourceIngress},
+			expected: &api.ObjectReference{
+				APIVersion: "networking.k8s.io/v1",
+				Kind:       "Ingress",
+				Namespace:  "default",
+				Name:       "ing1",
+			},
+		},
+		// 2
+		{
+			source: &Source{Namespace: "default", Name: "svc1", Type: convtypes.ResourceService},
+			expected: &api.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Namespace:  "default",
+				Name:       "svc1",
+			},
+		},
+		// 3
+		{
+			source: &Source{Name: "<default-backend>", Type: convtypes.ResourceIngress},
+		},
+		// 4
+		{
+			source: &Source{Namespace: "default", Name: "cm1", Type: convtypes.ResourceConfigMap},
+		},
+	}
+	for i, test := range testCases {
+		ref := test.source.ObjectReference()
+		if !reflect.DeepEqual(ref, test.expected) {
+			t.Errorf("object reference differs on %d - expected: %+v - actual: %+v", i, test.expected, ref)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"strings"
+
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
+)
+
+// NamespacePolicyLabel is the namespace label whose value names the
+// annotations policy applied to the resources of the namespace.
+const NamespacePolicyLabel = "haproxy-ingress.github.io/annotations-policy"
+
+// NamespacePolicy restricts the configuration keys that resources can use,
+// based on the annotations policy of their namespace.
+type NamespacePolicy struct {
+	logger    types.Logger
+	cache     convtypes.Cache
+	dynconfig *convtypes.DynamicConfig
+	policies  map[string]*namespacePolicy
+	denied    map[string]bool
+}
+
+type namespacePolicy struct {
+	name  string
+	allow []string
+	deny  []string
+	err   error
+}
+
+// NewNamespacePolicy ...
+func NewNamespacePolicy(logger types.Logger, cache convtypes.Cache, dynconfig *convtypes.DynamicConfig) *NamespacePolicy {
+	return &NamespacePolicy{
+		logger:    logger,
+		cache:     cache,
+		dynconfig: dynconfig,
+		policies:  map[string]*namespacePolicy{},
+		denied:    map[string]bool{},
+	}
+}
+
+// Allowed returns true if the configuration key can be used by source.
+// Only Ingress and Service resources are restricted. A denied key is
+// logged once per source, key and NamespacePolicy instance.
+func (p *NamespacePolicy) Allowed(source *Source, key string) bool {
+	if p == nil || p.dynconfig == nil || source == nil || source.Namespace == "" {
+		return true
+	}
+	if len(p.dynconfig.NamespaceAnnotationsAllow) == 0 && len(p.dynconfig.NamespaceAnnotationsDeny) == 0 {
+		return true
+	}
+	if source.Type != convtypes.ResourceIngress && source.Type != convtypes.ResourceService {
+		return true
+	}
+	policy := p.readPolicy(source.Namespace)
+	if policy.allowed(key) {
+		return true
+	}
+	deniedID := source.String() + "/" + key
+	if !p.denied[deniedID] {
+		p.denied[deniedID] = true
+		if policy.err != nil {
+			p.logger.Warn("ignoring key '%s' for %s: error reading namespace: %v", key, source, policy.err)
+		} else {
+			convtypes.Reject(p.logger, "ignoring key '%s' for %s: not allowed by annotations policy '%s' of namespace '%s'",
+				key, source, policy.name, source.Namespace)
+		}
+	}
+	return false
+}
+
+func (p *NamespacePolicy) readPolicy(namespace string) *namespacePolicy {
+	if policy, found := p.policies[namespace]; found {
+		return policy
+	}
+	policy := &namespacePolicy{}
+	ns, err := p.cache.GetNamespace(namespace)
+	if err != nil {
+		// fail closed, the namespace policy is unknown
+		policy.err = err
+	} else if name := ns.Labels[NamespacePolicyLabel]; name != "" {
+		policy.name = name
+		policy.allow = p.dynconfig.NamespaceAnnotationsAllow[name]
+		policy.deny = p.dynconfig.NamespaceAnnotationsDeny[name]
+		_, hasAllow := p.dynconfig.NamespaceAnnotationsAllow[name]
+		if hasAllow && policy.allow == nil {
+			// declared but empty, nothing is allowed
+			policy.allow = []string{}
+		}
+	}
+	p.policies[namespace] = policy
+	return policy
+}
+
+func (p *namespacePolicy) allowed(key string) bool {
+	if p.err != nil {
+		return false
+	}
+	if p.allow != nil && !matchKey(p.allow, key) {
+		return false
+	}
+	return !matchKey(p.deny, key)
+}
+
+// matchKey returns true if key matches one of the patterns. A pattern
+// ending with `*` matches all the keys starting with the pattern prefix.
+func matchKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if prefix, found := strings.CutSuffix(pattern, "*"); found {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if pattern == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	api "k8s.io/api/core/v1"
+
+	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+func TestNamespacePolicy(t *testing.T) {
+	ann := map[string]string{
+		ingtypes.BackConfigBackend:    "acl ok always_true",
+		ingtypes.HostAuthTLSSecret:    "ca",
+		ingtypes.HostAuthTLSStrict:    "true",
+		ingtypes.HostSSLPassthrough:   "true",
+		ingtypes.BackTimeoutServer:    "10s",
+		ingtypes.BackBalanceAlgorithm: "roundrobin",
+	}
+	testCases := []struct {
+		allow    map[string][]string
+		deny     map[string][]string
+		label    string
+		source   *Source
+		expected []string
+		logging  string
+	}{
+		// 0
+		{
+			label: "tenants",
+			expected: []string{
+				ingtypes.HostAuthTLSSecret, ingtypes.HostAuthTLSStrict, ingtypes.BackBalanceAlgorithm,
+				ingtypes.BackConfigBackend, ingtypes.HostSSLPassthrough, ingtypes.BackTimeoutServer,
+			},
+		},
+		// 1
+		{
+			deny:  map[string][]string{"tenants": {"config-backend", "auth-tls-*", "ssl-passthrough"}},
+			label: "tenants",
+			expected: []string{
+				ingtypes.BackBalanceAlgorithm, ingtypes.BackTimeoutServer,
+			},
+			logging: `
+WARN ignoring key 'auth-tls-secret' for Ingress 'ns1/ing1': not allowed by annotations policy 'tenants' of namespace 'ns1'
+WARN ignoring key 'auth-tls-strict' for Ingress 'ns1/ing1': not allowed by annotations policy 'tenants' of namespace 'ns1'
+WARN ignoring key 'config-backend' for Ingress 'ns1/ing1': not allowed by annotations policy 'tenants' of namespace 'ns1'
+WARN ignoring key 'ssl-passthrough' for Ingress 'ns1/ing1': not allowed by annotations policy 'tenants' of namespace 'ns1'
+`,
+		},
+		// 2
+		{
+			deny:  map[string][]string{"tenants": {"config-backend"}},
+			label: "other",
+			expected: []string{
+				ingtypes.HostAuthTLSSecret, ingtypes.HostAuthTLSStrict, ingtypes.BackBalanceAlgorithm,
+				ingtypes.BackConfigBackend, ingtypes.HostSSLPassthrough, ingtypes.BackTimeoutServer,
+			},
+		},
+		// 3
+		{
+			allow: map[string][]string{"restricted": {"timeout-*", "balance-algorithm", "auth-tls-strict"}},
+			deny:  map[string][]string{"restricted": {"auth-tls-*"}},
+			label: "restricted",
+			source: &Source{
+				Namespace: "ns1",
+				Name:      "svc1",
+				Type:      convtypes.ResourceService,
+			},
+			expected: []string{
+				ingtypes.BackBalanceAlgorithm, ingtypes.BackTimeoutServer,
+			},
+			logging: `
+WARN ignoring key 'auth-tls-secret' for Service 'ns1/svc1': not allowed by annotations policy 'restricted' of namespace 'ns1'
+WARN ignoring key 'auth-tls-strict' for Service 'ns1/svc1': not allowed by annotations policy 'restricted' of namespace 'ns1'
+WARN ignoring key 'config-backend' for Service 'ns1/svc1': not allowed by annotations policy 'restricted' of namespace 'ns1'
+WARN ignoring key 'ssl-passthrough' for Service 'ns1/svc1': not allowed by annotations policy 'restricted' of namespace 'ns1'
+`,
+		},
+		// 4
+		{
+			deny:  map[string][]string{"tenants": {"config-backend"}},
+			label: "tenants",
+			source: &Source{
+				Namespace: "ns1",
+				Name:      "cm1",
+				Type:      convtypes.ResourceConfigMap,
+			},
+			expected: []string{
+				ingtypes.HostAuthTLSSecret, ingtypes.HostAuthTLSStrict, ingtypes.BackBalanceAlgorithm,
+				ingtypes.BackConfigBackend, ingtypes.HostSSLPassthrough, ingtypes.BackTimeoutServer,
+			},
+		},
+		// 5
+		{
+			deny: map[string][]string{"tenants": {"config-backend"}},
+			source: &Source{
+				Namespace: "ns2",
+				Name:      "ing1",
+				Type:      convtypes.ResourceIngress,
+			},
+			logging: `
+WARN ignoring key 'auth-tls-secret' for Ingress 'ns2/ing1': error reading namespace: namespace not found: ns2
+WARN ignoring key 'auth-tls-strict' for Ingress 'ns2/ing1': error reading namespace: namespace not found: ns2
+WARN ignoring key 'balance-algorithm' for Ingress 'ns2/ing1': error reading namespace: namespace not found: ns2
+WARN ignoring key 'config-backend' for Ingress 'ns2/ing1': error reading namespace: namespace not found: ns2
+WARN ignoring key 'ssl-passthrough' for Ingress 'ns2/ing1': error reading namespace: namespace not found: ns2
+WARN ignoring key 'timeout-server' for Ingress 'ns2/ing1': error reading namespace: namespace not found: ns2
+`,
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		ns := &api.Namespace{}
+		ns.Name = "ns1"
+		ns.Labels = map[string]string{NamespacePolicyLabel: test.label}
+		c.cache.NsList["ns1"] = ns
+		dynconfig := &convtypes.DynamicConfig{
+			NamespaceAnnotationsAllow: test.allow,
+			NamespaceAnnotationsDeny:  test.deny,
+		}
+		policy := NewNamespacePolicy(c.logger, c.cache, dynconfig)
+		mapper := NewMapBuilder(c.logger, map[string]string{}).WithNamespacePolicy(policy).NewMapper()
+		source := test.source
+		if source == nil {
+			source = &Source{Namespace: "ns1", Name: "ing1", Type: convtypes.ResourceIngress}
+		}
+		// add twice on distinct paths, denied keys should be logged only once
+		for _, path := range []string{"/app1", "/app2"} {
+			// sorted keys make logging predictable
+			keys := make([]string, 0, len(ann))
+			for key := range ann {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				mapper.AddAnnotations(source, hatypes.CreatePathLink(path, hatypes.MatchBegin), map[string]string{key: ann[key]})
+			}
+		}
+		actual := make([]string, 0, len(mapper.configByKey))
+		for key := range mapper.configByKey {
+			actual = append(actual, key)
+		}
+		sort.Strings(actual)
+		expected := append([]string{}, test.expected...)
+		sort.Strings(expected)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("keys differ on %d - expected: %v - actual: %v", i, expected, actual)
+		}
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
@@ -87,9 +87,9 @@ var regexValidTime = regexp.MustCompile(`^[0-9]+(us|ms|s|m|h|d)$`)
 func (c *updater) validateTime(cfg *ConfigValue) string {
 	if !regexValidTime.MatchString(cfg.Value) {
 		if cfg.Source != nil {
-			c.logger.Warn("ignoring invalid time format on %v: %s", cfg.Source, cfg.Value)
+			convtypes.Reject(c.logger, "ignoring invalid time format on %v: %s", cfg.Source, cfg.Value)
 		} else if cfg.Value != "" {
-			c.logger.Warn("ignoring invalid time format on global/default config: %s", cfg.Value)
+			convtypes.Reject(c.logger, "ignoring invalid time format on global/default config: %s", cfg.Value)
 		}
 		return ""
 	}
@@ -101,7 +101,7 @@ func (c *updater) validateAllowDeny(d *globalData, key string) (allow bool) {
 	value := strings.ToLower(cfg.Value)
 	allow = value == "allow"
 	if value != "" && value != "allow" && value != "deny" {
-		c.logger.Warn("ignoring invalid value '%s' on global '%s', using 'deny'", cfg.Value, key)
+		convtypes.Reject(c.logger, "ignoring invalid value '%s' on global '%s', using 'deny'", cfg.Value, key)
 	}
 	return allow
 }
@@ -132,7 +132,7 @@ func (c *updater) splitDualCIDR(cidrlist *ConfigValue) (allow, deny []string) {
 			_, _, err = net.ParseCIDR(cidr)
 		}
 		if err != nil {
-			c.logger.Warn("skipping invalid IP or cidr on %v: %s", cidrlist.Source, cidr)
+			convtypes.Reject(c.logger, "skipping invalid IP or cidr on %v: %s", cidrlist.Source, cidr)
 		} else if neg {
 			deny = append(deny, cidr)
 		} else {
@@ -172,6 +172,7 @@ func (c *updater) UpdateGlobalConfig(haproxyConfig haproxy.Config, mapper *Mappe
 	c.buildGlobalAcme(d)
 	c.buildGlobalAuthProxy(d)
 	c.buildGlobalBind(d)
+	c.buildGlobalCache(d)
 	c.buildGlobalCloseSessions(d)
 	c.buildGlobalCustomConfig(d)
 	c.buildGlobalCustomResponses(d)
@@ -181,6 +182,7 @@ func (c *updater) UpdateGlobalConfig(haproxyConfig haproxy.Config, mapper *Mappe
 	c.buildGlobalHTTPStoHTTP(d)
 	c.buildGlobalModSecurity(d)
 	c.buildGlobalPathTypeOrder(d)
+	c.buildGlobalPeers(d)
 	c.buildGlobalProc(d)
 	c.buildSecurity(d)
 	c.buildGlobalSSL(d)
@@ -229,15 +231,20 @@ func (c *updater) UpdateBackendConfig(backend *hatypes.Backend, mapper *Mapper)
 		mapper:  mapper,
 	}
 	// TODO check ModeTCP with HTTP annotations
+	backend.ConfigSources = mapper.ConfigSources()
 	backend.BalanceAlgorithm = mapper.Get(ingtypes.BackBalanceAlgorithm).Value
 	backend.Server.MaxConn = mapper.Get(ingtypes.BackMaxconnServer).Int()
 	backend.Server.MaxQueue = mapper.Get(ingtypes.BackMaxQueueServer).Int()
 	c.buildBackendAffinity(data)
 	c.buildBackendAuthExternal(data)
+	c.buildBackendAuthAPIKey(data)
 	c.buildBackendAuthHTTP(data)
+	c.buildBackendAuthJWT(data)
 	c.buildBackendBlueGreenBalance(data)
 	c.buildBackendBlueGreenSelector(data)
 	c.buildBackendBodySize(data)
+	c.buildBackendCache(data)
+	c.buildBackendCompression(data)
 	c.buildBackendCors(data)
 	c.buildBackendCustomConfig(data)
 	c.buildBackendDNS(data)
@@ -250,6 +257,8 @@ func (c *updater) UpdateBackendConfig(backend *hatypes.Backend, mapper *Mapper)
 	c.buildBackendOAuth(data)
 	c.buildBackendProtocol(data)
 	c.buildBackendProxyProtocol(data)
+	c.buildBackendRateLimit(data)
+	c.buildBackendRetry(data)
 	c.buildBackendRewriteURL(data)
 	c.buildBackendServerNaming(data)
 	c.buildBackendSourceAddressIntf(data)
@@ -20,9 +20,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
 )
 
 type validate struct {
@@ -38,26 +41,33 @@ var (
 	corsHeadersRegex = regexp.MustCompile(`^([A-Za-z0-9\-\_]+,?\s?)|\*+$`)
 )
 
+// retryOnConditions has the conditions supported by HAProxy's retry-on keyword.
+var retryOnConditions = map[string]bool{
+	"none": true, "conn-failure": true, "empty-response": true, "junk-response": true,
+	"response-timeout": true, "0rtt-rejected": true, "all-retryable-errors": true,
+	"404": true, "408": true, "425": true, "500": true, "501": true, "502": true, "503": true, "504": true,
+}
+
 var validators = map[string]func(v validate) (string, bool){
 	ingtypes.BackCorsAllowCredentials: validateBool,
 	ingtypes.BackCorsAllowHeaders: func(v validate) (string, bool) {
 		if corsHeadersRegex.MatchString(v.value) {
 			return v.value, true
 		}
-		v.logger.Warn("ignoring invalid cors headers on %s: %s", v.source, v.value)
+		convtypes.Reject(v.logger, "ignoring invalid cors headers on %s: %s", v.source, v.value)
 		return "", false
 	},
 	ingtypes.BackCorsAllowMethods: func(v validate) (string, bool) {
 		if corsMethodsRegex.MatchString(v.value) {
 			return v.value, true
 		}
-		v.logger.Warn("ignoring invalid cors methods on %s: %s", v.source, v.value)
+		convtypes.Reject(v.logger, "ignoring invalid cors methods on %s: %s", v.source, v.value)
 		return "", false
 	},
 	ingtypes.BackCorsAllowOrigin: func(v validate) (string, bool) {
 		for _, value := range strings.Split(v.value, ",") {
 			if !corsOriginRegex.MatchString(value) {
-				v.logger.Warn("ignoring invalid cors origin on %s: %s", v.source, value)
+				convtypes.Reject(v.logger, "ignoring invalid cors origin on %s: %s", v.source, value)
 				return "", false
 			}
 		}
@@ -69,7 +79,7 @@ var validators = map[string]func(v validate) (string, bool){
 		for _, value := range strings.Split(v.value, " ") {
 			_, err := regexp.Compile(value)
 			if err != nil {
-				v.logger.Warn("ignoring invalid cors origin regex on %s: %s", v.source, value)
+				convtypes.Reject(v.logger, "ignoring invalid cors origin regex on %s: %s", v.source, value)
 				return "", false
 			}
 		}
@@ -79,7 +89,7 @@ var validators = map[string]func(v validate) (string, bool){
 		if corsHeadersRegex.MatchString(v.value) {
 			return v.value, true
 		}
-		v.logger.Warn("ignoring invalid cors expose headers on %s: %s", v.source, v.value)
+		convtypes.Reject(v.logger, "ignoring invalid cors expose headers on %s: %s", v.source, v.value)
 		return "", false
 	},
 	ingtypes.BackCorsMaxAge: func(v validate) (string, bool) {
@@ -87,21 +97,59 @@ var validators = map[string]func(v validate) (string, bool){
 		if err == nil || maxAge > 0 {
 			return v.value, true
 		}
-		v.logger.Warn("ignoring invalid cors max age on %s: %s", v.source, v.value)
+		convtypes.Reject(v.logger, "ignoring invalid cors max age on %s: %s", v.source, v.value)
 		return "", false
 	},
 	ingtypes.BackHSTS:                  validateBool,
 	ingtypes.BackHSTSMaxAge:            validateInt,
 	ingtypes.BackHSTSPreload:           validateBool,
 	ingtypes.BackHSTSIncludeSubdomains: validateBool,
-	ingtypes.BackSSLRedirect:           validateBool,
+	ingtypes.BackRetries: func(v validate) (string, bool) {
+		if retries, err := strconv.Atoi(v.value); err == nil && retries >= 0 {
+			return strconv.Itoa(retries), true
+		}
+		convtypes.Reject(v.logger, "ignoring invalid retries on %s: %s", v.source, v.value)
+		return "", false
+	},
+	ingtypes.BackRetryOn: func(v validate) (string, bool) {
+		conditions := utils.Split(v.value, ",")
+		for _, cond := range conditions {
+			if !retryOnConditions[cond] || (cond == "none" && len(conditions) > 1) {
+				convtypes.Reject(v.logger, "ignoring invalid retry-on condition on %s: %s", v.source, cond)
+				return "", false
+			}
+		}
+		return strings.Join(conditions, ","), true
+	},
+	ingtypes.BackRetryRedispatch: func(v validate) (string, bool) {
+		if interval, err := strconv.Atoi(v.value); err == nil {
+			if interval != 0 {
+				return strconv.Itoa(interval), true
+			}
+		} else if res, err := strconv.ParseBool(v.value); err == nil {
+			return strconv.FormatBool(res), true
+		}
+		convtypes.Reject(v.logger, "ignoring invalid retry redispatch on %s: %s", v.source, v.value)
+		return "", false
+	},
+	ingtypes.BackRetryTimeoutConnect: func(v validate) (string, bool) {
+		// haproxy does not wait more than one second before a retry
+		if regexValidTime.MatchString(v.value) {
+			if timeout, err := time.ParseDuration(v.value); err == nil && timeout > 0 && timeout <= time.Second {
+				return v.value, true
+			}
+		}
+		convtypes.Reject(v.logger, "ignoring invalid retry timeout connect on %s: %s", v.source, v.value)
+		return "", false
+	},
+	ingtypes.BackSSLRedirect: validateBool,
 }
 
 func validateBool(v validate) (string, bool) {
 	if res, err := strconv.ParseBool(v.value); err == nil {
 		return strconv.FormatBool(res), true
 	}
-	v.logger.Warn("ignoring invalid bool expression on %s key '%s': %s", v.source, v.key, v.value)
+	convtypes.Reject(v.logger, "ignoring invalid bool expression on %s key '%s': %s", v.source, v.key, v.value)
 	return "", false
 }
 
@@ -109,6 +157,18 @@ func validateInt(v validate) (string, bool) {
 	if res, err := strconv.Atoi(v.value); err == nil {
 		return strconv.Itoa(res), true
 	}
-	v.logger.Warn("ignoring invalid int expression on %s key '%s': %s", v.source, v.key, v.value)
+	convtypes.Reject(v.logger, "ignoring invalid int expression on %s key '%s': %s", v.source, v.key, v.value)
 	return "", false
 }
+
+// ValidateGlobal validates and normalizes a value of the global config,
+// which is used as the default value of the keys that annotations can
+// override. Annotations are validated when added to a mapper, the global
+// config should be validated once when it is parsed.
+func ValidateGlobal(logger types.Logger, key, value string) (string, bool) {
+	validator, found := validators[key]
+	if !found || value == "" {
+		return value, true
+	}
+	return validator(validate{logger: logger, key: key, value: value})
+}
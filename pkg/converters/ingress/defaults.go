@@ -49,6 +49,8 @@ func createDefaults() map[string]string {
 		types.BackBackendServerSlotsInc:  "1",
 		types.BackSlotsMinFree:           "6",
 		types.BackBalanceAlgorithm:       "roundrobin",
+		types.BackCompressionAlgo:        "gzip",
+		types.BackCompressionTypes:       "text/html,text/plain,text/css,text/javascript,application/javascript,application/json,application/xml,image/svg+xml",
 		types.BackCorsAllowHeaders:       "DNT,X-CustomHeader,Keep-Alive,User-Agent,X-Requested-With,If-Modified-Since,Cache-Control,Content-Type,Authorization",
 		types.BackCorsAllowMethods:       "GET, PUT, POST, DELETE, PATCH, OPTIONS",
 		types.BackCorsAllowOrigin:        "*",
@@ -60,6 +62,8 @@ func createDefaults() map[string]string {
 		types.BackHSTSMaxAge:             "15768000",
 		types.BackHSTSPreload:            "false",
 		types.BackInitialWeight:          "1",
+		types.BackLimitRequestsKey:       "src",
+		types.BackLimitRequestsWindow:    "1s",
 		types.BackOAuthHeaders:           "X-Auth-Request-Email",
 		types.BackSessionCookieDynamic:   "true",
 		types.BackSessionCookiePreserve:  "false",
@@ -79,6 +83,8 @@ func createDefaults() map[string]string {
 		//
 		types.GlobalAcmeExpiring:                 "30",
 		types.GlobalAuthProxy:                    "_front__auth__local:14415-14499",
+		types.GlobalCacheMaxAge:                  "60s",
+		types.GlobalCacheSize:                    "64",
 		types.GlobalCookieKey:                    "Ingress",
 		types.GlobalDNSAcceptedPayloadSize:       "8192",
 		types.GlobalDNSClusterDomain:             "cluster.local",
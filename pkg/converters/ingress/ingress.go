@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	api "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
@@ -61,8 +62,11 @@ func NewIngressConverter(options *convtypes.ConverterOptions, haproxy haproxy.Co
 	}
 	defaultConfig := options.DefaultConfig()
 	for key, value := range globalConfig {
-		defaultConfig[key] = value
+		if value, ok := annotations.ValidateGlobal(options.Logger, key, value); ok {
+			defaultConfig[key] = value
+		}
 	}
+	policy := annotations.NewNamespacePolicy(options.Logger, options.Cache, options.DynamicConfig)
 	c := &converter{
 		options:            options,
 		haproxy:            haproxy,
@@ -71,7 +75,8 @@ func NewIngressConverter(options *convtypes.ConverterOptions, haproxy haproxy.Co
 		cache:              options.Cache,
 		tracker:            options.Tracker,
 		defaultBackSource:  annotations.Source{Name: "<default-backend>", Type: convtypes.ResourceIngress},
-		mapBuilder:         annotations.NewMapBuilder(options.Logger, defaultConfig),
+		policy:             policy,
+		mapBuilder:         annotations.NewMapBuilder(options.Logger, defaultConfig).WithNamespacePolicy(policy),
 		updater:            annotations.NewUpdater(haproxy, options),
 		globalConfig:       annotations.NewMapBuilder(options.Logger, defaultConfig).NewMapper(),
 		tcpsvcAnnotations:  map[*hatypes.TCPServicePort]*annotations.Mapper{},
@@ -92,6 +97,7 @@ type converter struct {
 	tracker            convtypes.Tracker
 	defaultCrt         convtypes.CrtFile
 	defaultBackSource  annotations.Source
+	policy             *annotations.NamespacePolicy
 	mapBuilder         *annotations.MapBuilder
 	updater            annotations.Updater
 	globalConfig       *annotations.Mapper
@@ -113,7 +119,7 @@ func (c *converter) ReadAnnotations(backend *hatypes.Backend, services []*api.Se
 		for _, pathLink := range pathLinks {
 			conflict := mapper.AddAnnotations(source, pathLink, ann)
 			if len(conflict) > 0 {
-				c.logger.Warn("skipping %s annotation(s) due to conflict: %v", source, conflict)
+				convtypes.Reject(c.logger, "skipping %s annotation(s) due to conflict: %v", source, conflict)
 			}
 		}
 	}
@@ -141,6 +147,7 @@ func (c *converter) Sync(full bool) {
 	} else {
 		c.syncPartial()
 	}
+	c.syncPeers()
 }
 
 func (c *converter) defaultCrtNeedFullSync() bool {
@@ -198,6 +205,44 @@ func (c *converter) syncDefaultBackend() {
 	}
 }
 
+// syncPeers updates the peer servers, one per running controller pod. This runs
+// on every sync, since controller pods change outside the global config.
+func (c *converter) syncPeers() {
+	peers := &c.haproxy.Global().Peers
+	if peers.Port == 0 {
+		return
+	}
+	peers.LocalPeer = c.options.PodName
+	peers.Servers = nil
+	if peers.LocalPeer == "" {
+		c.logger.Warn("skipping peers configuration: POD_NAME envvar was not configured")
+		return
+	}
+	pods, err := c.cache.GetControllerPodList()
+	if err != nil {
+		c.logger.Warn("skipping peers configuration: error reading controller pods: %v", err)
+		return
+	}
+	var servers []hatypes.PeerServer
+	hasLocal := false
+	for _, pod := range pods {
+		local := pod.Name == peers.LocalPeer
+		if pod.Status.PodIP == "" || (pod.DeletionTimestamp != nil && !local) {
+			continue
+		}
+		hasLocal = hasLocal || local
+		servers = append(servers, hatypes.PeerServer{Name: pod.Name, IP: pod.Status.PodIP})
+	}
+	if !hasLocal {
+		c.logger.Warn("skipping peers configuration: controller pod '%s' not found or without IP", peers.LocalPeer)
+		return
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+	peers.Servers = servers
+}
+
 func (c *converter) syncFull() {
 	ingList, err := c.cache.GetIngressList()
 	if err != nil {
@@ -388,7 +433,11 @@ func (c *converter) syncIngressHTTP(source *annotations.Source, ing *networking.
 		hostname := normalizeHostname(rule.Host, 0)
 		ingressClass := c.readIngressClass(source, ing.Spec.IngressClassName)
 		sslpassthrough, _ := strconv.ParseBool(annHost[ingtypes.HostSSLPassthrough])
-		host := c.addHost(hostname, source, annHost)
+		host, err := c.addHost(hostname, source, ing.CreationTimestamp.Time, annHost)
+		if err != nil {
+			c.logger.Warn("skipping hostname '%s' of %v: %v", hostname, source, err)
+			continue
+		}
 		for _, path := range rule.HTTP.Paths {
 			uri := path.Path
 			if uri == "" {
@@ -404,17 +453,27 @@ func (c *converter) syncIngressHTTP(source *annotations.Source, ing *networking.
 			}
 			if sslpassthrough && uri == "/" {
 				if host.FindPath(uri) != nil {
-					c.logger.Warn("skipping redeclared ssl-passthrough root path on %v", source)
+					convtypes.Reject(c.logger, "skipping redeclared ssl-passthrough root path on %v", source)
 					continue
 				}
 			} else if host.FindPathWithLink(pathLink) != nil {
-				c.logger.Warn("skipping redeclared path '%s' type '%s' on %v", uri, match, source)
+				convtypes.Reject(c.logger, "skipping redeclared path '%s' type '%s' on %v", uri, match, source)
 				continue
 			}
 			if redirectTo := annBack[ingtypes.BackRedirectTo]; redirectTo != "" {
 				host.AddRedirect(uri, match, redirectTo)
 				continue
 			}
+			if resource := path.Backend.Resource; resource != nil {
+				backend, err := c.addResourceBackend(source, pathLink, resource, annBack, ingressClass)
+				if err != nil {
+					c.logger.Warn("skipping resource backend on path '%s' of %v: %v", uri, source, err)
+					continue
+				}
+				host.AddLink(backend, pathLink)
+				host.FindPathWithLink(pathLink).Source = source.String()
+				continue
+			}
 			svcName, svcPort, err := readServiceNamePort(&path.Backend)
 			if err != nil {
 				c.logger.Warn("skipping backend config of %v: %v", source, err)
@@ -427,6 +486,7 @@ func (c *converter) syncIngressHTTP(source *annotations.Source, ing *networking.
 				continue
 			}
 			host.AddLink(backend, pathLink)
+			host.FindPathWithLink(pathLink).Source = source.String()
 			sslpasshttpport := annHost[ingtypes.HostSSLPassthroughHTTPPort]
 			if sslpassthrough && sslpasshttpport != "" {
 				if _, err := c.addBackend(source, pathLink, fullSvcName, sslpasshttpport, annBack); err != nil {
@@ -457,8 +517,14 @@ func (c *converter) syncIngressHTTP(source *annotations.Source, ing *networking.
 	}
 	for _, tls := range ing.Spec.TLS {
 		// tls secret
+		tlsHosts := make([]string, 0, len(tls.Hosts))
 		for _, hostname := range tls.Hosts {
-			host := c.addHost(hostname, source, annHost)
+			host, err := c.addHost(hostname, source, ing.CreationTimestamp.Time, annHost)
+			if err != nil {
+				c.logger.Warn("skipping TLS of hostname '%s' of %v: %v", hostname, source, err)
+				continue
+			}
+			tlsHosts = append(tlsHosts, hostname)
 			tlsPath := c.addTLS(source, tls.SecretName)
 			if host.TLS.TLSHash == "" {
 				host.TLS.TLSFilename = tlsPath.Filename
@@ -468,9 +534,9 @@ func (c *converter) syncIngressHTTP(source *annotations.Source, ing *networking.
 			} else if host.TLS.TLSHash != tlsPath.SHA1Hash {
 				msg := fmt.Sprintf("TLS of host '%s' was already assigned", host.Hostname)
 				if tls.SecretName != "" {
-					c.logger.Warn("skipping TLS secret '%s' of %v: %s", tls.SecretName, source, msg)
+					convtypes.Reject(c.logger, "skipping TLS secret '%s' of %v: %s", tls.SecretName, source, msg)
 				} else {
-					c.logger.Warn("skipping default TLS secret of %v: %s", source, msg)
+					convtypes.Reject(c.logger, "skipping default TLS secret of %v: %s", source, msg)
 				}
 			}
 		}
@@ -489,7 +555,7 @@ func (c *converter) syncIngressHTTP(source *annotations.Source, ing *networking.
 				secretName := ing.Namespace + "/" + tls.SecretName
 				ingName := ing.Namespace + "/" + ing.Name
 				acmeStorage := c.haproxy.AcmeData().Storages().Acquire(secretName)
-				acmeStorage.AddDomains(tls.Hosts)
+				acmeStorage.AddDomains(tlsHosts)
 				if preferredChain := annHost[ingtypes.HostAcmePreferredChain]; preferredChain != "" {
 					if err := acmeStorage.AssignPreferredChain(preferredChain); err != nil {
 						c.logger.Warn("preferred chain ignored on %v due to an error: %v", source, err)
@@ -520,7 +586,7 @@ func (c *converter) syncIngressTCP(source *annotations.Source, ing *networking.I
 			return err
 		}
 		if !tcpService.Backend.IsEmpty() {
-			return fmt.Errorf("service '%s' on %v: backend for port '%d' was already assigned", svcName, source, tcpServicePort)
+			return convtypes.ConflictErrorf("service '%s' on %v: backend for port '%d' was already assigned", svcName, source, tcpServicePort)
 		}
 		fullSvcName := ing.Namespace + "/" + svcName
 		pathLink := hatypes.CreateHostPathLink(hostname, "/", hatypes.MatchExact)
@@ -570,9 +636,9 @@ func (c *converter) syncIngressTCP(source *annotations.Source, ing *networking.I
 		} else if tcpPort.TLS.TLSHash != tlsPath.SHA1Hash {
 			msg := fmt.Sprintf("TLS of tcp service port '%d' was already assigned", tcpServicePort)
 			if secretName != "" {
-				c.logger.Warn("skipping TLS secret '%s' of %v: %s", secretName, source, msg)
+				convtypes.Reject(c.logger, "skipping TLS secret '%s' of %v: %s", secretName, source, msg)
 			} else {
-				c.logger.Warn("skipping default TLS secret of %v: %s", source, msg)
+				convtypes.Reject(c.logger, "skipping default TLS secret of %v: %s", source, msg)
 			}
 		}
 	}
@@ -657,10 +723,10 @@ func (c *converter) readPathType(path networking.HTTPIngressPath, ann string) ha
 		case "regex":
 			match = hatypes.MatchRegex
 		default:
-			c.logger.Warn("unsupported path-type '%s', using 'begin' instead.", matchStr)
+			convtypes.Reject(c.logger, "unsupported path-type '%s', using 'begin' instead.", matchStr)
 		}
 		if pathType != networking.PathTypeImplementationSpecific {
-			c.logger.Warn("unsupported '%s' pathType from Ingress spec, using '%s' instead.",
+			convtypes.Reject(c.logger, "unsupported '%s' pathType from Ingress spec, using '%s' instead.",
 				pathType, networking.PathTypeImplementationSpecific)
 		}
 	}
@@ -685,7 +751,7 @@ func (c *converter) addDefaultHostBackend(source *annotations.Source, fullSvcNam
 	match := hatypes.MatchBegin
 	if fr := c.haproxy.Hosts().FindHost(hostname); fr != nil {
 		if fr.FindPath(uri, match) != nil {
-			return fmt.Errorf("path %s was already defined on default host", uri)
+			return convtypes.ConflictErrorf("path %s was already defined on default host", uri)
 		}
 	}
 	pathLink := hatypes.CreateHostPathLink(hostname, uri, match)
@@ -694,8 +760,12 @@ func (c *converter) addDefaultHostBackend(source *annotations.Source, fullSvcNam
 		c.tracker.TrackNames(source.Type, source.FullName(), convtypes.ResourceService, fullSvcName)
 		return err
 	}
-	host := c.addHost(hostname, source, annHost)
-	host.AddPath(backend, uri, match)
+	// the default host is never owned, so the creation timestamp is not used
+	host, err := c.addHost(hostname, source, time.Time{}, annHost)
+	if err != nil {
+		return err
+	}
+	host.AddPath(backend, uri, match).Source = source.String()
 	return nil
 }
 
@@ -703,7 +773,7 @@ func (c *converter) addTCPService(source *annotations.Source, hostname string, p
 	tcpPort, tcpHost := c.haproxy.TCPServices().AcquireTCPService(hostname)
 	if !tcpHost.Backend.IsEmpty() {
 		tcpservice := strings.TrimPrefix(hostname, hatypes.DefaultHost)
-		return nil, fmt.Errorf("tcp service %s was already assigned to %s", tcpservice, tcpHost.Backend)
+		return nil, convtypes.ConflictErrorf("tcp service %s was already assigned to %s", tcpservice, tcpHost.Backend)
 	}
 	c.tracker.TrackNames(source.Type, source.FullName(), convtypes.ResourceHATCPService, hostname)
 	mapper, found := c.tcpsvcAnnotations[tcpPort]
@@ -713,15 +783,19 @@ func (c *converter) addTCPService(source *annotations.Source, hostname string, p
 	}
 	conflict := mapper.AddAnnotations(source, hatypes.CreateHostPathLink(hostname, "/", hatypes.MatchExact), ann)
 	if len(conflict) > 0 {
-		c.logger.Warn("skipping tcp service annotation(s) from %v due to conflict: %v", source, conflict)
+		convtypes.Reject(c.logger, "skipping tcp service annotation(s) from %v due to conflict: %v", source, conflict)
 	}
 	return tcpHost, nil
 }
 
-func (c *converter) addHost(hostname string, source *annotations.Source, ann map[string]string) *hatypes.Host {
+func (c *converter) addHost(hostname string, source *annotations.Source, creation time.Time, ann map[string]string) (*hatypes.Host, error) {
 	// TODO build a stronger tracking
-	host := c.haproxy.Hosts().AcquireHost(hostname)
+	// tracking before acquire, so a rejected claim is parsed again when the owner releases the hostname
 	c.tracker.TrackNames(source.Type, source.FullName(), convtypes.ResourceHAHostname, hostname)
+	host, err := convutils.AcquireHostOwnership(c.options.DynamicConfig, c.haproxy.Hosts(), hostname, source.Namespace, creation)
+	if err != nil {
+		return nil, err
+	}
 	mapper, found := c.hostAnnotations[host]
 	if !found {
 		mapper = c.mapBuilder.NewMapper()
@@ -729,9 +803,9 @@ func (c *converter) addHost(hostname string, source *annotations.Source, ann map
 	}
 	conflict := mapper.AddAnnotations(source, hatypes.CreateHostPathLink(hostname, "/", hatypes.MatchExact), ann)
 	if len(conflict) > 0 {
-		c.logger.Warn("skipping host annotation(s) from %v due to conflict: %v", source, conflict)
+		convtypes.Reject(c.logger, "skipping host annotation(s) from %v due to conflict: %v", source, conflict)
 	}
-	return host
+	return host, nil
 }
 
 func (c *converter) addHeaderMatch(source *annotations.Source, pathLink *hatypes.PathLink, headerMatch string, regex bool) {
@@ -746,7 +820,7 @@ func (c *converter) addHeaderMatch(source *annotations.Source, pathLink *hatypes
 		}
 		if regex {
 			if _, err := regexp.Compile(value); err != nil {
-				c.logger.Warn("ignoring invalid regex on %s: %v", source, err)
+				convtypes.Reject(c.logger, "ignoring invalid regex on %s: %v", source, err)
 				continue
 			}
 		}
@@ -817,16 +891,17 @@ func (c *converter) addBackendWithClass(source *annotations.Source, pathLink *ha
 		c.backendAnnotations[backend] = mapper
 	}
 	// Starting with service annotations, giving precedence
-	_, _, svcann := c.readAnnotations(source, svc.Annotations)
-	mapper.AddAnnotations(&annotations.Source{
+	svcSource := &annotations.Source{
 		Namespace: namespace,
 		Name:      svcName,
 		Type:      convtypes.ResourceService,
-	}, pathLink, svcann)
+	}
+	_, _, svcann := c.readAnnotations(svcSource, svc.Annotations)
+	mapper.AddAnnotations(svcSource, pathLink, svcann)
 	// Merging Ingress annotations
 	conflict := mapper.AddAnnotations(source, pathLink, ann)
 	if len(conflict) > 0 {
-		c.logger.Warn("skipping backend '%s:%s' annotation(s) from %v due to conflict: %v",
+		convtypes.Reject(c.logger, "skipping backend '%s:%s' annotation(s) from %v due to conflict: %v",
 			svcName, svcPort, source, conflict)
 	}
 	// Merging IngressClass Parameters with less priority
@@ -837,7 +912,7 @@ func (c *converter) addBackendWithClass(source *annotations.Source, pathLink *ha
 			// ignoring conflicts. This would really conflict with other Parameters
 			// only if the same host+path is declared twice, but such duplication is
 			// already filtered out in the ingress parsing.
-			_ = mapper.AddAnnotations(source, pathLink, cfg)
+			_ = mapper.AddTrustedAnnotations(source, pathLink, cfg)
 		}
 	}
 	// Configure endpoints
@@ -862,10 +937,154 @@ func (c *converter) addBackendWithClass(source *annotations.Source, pathLink *ha
 				c.logger.Error("error adding endpoints of service '%s': %v", fullSvcName, err)
 			}
 		}
+		if fallback := mapper.Get(ingtypes.BackFallbackService); fallback.Value != "" {
+			if err := c.addFallbackEndpoints(namespace, fallback.Value, backend); err != nil {
+				c.logger.Warn("ignoring fallback service on %s: %v", fallback.Source, err)
+			}
+		}
+	}
+	return backend, nil
+}
+
+func (c *converter) addFallbackEndpoints(namespace, fallback string, backend *hatypes.Backend) error {
+	svcName, svcPort, _ := strings.Cut(fallback, ":")
+	if strings.Contains(svcName, "/") {
+		return fmt.Errorf("fallback service should be in the same namespace: '%s'", svcName)
+	}
+	if svcName == backend.Name {
+		return fmt.Errorf("fallback service cannot be the service itself: '%s'", svcName)
+	}
+	fullSvcName := namespace + "/" + svcName
+	c.tracker.TrackRefName([]convtypes.TrackingRef{
+		{Context: convtypes.ResourceService, UniqueName: fullSvcName},
+		{Context: convtypes.ResourceEndpoints, UniqueName: fullSvcName},
+	}, convtypes.ResourceHABackend, backend.ID)
+	svc, err := c.cache.GetService(namespace, fullSvcName)
+	if err != nil {
+		return err
+	}
+	var port *api.ServicePort
+	if svcPort == "" {
+		if len(svc.Spec.Ports) == 0 {
+			return fmt.Errorf("service '%s' has no port", fullSvcName)
+		}
+		port = &svc.Spec.Ports[0]
+	} else {
+		port = convutils.FindServicePort(svc, svcPort)
+		if port == nil {
+			return fmt.Errorf("port not found: '%s'", svcPort)
+		}
+	}
+	ready, _, err := convutils.CreateEndpoints(c.cache, svc, port, c.options.EnableEPSlices)
+	if err != nil {
+		return err
+	}
+	for _, addr := range ready {
+		backend.AddFallbackEndpoint(addr.IP, addr.Port, addr.TargetRef)
+	}
+	return nil
+}
+
+// addResourceBackend creates a backend without endpoints, which sends the static
+// response configured in the ConfigMap referenced by an Ingress resource backend.
+func (c *converter) addResourceBackend(source *annotations.Source, pathLink *hatypes.PathLink, resource *api.TypedLocalObjectReference, ann map[string]string, ingressClass *networking.IngressClass) (*hatypes.Backend, error) {
+	if (resource.APIGroup != nil && *resource.APIGroup != "") || resource.Kind != "ConfigMap" {
+		return nil, fmt.Errorf("unsupported resource kind: %s", resource.Kind)
+	}
+	fullName := source.Namespace + "/" + resource.Name
+	c.tracker.TrackNames(convtypes.ResourceConfigMap, fullName, convtypes.ResourceHAHostname, pathLink.Hostname())
+	configMap, err := c.cache.GetConfigMap(fullName)
+	if err != nil {
+		return nil, err
+	}
+	response, err := readBackendResponse(configMap.Data, c.readBufSize())
+	if err != nil {
+		return nil, fmt.Errorf("invalid response on ConfigMap '%s': %w", fullName, err)
+	}
+	backend := c.haproxy.Backends().AcquireBackend(source.Namespace, resource.Name, "_resource")
+	c.tracker.TrackNames(source.Type, source.FullName(), convtypes.ResourceHABackend, backend.ID)
+	backend.Response = response
+	mapper, found := c.backendAnnotations[backend]
+	if !found {
+		mapper = c.mapBuilder.NewMapper()
+		c.backendAnnotations[backend] = mapper
+	}
+	conflict := mapper.AddAnnotations(source, pathLink, ann)
+	if len(conflict) > 0 {
+		convtypes.Reject(c.logger, "skipping resource backend '%s' annotation(s) from %v due to conflict: %v",
+			resource.Name, source, conflict)
+	}
+	if ingressClass != nil {
+		if cfg := c.readParameters(ingressClass); cfg != nil {
+			_ = mapper.AddTrustedAnnotations(source, pathLink, cfg)
+		}
 	}
 	return backend, nil
 }
 
+var (
+	httpHeaderNameRegex  = regexp.MustCompile(`^[A-Za-z0-9!#$%&'*+.^_|~-]+$`)
+	httpHeaderValueRegex = regexp.MustCompile(`^[^"\\\x00-\x1f\x7f]+$`)
+)
+
+// haproxyBufSize is the default value of HAProxy's tune.bufsize
+const haproxyBufSize = 16384
+
+// readBufSize returns the tune.bufsize declared in the config-global snippet,
+// or the HAProxy default if it is missing.
+func (c *converter) readBufSize() int {
+	for _, line := range utils.LineToSlice(c.globalConfig.Get(ingtypes.GlobalConfigGlobal).Value) {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "tune.bufsize" {
+			if size, err := strconv.Atoi(fields[1]); err == nil && size > 0 {
+				return size
+			}
+		}
+	}
+	return haproxyBufSize
+}
+
+// readBackendResponse reads the static response of a resource backend. Keys are
+// `status-code`, `content-type`, `headers` with one `name: value` per line, and `body`.
+// HAProxy refuses to start if the body doesn't fit in a buffer, so bodies larger
+// than bufSize are rejected.
+func readBackendResponse(data map[string]string, bufSize int) (*hatypes.BackendResponse, error) {
+	response := &hatypes.BackendResponse{
+		StatusCode:  200,
+		ContentType: "text/plain",
+		Body:        data["body"],
+	}
+	if len(response.Body) > bufSize {
+		return nil, fmt.Errorf("body size of %d bytes is larger than tune.bufsize of %d bytes", len(response.Body), bufSize)
+	}
+	if code := data["status-code"]; code != "" {
+		statusCode, err := strconv.Atoi(code)
+		if err != nil || statusCode < 200 || statusCode > 599 {
+			return nil, fmt.Errorf("invalid status code: %s", code)
+		}
+		response.StatusCode = statusCode
+	}
+	if contentType := strings.TrimSpace(data["content-type"]); contentType != "" {
+		if !httpHeaderValueRegex.MatchString(contentType) {
+			return nil, fmt.Errorf("invalid content type: %s", contentType)
+		}
+		response.ContentType = contentType
+	}
+	for _, line := range utils.LineToSlice(data["headers"]) {
+		if line == "" {
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !found || !httpHeaderNameRegex.MatchString(name) || !httpHeaderValueRegex.MatchString(value) {
+			return nil, fmt.Errorf("invalid header: %s", line)
+		}
+		response.Headers = append(response.Headers, hatypes.HTTPHeader{Name: name, Value: value})
+	}
+	return response, nil
+}
+
 func readDNSPort(headlessService bool, port *api.ServicePort) string {
 	targetPort := port.TargetPort.String()
 	targetPortNum, _ := strconv.Atoi(targetPort)
@@ -973,8 +1192,24 @@ func (c *converter) addEndpoints(svc *api.Service, svcPort *api.ServicePort, bac
 	for _, addr := range ready {
 		backend.AcquireEndpoint(addr.IP, addr.Port, addr.TargetRef)
 	}
+	if c.options.PodReadinessGate {
+		// pods waiting for the readiness gate should be updated as soon as their containers are ready
+		for _, addr := range notReady {
+			if addr.TargetRef != "" {
+				c.tracker.TrackRefName([]convtypes.TrackingRef{{Context: convtypes.ResourcePod, UniqueName: addr.TargetRef}},
+					convtypes.ResourceHABackend, backend.ID)
+			}
+		}
+		for _, addr := range convutils.GatedEndpoints(c.cache, notReady) {
+			backend.AcquireEndpoint(addr.IP, addr.Port, addr.TargetRef)
+		}
+	}
 	if c.globalConfig.Get(ingtypes.GlobalDrainSupport).Bool() {
 		for _, addr := range notReady {
+			if backend.FindEndpoint(addr.Target) != nil {
+				// gated endpoint, already added
+				continue
+			}
 			ep := backend.AcquireEndpoint(addr.IP, addr.Port, addr.TargetRef)
 			ep.Weight = 0
 		}
@@ -1003,6 +1238,10 @@ func (c *converter) readAnnotations(source *annotations.Source, ann map[string]s
 	annHost = make(map[string]string, len(keys))
 	annBack = make(map[string]string, len(keys))
 	for key, value := range keys {
+		// keys read straight from these maps, like ssl-passthrough, would bypass the mapper
+		if !c.policy.Allowed(source, key) {
+			continue
+		}
 		if _, isTCPAnn := ingtypes.AnnTCP[key]; isTCPAnn {
 			annTCP[key] = value
 		} else if _, isHostAnn := ingtypes.AnnHost[key]; isHostAnn {
@@ -1041,7 +1280,7 @@ func (c *converter) readConfigKeys(source *annotations.Source, ann map[string]st
 				if curValue, found := keys[key]; !found {
 					keys[key] = annValue
 				} else if curValue != annValue {
-					c.logger.Warn(
+					convtypes.Reject(c.logger,
 						"annotation '%s' on %s was ignored due to conflict with another annotation(s) for the same '%s' configuration key",
 						annKey, source, key)
 				}
@@ -1072,11 +1311,11 @@ func (c *converter) parseParameters(ingressClass *networking.IngressClass) *ingr
 	}
 	// Currently only ConfigMap is supported
 	if parameters.APIGroup != nil && *parameters.APIGroup != "" {
-		c.logger.Warn("unsupported Parameters' APIGroup on IngressClass '%s': %s", ingressClass.Name, *parameters.APIGroup)
+		convtypes.Reject(c.logger, "unsupported Parameters' APIGroup on IngressClass '%s': %s", ingressClass.Name, *parameters.APIGroup)
 		return nil
 	}
 	if strings.ToLower(parameters.Kind) != "configmap" {
-		c.logger.Warn("unsupported Parameters' Kind on IngressClass '%s': %s", ingressClass.Name, parameters.Kind)
+		convtypes.Reject(c.logger, "unsupported Parameters' Kind on IngressClass '%s': %s", ingressClass.Name, parameters.Kind)
 		return nil
 	}
 	podNamespace := c.cache.GetPodNamespace()
@@ -1100,7 +1339,7 @@ func (c *converter) parseParameters(ingressClass *networking.IngressClass) *ingr
 
 func readServiceNamePort(backend *networking.IngressBackend) (string, string, error) {
 	if backend.Service == nil {
-		return "", "", fmt.Errorf("resource backend is not supported yet")
+		return "", "", fmt.Errorf("resource backend is supported only on HTTP rule paths")
 	}
 	serviceName := backend.Service.Name
 	servicePort := backend.Service.Port.Name
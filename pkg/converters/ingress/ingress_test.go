@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/diff"
 	api "k8s.io/api/core/v1"
@@ -38,6 +39,7 @@ import (
 	ingtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/types"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/tracker"
 	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	convutils "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/utils"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
 	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
 	types_helper "github.com/jcmoraisjr/haproxy-ingress/pkg/types/helper_test"
@@ -177,6 +179,64 @@ func TestSyncSvcUpstream(t *testing.T) {
     port: 8080` + defaultBackendConfig)
 }
 
+func TestSyncSvcFallback(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.createSvc1Ann("default/echo", "8080", "172.17.1.101", map[string]string{
+		"ingress.kubernetes.io/fallback-service": "maintenance",
+	})
+	c.createSvc1("default/maintenance", "8080", "172.17.1.201,172.17.1.202")
+	c.createSvc1Ann("default/app", "8080", "172.17.1.111", map[string]string{
+		"ingress.kubernetes.io/fallback-service": "maintenance:9000",
+	})
+	c.createSvc1Ann("default/self", "8080", "172.17.1.121", map[string]string{
+		"ingress.kubernetes.io/fallback-service": "self",
+	})
+	c.createSvc1Ann("default/web", "8080", "172.17.1.131", map[string]string{
+		"ingress.kubernetes.io/fallback-service": "static",
+	})
+	static, staticEP := c.createSvc1("default/static", "http:80", "172.17.1.211")
+	static.Spec.Ports[0].TargetPort = intstr.FromInt(8080)
+	staticEP.Subsets[0].Ports[0].Port = 8080
+	c.Sync(
+		c.createIng1("default/echo", "echo.example.com", "/", "echo:8080"),
+		c.createIng1("default/app", "app.example.com", "/", "app:8080"),
+		c.createIng1("default/self", "self.example.com", "/", "self:8080"),
+		c.createIng1("default/web", "web.example.com", "/", "web:8080"),
+	)
+
+	c.compareConfigBack(`
+- id: default_app_8080
+  endpoints:
+  - ip: 172.17.1.111
+    port: 8080
+- id: default_echo_8080
+  endpoints:
+  - ip: 172.17.1.101
+    port: 8080
+  fallback:
+  - ip: 172.17.1.201
+    port: 8080
+  - ip: 172.17.1.202
+    port: 8080
+- id: default_self_8080
+  endpoints:
+  - ip: 172.17.1.121
+    port: 8080
+- id: default_web_8080
+  endpoints:
+  - ip: 172.17.1.131
+    port: 8080
+  fallback:
+  - ip: 172.17.1.211
+    port: 8080` + defaultBackendConfig)
+
+	c.logger.CompareLogging(`
+WARN ignoring fallback service on Service 'default/app': port not found: '9000'
+WARN ignoring fallback service on Service 'default/self': fallback service cannot be the service itself: 'self'`)
+}
+
 func TestSyncSvcExternalName(t *testing.T) {
 	createSvc := func(c *testConfig, port string) *api.Service {
 		svc, _, _ := conv_helper.CreateService("default/echo", port, "")
@@ -384,6 +444,53 @@ func TestSyncDrainSupport(t *testing.T) {
 	c.logger.CompareLogging("WARN skipping endpoint 172.17.1.104 of service default/echo: port 'http' was not found")
 }
 
+func TestSyncPodReadinessGate(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	_, ep := c.createSvc1("default/echo", "8080", "172.17.1.101,172.17.1.102,172.17.1.103,172.17.1.104")
+	ss := &ep.Subsets[0]
+	addr := ss.Addresses
+	ss.Addresses = []api.EndpointAddress{addr[0]}
+	ss.NotReadyAddresses = []api.EndpointAddress{addr[1], addr[2], addr[3]}
+	c.cache.PodList = map[string]*api.Pod{}
+	for i, name := range []string{"echo-1", "echo-2", "echo-3", "echo-4"} {
+		if i == 0 {
+			ss.Addresses[0].TargetRef.Name = name
+		} else {
+			ss.NotReadyAddresses[i-1].TargetRef.Name = name
+		}
+		pod := c.createPod1("default/"+name, "172.17.1.10"+strconv.Itoa(i+1), "http:8080")
+		pod.Spec.ReadinessGates = []api.PodReadinessGate{{ConditionType: convutils.ReadinessGateServerUp}}
+		pod.Status.Conditions = []api.PodCondition{{Type: api.ContainersReady, Status: api.ConditionTrue}}
+		c.cache.PodList["default/"+name] = pod
+	}
+	// echo-3: containers not ready
+	c.cache.PodList["default/echo-3"].Status.Conditions[0].Status = api.ConditionFalse
+	// echo-4: does not declare the readiness gate
+	c.cache.PodList["default/echo-4"].Spec.ReadinessGates = nil
+
+	c.cache.SecretTLSPath["system/default"] = "/tls/tls-default.pem"
+	conv := c.createConverter()
+	conv.options.PodReadinessGate = true
+	c.SyncConverter(conv,
+		c.createIng1("default/echo", "echo.example.com", "/", "echo:8080"),
+	)
+
+	c.compareConfigBack(`
+- id: default_echo_8080
+  endpoints:
+  - ip: 172.17.1.101
+    port: 8080
+  - ip: 172.17.1.102
+    port: 8080
+- id: system_default_8080
+  endpoints:
+  - ip: 172.17.0.99
+    port: 8080
+`)
+}
+
 func TestSyncServerIDs(t *testing.T) {
 	c := setup(t)
 	defer c.teardown()
@@ -1054,6 +1161,120 @@ func TestSyncMultiNamespace(t *testing.T) {
     port: 8080` + defaultBackendConfig)
 }
 
+func TestSyncHostOwnership(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.dynconfig.HostOwnership = true
+	c.createSvc1("ns1/echo", "8080", "172.17.0.11")
+	c.createSvc1("ns2/echo", "8080", "172.17.0.12")
+
+	c.Sync(
+		c.createIng1("ns1/echo", "echo.example.com", "/app1", "echo:8080"),
+		c.createIng1("ns1/other", "echo.example.com", "/app3", "echo:8080"),
+		c.createIng1("ns2/echo", "echo.example.com", "/app2", "echo:8080"),
+	)
+
+	c.compareConfigFront(`
+- hostname: echo.example.com
+  paths:
+  - path: /app3
+    backend: ns1_echo_8080
+  - path: /app1
+    backend: ns1_echo_8080`)
+
+	c.logger.CompareLogging(`
+WARN skipping hostname 'echo.example.com' of Ingress 'ns2/echo': hostname 'echo.example.com' is owned by namespace 'ns1'`)
+
+	c.hconfig.Commit()
+	c.cache.Changed.IngressesDel = []*networking.Ingress{
+		c.createIng1("ns1/echo", "echo.example.com", "/app1", "echo:8080"),
+	}
+	c.cache.Changed.IngressesUpd = []*networking.Ingress{
+		c.createIng1("ns1/other", "other.example.com", "/app3", "echo:8080"),
+	}
+	c.Sync()
+
+	c.compareConfigFront(`
+- hostname: echo.example.com
+  paths:
+  - path: /app2
+    backend: ns2_echo_8080
+- hostname: other.example.com
+  paths:
+  - path: /app3
+    backend: ns1_echo_8080`)
+
+	c.logger.CompareLogging(`INFO-V(2) syncing 2 host(s) and 1 backend(s)`)
+}
+
+func TestSyncHostOwnershipTimestamp(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.dynconfig.HostOwnership = true
+	c.createSvc1("ns1/echo", "8080", "172.17.0.11")
+	c.createSvc1("ns2/echo", "8080", "172.17.0.12")
+
+	// hostnames acquired by resources of another converter, e.g. HTTPRoutes
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := c.hconfig.Backends().AcquireBackend("ns3", "web", "8080")
+	for _, hostname := range []string{"echo1.example.com", "echo2.example.com"} {
+		h := c.hconfig.Hosts().AcquireHost(hostname)
+		h.OwnerNamespace = "ns3"
+		h.OwnerTimestamp = created
+		h.AddPath(b, "/web", hatypes.MatchBegin)
+	}
+
+	ing1 := c.createIng1("ns1/echo", "echo1.example.com", "/app1", "echo:8080")
+	ing1.CreationTimestamp = metav1.NewTime(created.Add(-time.Hour))
+	ing2 := c.createIng1("ns2/echo", "echo2.example.com", "/app2", "echo:8080")
+	ing2.CreationTimestamp = metav1.NewTime(created.Add(time.Hour))
+	c.Sync(ing1, ing2)
+
+	c.compareConfigFront(`
+- hostname: echo1.example.com
+  paths:
+  - path: /app1
+    backend: ns1_echo_8080
+- hostname: echo2.example.com
+  paths:
+  - path: /web
+    backend: ns3_web_8080`)
+
+	c.logger.CompareLogging(`
+WARN skipping hostname 'echo2.example.com' of Ingress 'ns2/echo': hostname 'echo2.example.com' is owned by namespace 'ns3'`)
+}
+
+func TestSyncHostOwnershipDelegation(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.dynconfig.HostOwnership = true
+	c.dynconfig.HostOwnershipDelegation = map[string][]string{
+		"example.com": {"ns2"},
+	}
+	c.createSvc1("ns1/echo", "8080", "172.17.0.11")
+	c.createSvc1("ns2/echo", "8080", "172.17.0.12")
+
+	c.Sync(
+		c.createIng1("ns1/echo", "echo.example.com", "/", "echo:8080"),
+		c.createIngTLS1("ns1/echo-tls", "echo.example.com", "/app", "echo:8080", "tls1:echo.example.com"),
+		c.createIng1("ns2/echo", "echo.example.com", "/app2", "echo:8080"),
+	)
+
+	c.compareConfigFront(`
+- hostname: echo.example.com
+  paths:
+  - path: /app2
+    backend: ns2_echo_8080`)
+
+	c.logger.CompareLogging(`
+WARN skipping hostname 'echo.example.com' of Ingress 'ns1/echo': hostname 'echo.example.com' is not delegated to namespace 'ns1'
+WARN skipping hostname 'echo.example.com' of Ingress 'ns1/echo-tls': hostname 'echo.example.com' is not delegated to namespace 'ns1'
+WARN skipping TLS of hostname 'echo.example.com' of Ingress 'ns1/echo-tls': hostname 'echo.example.com' is not delegated to namespace 'ns1'`)
+}
+
 func TestSyncAllowPathDup(t *testing.T) {
 	c := setup(t)
 	defer c.teardown()
@@ -1095,6 +1316,249 @@ func TestSyncAllowPathDup(t *testing.T) {
     maxbodysize: 65536` + defaultBackendConfig)
 }
 
+func TestSyncResourceBackend(t *testing.T) {
+	ing := func(kind string) string {
+		return `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: echo
+  namespace: default
+spec:
+  rules:
+  - host: echo.example.com
+    http:
+      paths:
+      - path: /maintenance
+        pathType: Prefix
+        backend:
+          resource:
+            kind: ` + kind + `
+            name: maint`
+	}
+	testCases := []struct {
+		kind     string
+		global   map[string]string
+		data     map[string]string
+		expected *hatypes.BackendResponse
+		logging  string
+	}{
+		// 0
+		{
+			kind: "ConfigMap",
+			data: map[string]string{},
+			expected: &hatypes.BackendResponse{
+				StatusCode:  200,
+				ContentType: "text/plain",
+			},
+		},
+		// 1
+		{
+			kind: "ConfigMap",
+			data: map[string]string{
+				"status-code":  "503",
+				"content-type": "text/html",
+				"headers":      "Retry-After: 120\nCache-Control: no-cache\n",
+				"body":         "<h1>under maintenance</h1>",
+			},
+			expected: &hatypes.BackendResponse{
+				StatusCode:  503,
+				ContentType: "text/html",
+				Headers: []hatypes.HTTPHeader{
+					{Name: "Retry-After", Value: "120"},
+					{Name: "Cache-Control", Value: "no-cache"},
+				},
+				Body: "<h1>under maintenance</h1>",
+			},
+		},
+		// 2
+		{
+			kind:    "ConfigMap",
+			data:    map[string]string{"status-code": "99"},
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': invalid response on ConfigMap 'default/maint': invalid status code: 99`,
+		},
+		// 3
+		{
+			kind:    "ConfigMap",
+			data:    map[string]string{"headers": "X-Info: \"quoted\""},
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': invalid response on ConfigMap 'default/maint': invalid header: X-Info: "quoted"`,
+		},
+		// 4
+		{
+			kind:    "ConfigMap",
+			data:    map[string]string{"headers": "Retry-After: 120\nX-Info:"},
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': invalid response on ConfigMap 'default/maint': invalid header: X-Info:`,
+		},
+		// 5
+		{
+			kind: "ConfigMap",
+			data: map[string]string{"content-type": " "},
+			expected: &hatypes.BackendResponse{
+				StatusCode:  200,
+				ContentType: "text/plain",
+			},
+		},
+		// 6
+		{
+			kind:    "Secret",
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': unsupported resource kind: Secret`,
+		},
+		// 7
+		{
+			kind:    "ConfigMap",
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': configmap not found: default/maint`,
+		},
+		// 8
+		{
+			kind:    "ConfigMap",
+			data:    map[string]string{"body": strings.Repeat("x", 16385)},
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': invalid response on ConfigMap 'default/maint': body size of 16385 bytes is larger than tune.bufsize of 16384 bytes`,
+		},
+		// 9
+		{
+			kind:   "ConfigMap",
+			global: map[string]string{"config-global": "maxconn 1000\ntune.bufsize 32768\n"},
+			data:   map[string]string{"body": strings.Repeat("x", 16385)},
+			expected: &hatypes.BackendResponse{
+				StatusCode:  200,
+				ContentType: "text/plain",
+				Body:        strings.Repeat("x", 16385),
+			},
+		},
+		// 10
+		{
+			kind:    "ConfigMap",
+			global:  map[string]string{"config-global": "tune.bufsize 8192"},
+			data:    map[string]string{"body": strings.Repeat("x", 8193)},
+			logging: `WARN skipping resource backend on path '/maintenance' of Ingress 'default/echo': invalid response on ConfigMap 'default/maint': body size of 8193 bytes is larger than tune.bufsize of 8192 bytes`,
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		if test.global != nil {
+			c.cache.Changed.GlobalConfigMapDataNew = test.global
+		}
+		if test.data != nil {
+			c.cache.ConfigMapList = map[string]*api.ConfigMap{"default/maint": {Data: test.data}}
+		}
+		c.Sync(c.createObject(ing(test.kind)).(*networking.Ingress))
+		var response *hatypes.BackendResponse
+		if backend := c.hconfig.Backends().FindBackend("default", "maint", "_resource"); backend != nil {
+			response = backend.Response
+			c.compareConfigFront(`
+- hostname: echo.example.com
+  paths:
+  - path: /maintenance
+    match: prefix
+    backend: default_maint__resource`)
+		}
+		if !reflect.DeepEqual(response, test.expected) {
+			t.Errorf("response differs on %d - expected: %+v - actual: %+v", i, test.expected, response)
+		}
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
+func TestSyncPeers(t *testing.T) {
+	pod := func(name, ip string, terminating bool) *api.Pod {
+		pod := &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ingress-controller"},
+			Status:     api.PodStatus{PodIP: ip},
+		}
+		if terminating {
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+		}
+		return pod
+	}
+	testCases := []struct {
+		port     int
+		podName  string
+		pods     []*api.Pod
+		expected []hatypes.PeerServer
+		logging  string
+	}{
+		// 0
+		{
+			port:    0,
+			podName: "ingress-1",
+			pods:    []*api.Pod{pod("ingress-1", "10.0.0.1", false)},
+		},
+		// 1
+		{
+			port:    10000,
+			podName: "ingress-1",
+			pods:    []*api.Pod{pod("ingress-1", "10.0.0.1", false)},
+			expected: []hatypes.PeerServer{
+				{Name: "ingress-1", IP: "10.0.0.1"},
+			},
+		},
+		// 2
+		{
+			port:    10000,
+			podName: "ingress-2",
+			pods: []*api.Pod{
+				pod("ingress-3", "10.0.0.3", false),
+				pod("ingress-2", "10.0.0.2", false),
+				pod("ingress-1", "10.0.0.1", false),
+			},
+			expected: []hatypes.PeerServer{
+				{Name: "ingress-1", IP: "10.0.0.1"},
+				{Name: "ingress-2", IP: "10.0.0.2"},
+				{Name: "ingress-3", IP: "10.0.0.3"},
+			},
+		},
+		// 3
+		{
+			port:    10000,
+			podName: "ingress-1",
+			pods: []*api.Pod{
+				pod("ingress-1", "10.0.0.1", true),
+				pod("ingress-2", "10.0.0.2", true),
+				pod("ingress-3", "", false),
+			},
+			expected: []hatypes.PeerServer{
+				{Name: "ingress-1", IP: "10.0.0.1"},
+			},
+		},
+		// 4
+		{
+			port:    10000,
+			podName: "ingress-1",
+			pods:    []*api.Pod{pod("ingress-1", "", false)},
+			logging: `WARN skipping peers configuration: controller pod 'ingress-1' not found or without IP`,
+		},
+		// 5
+		{
+			port:    10000,
+			podName: "ingress-1",
+			logging: `WARN skipping peers configuration: error reading controller pods: controller pods not found`,
+		},
+		// 6
+		{
+			port:    10000,
+			pods:    []*api.Pod{pod("ingress-1", "10.0.0.1", false)},
+			logging: `WARN skipping peers configuration: POD_NAME envvar was not configured`,
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		c.cache.CtrlPodList = test.pods
+		c.cache.SecretTLSPath["system/default"] = "/tls/tls-default.pem"
+		c.hconfig.Global().Peers.Port = test.port
+		conv := c.createConverter()
+		conv.options.PodName = test.podName
+		c.SyncConverter(conv)
+		peers := c.hconfig.Global().Peers
+		if !reflect.DeepEqual(peers.Servers, test.expected) {
+			t.Errorf("peer servers differ on %d - expected: %+v - actual: %+v", i, test.expected, peers.Servers)
+		}
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
+
 func paramToMap(param ...string) map[string]string {
 	res := make(map[string]string, len(param))
 	for _, p := range param {
@@ -2122,6 +2586,39 @@ func TestSyncAnnFrontDefault(t *testing.T) {
     backend: default_echo_8080`)
 }
 
+func TestSyncAnnGlobalValidate(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.createSvc1Auto()
+	c.cache.Changed.GlobalConfigMapDataNew = map[string]string{
+		"retries":          "many",
+		"retry-on":         "conn-failure, 503",
+		"retry-redispatch": "true",
+	}
+	c.cache.SecretTLSPath["system/default"] = "/tls/tls-default.pem"
+	conv := c.createConverter()
+	c.SyncConverter(conv,
+		c.createIng1("default/echo1", "echo1.example.com", "/", "echo:8080"),
+		c.createIng1("default/echo2", "echo2.example.com", "/", "echo:8080"),
+	)
+
+	expected := map[string]string{
+		"retries":          "",
+		"retry-on":         "conn-failure,503",
+		"retry-redispatch": "true",
+	}
+	actual := map[string]string{}
+	for key := range expected {
+		actual[key] = conv.globalConfig.Get(key).Value
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("global config differs\nexpected: %v\n  actual: %v", expected, actual)
+	}
+	c.logger.CompareLogging(`
+WARN ignoring invalid retries on <global>: many`)
+}
+
 func TestSyncAnnBack(t *testing.T) {
 	c := setup(t)
 	defer c.teardown()
@@ -2371,6 +2868,52 @@ func TestSyncAnnAuthURL(t *testing.T) {
 	c.logger.CompareLogging(`WARN skipping auth-url on Ingress 'default/echo2': service not found: 'default/authsvc2'`)
 }
 
+func TestSyncAnnNamespacePolicy(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.dynconfig.NamespaceAnnotationsDeny = map[string][]string{
+		"tenants": {"ssl-passthrough*", "balance-algorithm"},
+	}
+	ns := &api.Namespace{}
+	ns.Name = "ns1"
+	ns.Labels = map[string]string{annotations.NamespacePolicyLabel: "tenants"}
+	c.cache.NsList["ns1"] = ns
+	c.cache.NsList["ns2"] = &api.Namespace{}
+	c.createSvc1Ann("ns1/echo", "8080", "172.17.0.11", map[string]string{
+		"ingress.kubernetes.io/balance-algorithm": "leastconn",
+	})
+	c.createSvc1("ns2/echo", "8080", "172.17.0.12")
+	c.Sync(
+		c.createIng1Ann("ns1/echo", "echo1.example.com", "/", "echo:8080",
+			map[string]string{
+				"ingress.kubernetes.io/app-root":        "/login",
+				"ingress.kubernetes.io/ssl-passthrough": "true",
+			}),
+		c.createIng1Ann("ns2/echo", "echo2.example.com", "/", "echo:8080",
+			map[string]string{
+				"ingress.kubernetes.io/app-root": "/login",
+			}),
+	)
+
+	c.compareConfigFront(`
+- hostname: echo1.example.com
+  paths:
+  - path: /
+    backend: ns1_echo_8080
+  rootredirect: /login
+- hostname: echo2.example.com
+  paths:
+  - path: /
+    backend: ns2_echo_8080
+  rootredirect: /login
+`)
+
+	c.logger.CompareLogging(`
+WARN ignoring key 'ssl-passthrough' for Ingress 'ns1/echo': not allowed by annotations policy 'tenants' of namespace 'ns1'
+WARN ignoring key 'balance-algorithm' for Service 'ns1/echo': not allowed by annotations policy 'tenants' of namespace 'ns1'`)
+}
+
 func TestSyncAnnPassthrough(t *testing.T) {
 	c := setup(t)
 	defer c.teardown()
@@ -2465,26 +3008,28 @@ WARN skipping redeclared ssl-passthrough root path on Ingress 'default/echo2a'
  * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
 
 type testConfig struct {
-	t       *testing.T
-	decode  func(data []byte, defaults *schema.GroupVersionKind, into runtime.Object) (runtime.Object, *schema.GroupVersionKind, error)
-	hconfig haproxy.Config
-	logger  *types_helper.LoggerMock
-	cache   *conv_helper.CacheMock
-	tracker convtypes.Tracker
-	updater *updaterMock
+	t         *testing.T
+	decode    func(data []byte, defaults *schema.GroupVersionKind, into runtime.Object) (runtime.Object, *schema.GroupVersionKind, error)
+	hconfig   haproxy.Config
+	logger    *types_helper.LoggerMock
+	cache     *conv_helper.CacheMock
+	tracker   convtypes.Tracker
+	updater   *updaterMock
+	dynconfig *convtypes.DynamicConfig
 }
 
 func setup(t *testing.T) *testConfig {
 	logger := types_helper.NewLoggerMock(t)
 	tracker := tracker.NewTracker()
 	c := &testConfig{
-		t:       t,
-		decode:  scheme.Codecs.UniversalDeserializer().Decode,
-		hconfig: haproxy.CreateInstance(logger, haproxy.InstanceOptions{}).Config(),
-		cache:   conv_helper.NewCacheMock(tracker),
-		logger:  logger,
-		tracker: tracker,
-		updater: &updaterMock{},
+		t:         t,
+		decode:    scheme.Codecs.UniversalDeserializer().Decode,
+		hconfig:   haproxy.CreateInstance(logger, haproxy.InstanceOptions{}).Config(),
+		cache:     conv_helper.NewCacheMock(tracker),
+		logger:    logger,
+		tracker:   tracker,
+		updater:   &updaterMock{},
+		dynconfig: &convtypes.DynamicConfig{},
 	}
 	c.createSvc1("system/default", "8080", "172.17.0.99")
 	return c
@@ -2538,7 +3083,7 @@ func (c *testConfig) createConverter() *converter {
 			Cache:            c.cache,
 			Logger:           c.logger,
 			Tracker:          c.tracker,
-			DynamicConfig:    &convtypes.DynamicConfig{},
+			DynamicConfig:    c.dynconfig,
 			DefaultConfig:    defaultConfig,
 			DefaultBackend:   "system/default",
 			DefaultCrtSecret: "system/default",
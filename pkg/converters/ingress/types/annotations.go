@@ -106,10 +106,19 @@ const (
 	BackAllowlistSourceRange   = "allowlist-source-range"
 	BackAllowlistSourceHeader  = "allowlist-source-header"
 	BackAssignBackendServerID  = "assign-backend-server-id"
+	BackAuthAPIKeyHeader       = "auth-apikey-header"
+	BackAuthAPIKeyLabelHeader  = "auth-apikey-label-header"
+	BackAuthAPIKeyQuery        = "auth-apikey-query"
+	BackAuthAPIKeySecret       = "auth-apikey-secret"
 	BackAuthExternalPlacement  = "auth-external-placement"
 	BackAuthHeadersFail        = "auth-headers-fail"
 	BackAuthHeadersRequest     = "auth-headers-request"
 	BackAuthHeadersSucceed     = "auth-headers-succeed"
+	BackAuthJWTAlgorithm       = "auth-jwt-algorithm"
+	BackAuthJWTAudience        = "auth-jwt-audience"
+	BackAuthJWTClaimHeaders    = "auth-jwt-claim-headers"
+	BackAuthJWTIssuer          = "auth-jwt-issuer"
+	BackAuthJWTSecret          = "auth-jwt-secret"
 	BackAuthMethod             = "auth-method"
 	BackAuthRealm              = "auth-realm"
 	BackAuthSecret             = "auth-secret"
@@ -126,6 +135,14 @@ const (
 	BackBlueGreenDeploy        = "blue-green-deploy"
 	BackBlueGreenHeader        = "blue-green-header"
 	BackBlueGreenMode          = "blue-green-mode"
+	BackCacheBypass            = "cache-bypass"
+	BackCacheEnable            = "cache-enable"
+	BackCacheVary              = "cache-vary"
+	BackCompressionAlgo        = "compression-algo"
+	BackCompressionEnable      = "compression-enable"
+	BackCompressionMinSize     = "compression-min-size"
+	BackCompressionOffload     = "compression-offload"
+	BackCompressionTypes       = "compression-types"
 	BackConfigBackend          = "config-backend"
 	BackCorsAllowCredentials   = "cors-allow-credentials"
 	BackCorsAllowHeaders       = "cors-allow-headers"
@@ -137,11 +154,21 @@ const (
 	BackCorsMaxAge             = "cors-max-age"
 	BackDenylistSourceRange    = "denylist-source-range"
 	BackDynamicScaling         = "dynamic-scaling"
+	BackFallbackService        = "fallback-service"
 	BackHeaders                = "headers"
 	BackHealthCheckAddr        = "health-check-addr"
+	BackHealthCheckErrorLimit  = "health-check-error-limit"
+	BackHealthCheckExpBody     = "health-check-expect-body"
+	BackHealthCheckExpStatus   = "health-check-expect-status"
 	BackHealthCheckFallCount   = "health-check-fall-count"
+	BackHealthCheckHeaders     = "health-check-headers"
+	BackHealthCheckHost        = "health-check-host"
 	BackHealthCheckInterval    = "health-check-interval"
+	BackHealthCheckMethod      = "health-check-method"
+	BackHealthCheckObserve     = "health-check-observe"
+	BackHealthCheckOnError     = "health-check-on-error"
 	BackHealthCheckPort        = "health-check-port"
+	BackHealthCheckProtocol    = "health-check-protocol"
 	BackHealthCheckRiseCount   = "health-check-rise-count"
 	BackHealthCheckURI         = "health-check-uri"
 	BackHSTS                   = "hsts"
@@ -152,6 +179,11 @@ const (
 	BackHTTPHeaderMatchRegex   = "http-header-match-regex"
 	BackInitialWeight          = "initial-weight"
 	BackLimitConnections       = "limit-connections"
+	BackLimitRequests          = "limit-requests"
+	BackLimitRequestsExempt    = "limit-requests-exempt"
+	BackLimitRequestsKey       = "limit-requests-key"
+	BackLimitRequestsRetry     = "limit-requests-retry-after"
+	BackLimitRequestsWindow    = "limit-requests-window"
 	BackLimitRPS               = "limit-rps"
 	BackLimitWhitelist         = "limit-whitelist"
 	BackMaxconnServer          = "maxconn-server"
@@ -163,6 +195,10 @@ const (
 	BackProxyBodySize          = "proxy-body-size"
 	BackProxyProtocol          = "proxy-protocol"
 	BackRedirectTo             = "redirect-to"
+	BackRetries                = "retries"
+	BackRetryOn                = "retry-on"
+	BackRetryRedispatch        = "retry-redispatch"
+	BackRetryTimeoutConnect    = "retry-timeout-connect"
 	BackRewriteTarget          = "rewrite-target"
 	BackSlotsMinFree           = "slots-min-free"
 	BackSecureBackends         = "secure-backends"
@@ -33,6 +33,9 @@ const (
 	GlobalBindIPAddrPrometheus         = "bind-ip-addr-prometheus"
 	GlobalBindIPAddrStats              = "bind-ip-addr-stats"
 	GlobalBindIPAddrTCP                = "bind-ip-addr-tcp"
+	GlobalCacheMaxAge                  = "cache-max-age"
+	GlobalCacheMaxObjectSize           = "cache-max-object-size"
+	GlobalCacheSize                    = "cache-size"
 	GlobalCloseSessionsDuration        = "close-sessions-duration"
 	GlobalConfigDefaults               = "config-defaults"
 	GlobalConfigFrontend               = "config-frontend"
@@ -46,6 +49,7 @@ const (
 	GlobalCPUMap                       = "cpu-map"
 	GlobalCrossNamespaceSecretsCA      = "cross-namespace-secrets-ca"
 	GlobalCrossNamespaceSecretsCrt     = "cross-namespace-secrets-crt"
+	GlobalCrossNamespaceSecretsJWT     = "cross-namespace-secrets-jwt"
 	GlobalCrossNamespaceSecretsPasswd  = "cross-namespace-secrets-passwd"
 	GlobalCrossNamespaceServices       = "cross-namespace-services"
 	GlobalDefaultBackendRedirect       = "default-backend-redirect"
@@ -63,6 +67,8 @@ const (
 	GlobalFrontingProxyPort            = "fronting-proxy-port"
 	GlobalGroupname                    = "groupname"
 	GlobalHealthzPort                  = "healthz-port"
+	GlobalHostOwnership                = "host-ownership"
+	GlobalHostOwnershipDelegation      = "host-ownership-delegation"
 	GlobalHTTPLogFormat                = "http-log-format"
 	GlobalHTTPPort                     = "http-port"
 	GlobalHTTPResponse200              = "http-response-200"
@@ -100,6 +106,8 @@ const (
 	GlobalModsecurityTimeoutProcessing = "modsecurity-timeout-processing"
 	GlobalModsecurityTimeoutServer     = "modsecurity-timeout-server"
 	GlobalModsecurityUseCoraza         = "modsecurity-use-coraza"
+	GlobalNamespaceAnnotationsAllow    = "namespace-annotations-allow"
+	GlobalNamespaceAnnotationsDeny     = "namespace-annotations-deny"
 	GlobalNbprocBalance                = "nbproc-balance"
 	GlobalNbprocSSL                    = "nbproc-ssl"
 	GlobalNbthread                     = "nbthread"
@@ -107,6 +115,7 @@ const (
 	GlobalNoTLSRedirectLocations       = "no-tls-redirect-locations"
 	GlobalOriginalForwardedForHdr      = "original-forwarded-for-hdr"
 	GlobalPathTypeOrder                = "path-type-order"
+	GlobalPeersPort                    = "peers-port"
 	GlobalPrometheusPort               = "prometheus-port"
 	GlobalRealIPHdr                    = "real-ip-hdr"
 	GlobalRedirectFromCode             = "redirect-from-code"
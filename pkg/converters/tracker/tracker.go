@@ -113,6 +113,29 @@ func (t *tracker) QueryLinks(input convtypes.TrackingLinks, removeMatches bool)
 	return output
 }
 
+// ListLinks lists the direct links of all the tracked resources
+func (t *tracker) ListLinks() convtypes.TrackingGraph {
+	output := convtypes.TrackingGraph{}
+	for ctx, names := range t.tracking {
+		outnames := make(map[string][]convtypes.TrackingRef, len(names))
+		for name, refs := range names {
+			reflist := make([]convtypes.TrackingRef, 0, len(refs))
+			for ref := range refs {
+				reflist = append(reflist, ref)
+			}
+			sort.Slice(reflist, func(i, j int) bool {
+				if reflist[i].Context == reflist[j].Context {
+					return reflist[i].UniqueName < reflist[j].UniqueName
+				}
+				return reflist[i].Context < reflist[j].Context
+			})
+			outnames[name] = reflist
+		}
+		output[ctx] = outnames
+	}
+	return output
+}
+
 func (t *tracker) ClearLinks() {
 	t.tracking = trackingMap{}
 }
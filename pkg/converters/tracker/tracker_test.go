@@ -18,6 +18,7 @@ package tracker
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -346,6 +347,54 @@ ingress
 	}
 }
 
+func TestListLinks(t *testing.T) {
+	ing1 := convtypes.TrackingRef{Context: "ingress", UniqueName: "default/ing1"}
+	back1 := convtypes.TrackingRef{Context: "backend", UniqueName: "default_echo1_8080"}
+	back2 := convtypes.TrackingRef{Context: "backend", UniqueName: "default_echo2_8080"}
+	host1 := convtypes.TrackingRef{Context: "hostname", UniqueName: "domain.local"}
+
+	c := setup(t)
+	defer c.teardown()
+	c.tracker.TrackRefs(ing1, back2)
+	c.tracker.TrackRefs(ing1, back1)
+	c.tracker.TrackRefs(ing1, host1)
+	links := c.tracker.ListLinks()
+
+	expected := convtypes.TrackingGraph{
+		"backend": {
+			"default_echo1_8080": {ing1},
+			"default_echo2_8080": {ing1},
+		},
+		"hostname": {
+			"domain.local": {ing1},
+		},
+		"ingress": {
+			"default/ing1": {back1, back2, host1},
+		},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("expected %v but was %v", expected, links)
+	}
+
+	// changing the output should not change the tracking state
+	links["ingress"]["default/ing1"][0] = host1
+	c.compareTrackingMap(0, `
+backend
+  default_echo1_8080
+    ingress:default/ing1
+  default_echo2_8080
+    ingress:default/ing1
+hostname
+  domain.local
+    ingress:default/ing1
+ingress
+  default/ing1
+    backend:default_echo1_8080
+    backend:default_echo2_8080
+    hostname:domain.local
+`)
+}
+
 type testConfig struct {
 	t       *testing.T
 	tracker *tracker
@@ -48,13 +48,17 @@ type Cache interface {
 	GetEndpoints(service *api.Service) (*api.Endpoints, error)
 	GetConfigMap(configMapName string) (*api.ConfigMap, error)
 	GetNamespace(name string) (*api.Namespace, error)
+	GetNamespaceList() ([]*api.Namespace, error)
 	GetTerminatingPods(service *api.Service, track []TrackingRef) ([]*api.Pod, error)
 	GetPod(podName string) (*api.Pod, error)
 	GetPodNamespace() string
+	GetControllerPodList() ([]*api.Pod, error)
 	GetTLSSecretPath(defaultNamespace, secretName string, track []TrackingRef) (CrtFile, error)
 	GetCASecretPath(defaultNamespace, secretName string, track []TrackingRef) (ca, crl File, err error)
 	GetDHSecretPath(defaultNamespace, secretName string) (File, error)
 	GetPasswdSecretContent(defaultNamespace, secretName string, track []TrackingRef) ([]byte, error)
+	GetJWTSecretContent(defaultNamespace, secretName string, track []TrackingRef) ([]byte, error)
+	GetAPIKeySecretContent(defaultNamespace, secretName string, track []TrackingRef) (map[string][]byte, error)
 	SwapChangedObjects() *ChangedObjects
 	UpdateStatus(obj client.Object)
 	GetEndpointSlices(service *api.Service) ([]*discoveryv1.EndpointSlice, error)
@@ -125,6 +129,7 @@ const (
 	ResourceEndpoints ResourceType = "Endpoints"
 	ResourceSecret    ResourceType = "Secret"
 	ResourcePod       ResourceType = "Pod"
+	ResourceNamespace ResourceType = "Namespace"
 
 	ResourceHATCPService ResourceType = "HATCPService"
 	ResourceHAHostname   ResourceType = "HAHostname"
@@ -134,6 +139,10 @@ const (
 	ResourceAcmeData ResourceType = "AcmeData"
 )
 
+// ResourceBackendLabel flags ConfigMaps used as Ingress resource backends, so
+// changes on them are watched and reflected in the configuration.
+const ResourceBackendLabel = "haproxy-ingress.github.io/resource-backend"
+
 // TrackingRef ...
 type TrackingRef struct {
 	Context    ResourceType
@@ -143,12 +152,16 @@ type TrackingRef struct {
 // TrackingLinks ...
 type TrackingLinks map[ResourceType][]string
 
+// TrackingGraph ...
+type TrackingGraph map[ResourceType]map[string][]TrackingRef
+
 // Tracker ...
 type Tracker interface {
 	TrackNames(leftContext ResourceType, leftName string, rightContext ResourceType, rightName string)
 	TrackRefName(left []TrackingRef, rightContext ResourceType, rightName string)
 	TrackRefs(left, right TrackingRef)
 	QueryLinks(input TrackingLinks, removeMatches bool) TrackingLinks
+	ListLinks() TrackingGraph
 	ClearLinks()
 }
 
@@ -157,6 +170,14 @@ type AnnotationReader interface {
 	ReadAnnotations(backend *hatypes.Backend, services []*api.Service, pathLinks []*hatypes.PathLink)
 }
 
+// ObjectReferrer is implemented by the sources of the converters' resources.
+// Logger implementations can use it to identify the Kubernetes object that a
+// warning refers to. ObjectReference returns nil if the source does not refer
+// to a Kubernetes object.
+type ObjectReferrer interface {
+	ObjectReference() *api.ObjectReference
+}
+
 // File ...
 type File struct {
 	Filename string
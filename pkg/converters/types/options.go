@@ -18,6 +18,7 @@ package types
 
 import (
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
 )
 
 // ConverterOptions ...
@@ -37,6 +38,7 @@ type ConverterOptions struct {
 	FakeCrtFile      CrtFile
 	FakeCAFile       CrtFile
 	AnnotationPrefix []string
+	PodName          string
 	DisableKeywords  []string
 	AcmeTrackTLSAnn  bool
 	TrackInstances   bool
@@ -45,14 +47,22 @@ type ConverterOptions struct {
 	HasGatewayV1     bool
 	HasTCPRouteA2    bool
 	EnableEPSlices   bool
+	PodReadinessGate bool
+	NamespaceLabels  bool
+	HAProxyVersion   utils.HAProxyVersion
 }
 
 // DynamicConfig ...
 type DynamicConfig struct {
 	CrossNamespaceSecretCertificate bool
 	CrossNamespaceSecretCA          bool
+	CrossNamespaceSecretJWT         bool
 	CrossNamespaceSecretPasswd      bool
 	CrossNamespaceServices          bool
+	HostOwnership                   bool
+	HostOwnershipDelegation         map[string][]string
+	NamespaceAnnotationsAllow       map[string][]string
+	NamespaceAnnotationsDeny        map[string][]string
 	// config from the command-line for backward compatibility
 	StaticCrossNamespaceSecrets bool
 }
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/types"
+)
+
+// Rejecter is implemented by loggers that handle the warnings of an invalid,
+// unsupported, conflicting or not allowed configuration apart from the other
+// ones, e.g. the admission webhook denies the resource that caused them.
+type Rejecter interface {
+	Reject(msg string, args ...interface{})
+}
+
+// Reject logs a warning about an invalid, unsupported, conflicting or not
+// allowed configuration, which is sent to Reject() if logger is a Rejecter.
+func Reject(logger types.Logger, msg string, args ...interface{}) {
+	if rejecter, ok := logger.(Rejecter); ok {
+		rejecter.Reject(msg, args...)
+		return
+	}
+	logger.Warn(msg, args...)
+}
+
+// ConflictError is returned when a resource claims a hostname, path or port
+// that was already claimed by another resource, or that it is not allowed to.
+type ConflictError struct {
+	msg string
+}
+
+// ConflictErrorf ...
+func ConflictErrorf(format string, args ...interface{}) error {
+	return &ConflictError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *ConflictError) Error() string {
+	return e.msg
+}
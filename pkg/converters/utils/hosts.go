@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+// AcquireHostOwnership acquires hostname on behalf of namespace, taking the
+// hostname ownership configuration into account. The namespace of the oldest
+// resource that claims a hostname owns it, claims from other namespaces are
+// rejected, and a hostname under a delegated domain can only be claimed by
+// one of its delegated namespaces. The default host is never owned. A non nil
+// error means that the hostname was not acquired.
+//
+// Converters parse their resources from the oldest to the newest one, but a
+// hostname might have already been acquired by a newer resource of another
+// converter. In this case the content of the newer resource is removed from
+// the hostname, so the result does not depend on which converter runs first.
+func AcquireHostOwnership(dynconfig *types.DynamicConfig, hosts *hatypes.Hosts, hostname, namespace string, creation time.Time) (*hatypes.Host, error) {
+	if dynconfig == nil || !dynconfig.HostOwnership || hostname == hatypes.DefaultHost {
+		return hosts.AcquireHost(hostname), nil
+	}
+	if namespaces, found := findHostDelegation(dynconfig.HostOwnershipDelegation, hostname); found && !contains(namespaces, namespace) {
+		return nil, types.ConflictErrorf("hostname '%s' is not delegated to namespace '%s'", hostname, namespace)
+	}
+	host := hosts.FindHost(hostname)
+	if host != nil && host.OwnerNamespace != "" && host.OwnerNamespace != namespace {
+		if !creation.Before(host.OwnerTimestamp) {
+			return nil, types.ConflictErrorf("hostname '%s' is owned by namespace '%s'", hostname, host.OwnerNamespace)
+		}
+		hosts.RemoveAll([]string{hostname})
+		host = nil
+	}
+	if host == nil {
+		host = hosts.AcquireHost(hostname)
+	}
+	if host.OwnerNamespace != namespace || creation.Before(host.OwnerTimestamp) {
+		host.OwnerNamespace = namespace
+		host.OwnerTimestamp = creation
+	}
+	return host, nil
+}
+
+// findHostDelegation returns the namespaces that hostname is delegated to.
+// A delegated domain applies to itself and all of its subdomains, the most
+// specific one wins.
+func findHostDelegation(delegation map[string][]string, hostname string) (namespaces []string, found bool) {
+	domain := strings.ToLower(strings.TrimPrefix(hostname, "*."))
+	for {
+		if namespaces, found := delegation[domain]; found {
+			return namespaces, true
+		}
+		pos := strings.Index(domain, ".")
+		if pos < 0 {
+			return nil, false
+		}
+		domain = domain[pos+1:]
+	}
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+func TestAcquireHostOwnership(t *testing.T) {
+	type claim struct {
+		hostname  string
+		namespace string
+		creation  int
+	}
+	testCases := []struct {
+		ownership  bool
+		delegation map[string][]string
+		claims     []claim
+		expErrors  []string
+		expOwner   string
+	}{
+		// 0
+		{
+			claims:    []claim{{"d1.local", "ns1", 0}, {"d1.local", "ns2", 0}},
+			expErrors: []string{"", ""},
+		},
+		// 1
+		{
+			ownership: true,
+			claims:    []claim{{"d1.local", "ns1", 0}, {"d1.local", "ns1", 0}},
+			expErrors: []string{"", ""},
+			expOwner:  "ns1",
+		},
+		// 2
+		{
+			ownership: true,
+			claims:    []claim{{"d1.local", "ns1", 0}, {"d1.local", "ns2", 0}},
+			expErrors: []string{"", "hostname 'd1.local' is owned by namespace 'ns1'"},
+			expOwner:  "ns1",
+		},
+		// 3
+		{
+			ownership: true,
+			claims:    []claim{{hatypes.DefaultHost, "ns1", 0}, {hatypes.DefaultHost, "ns2", 0}},
+			expErrors: []string{"", ""},
+		},
+		// 4
+		{
+			ownership:  true,
+			delegation: map[string][]string{"app.local": {"ns2"}},
+			claims:     []claim{{"d1.app.local", "ns1", 0}, {"d1.app.local", "ns2", 0}},
+			expErrors:  []string{"hostname 'd1.app.local' is not delegated to namespace 'ns1'", ""},
+			expOwner:   "ns2",
+		},
+		// 5
+		{
+			ownership:  true,
+			delegation: map[string][]string{"app.local": {"ns2"}, "api.app.local": {"ns1"}},
+			claims:     []claim{{"v1.api.app.local", "ns2", 0}, {"v1.api.app.local", "ns1", 0}},
+			expErrors:  []string{"hostname 'v1.api.app.local' is not delegated to namespace 'ns2'", ""},
+			expOwner:   "ns1",
+		},
+		// 6
+		{
+			ownership:  true,
+			delegation: map[string][]string{"app.local": {"ns1", "ns2"}},
+			claims:     []claim{{"*.app.local", "ns2", 0}, {"*.app.local", "ns1", 0}},
+			expErrors:  []string{"", "hostname '*.app.local' is owned by namespace 'ns2'"},
+			expOwner:   "ns2",
+		},
+		// 7
+		{
+			ownership:  true,
+			delegation: map[string][]string{"app.local": {"ns2"}},
+			claims:     []claim{{"d1.other.local", "ns1", 0}},
+			expErrors:  []string{""},
+			expOwner:   "ns1",
+		},
+		// 8
+		{
+			ownership: true,
+			claims:    []claim{{"d1.local", "ns1", 20}, {"d1.local", "ns2", 10}},
+			expErrors: []string{"", ""},
+			expOwner:  "ns2",
+		},
+		// 9
+		{
+			ownership: true,
+			claims:    []claim{{"d1.local", "ns1", 20}, {"d1.local", "ns1", 10}, {"d1.local", "ns2", 15}},
+			expErrors: []string{"", "", "hostname 'd1.local' is owned by namespace 'ns1'"},
+			expOwner:  "ns1",
+		},
+		// 10
+		{
+			ownership: true,
+			claims:    []claim{{"d1.local", "ns1", 10}, {"d1.local", "ns2", 10}},
+			expErrors: []string{"", "hostname 'd1.local' is owned by namespace 'ns1'"},
+			expOwner:  "ns1",
+		},
+	}
+	for i, test := range testCases {
+		dynconfig := &types.DynamicConfig{
+			HostOwnership:           test.ownership,
+			HostOwnershipDelegation: test.delegation,
+		}
+		hosts := hatypes.CreateHosts()
+		var hostname string
+		for j, claim := range test.claims {
+			hostname = claim.hostname
+			var errStr string
+			host, err := AcquireHostOwnership(dynconfig, hosts, claim.hostname, claim.namespace, time.Unix(int64(claim.creation), 0))
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != test.expErrors[j] {
+				t.Errorf("error differs on %d/%d - expected: '%s' - actual: '%s'", i, j, test.expErrors[j], errStr)
+			}
+			if host != nil {
+				host.AddPath(&hatypes.Backend{}, "/"+claim.namespace, hatypes.MatchBegin)
+			}
+		}
+		var owner string
+		if host := hosts.FindHost(hostname); host != nil {
+			owner = host.OwnerNamespace
+			for _, path := range host.Paths {
+				if test.expOwner != "" && path.Path() != "/"+owner {
+					t.Errorf("path of a former owner found on %d: %s", i, path.Path())
+				}
+			}
+		}
+		if owner != test.expOwner {
+			t.Errorf("owner differs on %d - expected: '%s' - actual: '%s'", i, test.expOwner, owner)
+		}
+	}
+}
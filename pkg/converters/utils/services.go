@@ -182,6 +182,53 @@ func CreateEndpoints(cache types.Cache, svc *api.Service, svcPort *api.ServicePo
 	return ready, notReady, err
 }
 
+// ReadinessGateServerUp is the condition type of the pod readiness gate
+// managed by the controller. The condition is set once haproxy reports the
+// pod as an UP server.
+const ReadinessGateServerUp api.PodConditionType = "haproxy-ingress.github.io/server-up"
+
+// ReadinessGatePending reports whether a pod declares the server up readiness
+// gate, its containers are ready, and the controller has not set the condition
+// yet. The pod is not ready only because it is waiting for haproxy.
+func ReadinessGatePending(pod *api.Pod) bool {
+	var hasGate bool
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == ReadinessGateServerUp {
+			hasGate = true
+			break
+		}
+	}
+	if !hasGate {
+		return false
+	}
+	var containersReady, serverUp bool
+	for _, cond := range pod.Status.Conditions {
+		switch cond.Type {
+		case api.ContainersReady:
+			containersReady = cond.Status == api.ConditionTrue
+		case ReadinessGateServerUp:
+			serverUp = cond.Status == api.ConditionTrue
+		}
+	}
+	return containersReady && !serverUp && pod.DeletionTimestamp == nil
+}
+
+// GatedEndpoints returns the not ready endpoints whose pods are waiting for
+// the server up readiness gate. These endpoints should be added as regular
+// servers, so haproxy can check and report them as UP.
+func GatedEndpoints(cache types.Cache, notReady []*Endpoint) []*Endpoint {
+	var gated []*Endpoint
+	for _, ep := range notReady {
+		if ep.TargetRef == "" {
+			continue
+		}
+		if pod, err := cache.GetPod(ep.TargetRef); err == nil && ReadinessGatePending(pod) {
+			gated = append(gated, ep)
+		}
+	}
+	return gated
+}
+
 func matchPort(svcPort *api.ServicePort, epPort *api.EndpointPort) bool {
 	if epPort.Protocol != api.ProtocolTCP {
 		return false
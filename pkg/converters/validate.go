@@ -0,0 +1,423 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/gateway"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/ingress/annotations"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+// Validate runs the converters against obj and the resources of the cache that
+// share a hostname, or an Ingress a service, with it. obj replaces its stored
+// version, and the warnings that refer to obj are returned.
+// Warnings about invalid values, conflicting hostnames or paths, and disabled
+// keywords are returned as errs, which should reject obj. Supported objects
+// are Ingress, IngressClass and HTTPRoute v1. haproxy should be an empty model,
+// and the tracker, cache and dynamic config of options should not be shared
+// with the running converters. options is copied and left unchanged.
+func Validate(haproxy haproxy.Config, options *convtypes.ConverterOptions, globalConfig map[string]string, obj client.Object) (errs, warnings []string, err error) {
+	logger := &validateLogger{
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+	switch obj := obj.(type) {
+	case *networking.IngressClass:
+		logger.all = true
+		validateIngressClass(logger, options.Cache, obj)
+		return logger.errs, logger.warnings, nil
+	case *networking.Ingress:
+		logger.kind = string(convtypes.ResourceIngress)
+	case *gatewayv1.HTTPRoute:
+		logger.kind = "HTTPRoute"
+	default:
+		return nil, nil, fmt.Errorf("unsupported resource type: %T", obj)
+	}
+
+	// objects being created do not have a creation timestamp yet, which would
+	// wrongly give them precedence on hostname and path claims
+	obj = obj.DeepCopyObject().(client.Object)
+	if ts := obj.GetCreationTimestamp(); ts.IsZero() {
+		obj.SetCreationTimestamp(metav1.NewTime(time.Now()))
+	}
+	if _, ok := obj.(*gatewayv1.HTTPRoute); ok {
+		// gateway converter names its sources from the object kind
+		obj.GetObjectKind().SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	}
+
+	opt := *options
+	opt.Logger = logger
+	opt.Cache = newValidateCache(options.Cache, obj)
+	changed := &convtypes.ChangedObjects{
+		GlobalConfigMapDataCur: globalConfig,
+		NeedFullSync:           true,
+	}
+	ingressConverter := ingress.NewIngressConverter(&opt, haproxy, changed)
+	gatewayConverter := gateway.NewGatewayConverter(&opt, haproxy, changed, ingressConverter)
+	if opt.HasGatewayV1 {
+		gatewayConverter.Sync(true, &gatewayv1.Gateway{})
+	}
+	if opt.HasGatewayB1 {
+		gatewayConverter.Sync(true, &gatewayv1beta1.Gateway{})
+	}
+	if opt.HasGatewayA2 {
+		gatewayConverter.Sync(true, &gatewayv1alpha2.Gateway{})
+	}
+	ingressConverter.Sync(true)
+	return logger.errs, logger.warnings, nil
+}
+
+func validateIngressClass(logger *validateLogger, cache convtypes.Cache, ingressClass *networking.IngressClass) {
+	parameters := ingressClass.Spec.Parameters
+	if parameters == nil {
+		return
+	}
+	if parameters.APIGroup != nil && *parameters.APIGroup != "" {
+		logger.Reject("unsupported Parameters' APIGroup on IngressClass '%s': %s", ingressClass.Name, *parameters.APIGroup)
+		return
+	}
+	if strings.ToLower(parameters.Kind) != "configmap" {
+		logger.Reject("unsupported Parameters' Kind on IngressClass '%s': %s", ingressClass.Name, parameters.Kind)
+		return
+	}
+	podNamespace := cache.GetPodNamespace()
+	if podNamespace == "" {
+		logger.Warn("need to configure POD_NAMESPACE to use ConfigMap on IngressClass '%s'", ingressClass.Name)
+		return
+	}
+	configMap, err := cache.GetConfigMap(podNamespace + "/" + parameters.Name)
+	if err != nil {
+		logger.Warn("error reading ConfigMap on IngressClass '%s': %v", ingressClass.Name, err)
+		return
+	}
+	source := &annotations.Source{
+		Namespace: podNamespace,
+		Name:      parameters.Name,
+		Type:      convtypes.ResourceConfigMap,
+	}
+	mapper := annotations.NewMapBuilder(logger, map[string]string{}).NewMapper()
+	_ = mapper.AddTrustedAnnotations(source, hatypes.CreatePathLink("/", hatypes.MatchBegin), configMap.Data)
+}
+
+// validateCache replaces the stored version of the object being validated, and
+// lists only the objects that can conflict with it: the ones that share one of
+// its hostnames, and Ingress resources that share one of its services, whose
+// backend configuration is merged.
+type validateCache struct {
+	convtypes.Cache
+	obj      client.Object
+	hosts    map[string]bool
+	services map[string]bool
+}
+
+func newValidateCache(cache convtypes.Cache, obj client.Object) *validateCache {
+	c := &validateCache{
+		Cache:    cache,
+		obj:      obj,
+		hosts:    map[string]bool{},
+		services: map[string]bool{},
+	}
+	switch obj := obj.(type) {
+	case *networking.Ingress:
+		for _, host := range ingressHostnames(obj) {
+			c.hosts[host] = true
+		}
+		for _, svc := range ingressServices(obj) {
+			c.services[svc] = true
+		}
+	case *gatewayv1.HTTPRoute:
+		for _, host := range c.httpRouteHostnames(obj, &obj.Spec, c.v1Listeners) {
+			c.hosts[host] = true
+		}
+	}
+	return c
+}
+
+func (c *validateCache) GetIngress(ingressName string) (*networking.Ingress, error) {
+	if ing, ok := c.obj.(*networking.Ingress); ok && ingressName == ing.Namespace+"/"+ing.Name {
+		return ing, nil
+	}
+	return c.Cache.GetIngress(ingressName)
+}
+
+func (c *validateCache) GetIngressList() ([]*networking.Ingress, error) {
+	ingList, err := c.Cache.GetIngressList()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*networking.Ingress, 0, len(ingList))
+	for _, ing := range ingList {
+		if matchAny(c.hosts, ingressHostnames(ing)) || matchAny(c.services, ingressServices(ing)) {
+			out = append(out, ing)
+		}
+	}
+	if ing, ok := c.obj.(*networking.Ingress); ok {
+		return replaceObject(out, ing), nil
+	}
+	return out, nil
+}
+
+func (c *validateCache) GetHTTPRouteA2List() ([]*gatewayv1alpha2.HTTPRoute, error) {
+	httpRoutes, err := c.Cache.GetHTTPRouteA2List()
+	if err != nil {
+		return nil, err
+	}
+	return filterObjects(httpRoutes, func(httpRoute *gatewayv1alpha2.HTTPRoute) bool {
+		return matchAny(c.hosts, c.httpRouteHostnames(httpRoute, &httpRoute.Spec, c.a2Listeners))
+	}), nil
+}
+
+func (c *validateCache) GetHTTPRouteB1List() ([]*gatewayv1beta1.HTTPRoute, error) {
+	httpRoutes, err := c.Cache.GetHTTPRouteB1List()
+	if err != nil {
+		return nil, err
+	}
+	return filterObjects(httpRoutes, func(httpRoute *gatewayv1beta1.HTTPRoute) bool {
+		return matchAny(c.hosts, c.httpRouteHostnames(httpRoute, &httpRoute.Spec, c.b1Listeners))
+	}), nil
+}
+
+func (c *validateCache) GetHTTPRouteList() ([]*gatewayv1.HTTPRoute, error) {
+	httpRoutes, err := c.Cache.GetHTTPRouteList()
+	if err != nil {
+		return nil, err
+	}
+	httpRoutes = filterObjects(httpRoutes, func(httpRoute *gatewayv1.HTTPRoute) bool {
+		return matchAny(c.hosts, c.httpRouteHostnames(httpRoute, &httpRoute.Spec, c.v1Listeners))
+	})
+	if httpRoute, ok := c.obj.(*gatewayv1.HTTPRoute); ok {
+		return replaceObject(httpRoutes, httpRoute), nil
+	}
+	return httpRoutes, nil
+}
+
+// GetTCPRouteList does not list TCPRoutes, they are bound to listener ports,
+// not to the hostnames and paths of Ingress and HTTPRoute resources.
+func (c *validateCache) GetTCPRouteList() ([]*gatewayv1alpha2.TCPRoute, error) {
+	return nil, nil
+}
+
+// GetControllerPodList does not list the controller pods, peers configuration
+// does not depend on the object being validated.
+func (c *validateCache) GetControllerPodList() ([]*api.Pod, error) {
+	return nil, nil
+}
+
+func (c *validateCache) SwapChangedObjects() *convtypes.ChangedObjects {
+	return &convtypes.ChangedObjects{NeedFullSync: true}
+}
+
+func (c *validateCache) UpdateStatus(obj client.Object) {}
+
+func (c *validateCache) v1Listeners(namespace, name string) []gatewayv1.Listener {
+	if gw, err := c.Cache.GetGateway(namespace, name); err == nil && gw != nil {
+		return gw.Spec.Listeners
+	}
+	return nil
+}
+
+func (c *validateCache) b1Listeners(namespace, name string) []gatewayv1.Listener {
+	if gw, err := c.Cache.GetGatewayB1(namespace, name); err == nil && gw != nil {
+		return gw.Spec.Listeners
+	}
+	return nil
+}
+
+func (c *validateCache) a2Listeners(namespace, name string) []gatewayv1.Listener {
+	if gw, err := c.Cache.GetGatewayA2(namespace, name); err == nil && gw != nil {
+		return gw.Spec.Listeners
+	}
+	return nil
+}
+
+// httpRouteHostnames returns the hostnames that an HTTPRoute claims: a listener
+// hostname replaces the ones declared in the route, see gateway's filterHostnames.
+func (c *validateCache) httpRouteHostnames(httpRoute client.Object, spec *gatewayv1.HTTPRouteSpec, listeners func(namespace, name string) []gatewayv1.Listener) []string {
+	routeHostnames := make([]string, len(spec.Hostnames))
+	for i, hostname := range spec.Hostnames {
+		routeHostnames[i] = validateHostname(string(hostname))
+	}
+	if len(routeHostnames) == 0 {
+		routeHostnames = []string{hatypes.DefaultHost}
+	}
+	var hostnames []string
+	for _, parentRef := range spec.ParentRefs {
+		namespace := httpRoute.GetNamespace()
+		if parentRef.Namespace != nil && *parentRef.Namespace != "" {
+			namespace = string(*parentRef.Namespace)
+		}
+		for _, listener := range listeners(namespace, string(parentRef.Name)) {
+			if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+				continue
+			}
+			if listener.Hostname != nil && *listener.Hostname != "" && *listener.Hostname != "*" {
+				hostnames = append(hostnames, string(*listener.Hostname))
+			} else {
+				hostnames = append(hostnames, routeHostnames...)
+			}
+		}
+	}
+	if len(hostnames) == 0 {
+		// gateway not found, keep the conflicts of the route's own hostnames
+		return routeHostnames
+	}
+	return hostnames
+}
+
+func ingressHostnames(ing *networking.Ingress) []string {
+	var hostnames []string
+	if ing.Spec.DefaultBackend != nil {
+		hostnames = append(hostnames, hatypes.DefaultHost)
+	}
+	for _, rule := range ing.Spec.Rules {
+		hostnames = append(hostnames, validateHostname(rule.Host))
+	}
+	return hostnames
+}
+
+func ingressServices(ing *networking.Ingress) []string {
+	var services []string
+	addService := func(backend *networking.IngressBackend) {
+		if backend != nil && backend.Service != nil {
+			services = append(services, ing.Namespace+"/"+backend.Service.Name)
+		}
+	}
+	addService(ing.Spec.DefaultBackend)
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP != nil {
+			for i := range rule.HTTP.Paths {
+				addService(&rule.HTTP.Paths[i].Backend)
+			}
+		}
+	}
+	return services
+}
+
+func validateHostname(hostname string) string {
+	if hostname == "" || hostname == "*" {
+		return hatypes.DefaultHost
+	}
+	return hostname
+}
+
+func matchAny(items map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if items[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasConflict(args []interface{}) bool {
+	for _, arg := range args {
+		var conflict *convtypes.ConflictError
+		if err, ok := arg.(error); ok && errors.As(err, &conflict) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterObjects[T client.Object](list []T, match func(obj T) bool) []T {
+	out := make([]T, 0, len(list))
+	for _, item := range list {
+		if match(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func replaceObject[T client.Object](list []T, obj T) []T {
+	out := make([]T, 0, len(list)+1)
+	for _, item := range list {
+		if item.GetNamespace() != obj.GetNamespace() || item.GetName() != obj.GetName() {
+			out = append(out, item)
+		}
+	}
+	return append(out, obj)
+}
+
+// validateLogger collects the warnings that refer to the object being validated,
+// either from an ObjectReferrer argument or from the object's name in the message.
+// Invalid, unsupported, conflicting and not allowed configurations, reported via
+// Reject() or via a ConflictError argument, should reject the object. Other
+// warnings, e.g. a missing Service or Secret, refer to objects that might be
+// created later, so they do not reject the object.
+type validateLogger struct {
+	kind, namespace, name string
+	all                   bool
+	errs                  []string
+	warnings              []string
+}
+
+func (l *validateLogger) InfoV(v int, msg string, args ...interface{}) {}
+
+func (l *validateLogger) Info(msg string, args ...interface{}) {}
+
+func (l *validateLogger) Warn(msg string, args ...interface{}) {
+	message := fmt.Sprintf(msg, args...)
+	if !l.all && !l.refers(message, args) {
+		return
+	}
+	if hasConflict(args) {
+		l.errs = append(l.errs, message)
+	} else {
+		l.warnings = append(l.warnings, message)
+	}
+}
+
+func (l *validateLogger) Reject(msg string, args ...interface{}) {
+	message := fmt.Sprintf(msg, args...)
+	if l.all || l.refers(message, args) {
+		l.errs = append(l.errs, message)
+	}
+}
+
+func (l *validateLogger) Error(msg string, args ...interface{}) {}
+
+func (l *validateLogger) Fatal(msg string, args ...interface{}) {}
+
+func (l *validateLogger) refers(message string, args []interface{}) bool {
+	for _, arg := range args {
+		if referrer, ok := arg.(convtypes.ObjectReferrer); ok {
+			if ref := referrer.ObjectReference(); ref != nil &&
+				ref.Kind == l.kind && ref.Namespace == l.namespace && ref.Name == l.name {
+				return true
+			}
+		}
+	}
+	// some warnings refer to the object only by its stringified name
+	return strings.Contains(strings.ToLower(message), strings.ToLower(fmt.Sprintf("%s '%s/%s'", l.kind, l.namespace, l.name)))
+}
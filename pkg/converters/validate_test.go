@@ -0,0 +1,408 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapischeme "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/scheme"
+
+	conv_helper "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/helper_test"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/converters/tracker"
+	convtypes "github.com/jcmoraisjr/haproxy-ingress/pkg/converters/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy"
+	types_helper "github.com/jcmoraisjr/haproxy-ingress/pkg/types/helper_test"
+)
+
+func TestValidate(t *testing.T) {
+	ing1 := `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ing1
+  namespace: default
+spec:
+  rules:
+  - host: d1.local
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: echo
+            port:
+              number: 8080`
+	ing2 := func(path, annKey, annValue string) string {
+		return `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ing2
+  namespace: default
+  annotations:
+    ` + annKey + `: "` + annValue + `"
+spec:
+  rules:
+  - host: d1.local
+    http:
+      paths:
+      - path: ` + path + `
+        pathType: Prefix
+        backend:
+          service:
+            name: echo
+            port:
+              number: 8080`
+	}
+	testCases := []struct {
+		obj         string
+		disable     []string
+		global      map[string]string
+		expected    []string
+		expWarnings []string
+	}{
+		// 0
+		{
+			obj: ing2("/app", "ingress.kubernetes.io/timeout-server", "10s"),
+		},
+		// 1
+		{
+			obj: ing2("/app", "ingress.kubernetes.io/cors-allow-methods", "GET;POST"),
+			expected: []string{
+				"ignoring invalid cors methods on Ingress 'default/ing2': GET;POST",
+			},
+		},
+		// 2
+		{
+			obj: ing2("/", "ingress.kubernetes.io/timeout-server", "10s"),
+			expected: []string{
+				"skipping redeclared path '/' type 'prefix' on Ingress 'default/ing2'",
+			},
+		},
+		// 3
+		{
+			obj:     ing2("/app", "ingress.kubernetes.io/config-backend", "http-request deny"),
+			disable: []string{"*"},
+			expected: []string{
+				"skipping configuration snippet on Ingress 'default/ing2': custom configuration is disabled",
+			},
+		},
+		// 4
+		{
+			obj: ing1,
+		},
+		// 5
+		{
+			obj: ing2("/app", "ingress.kubernetes.io/timeout-server", "10s") + `
+  - host: d2.local
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: missing
+            port:
+              number: 8080`,
+			expWarnings: []string{
+				"skipping backend config of Ingress 'default/ing2': service not found: 'default/missing'",
+			},
+		},
+		// 6
+		{
+			obj: ing2("/app", "ingress.kubernetes.io/cors-allow-methods", "GET;POST") + `
+  - host: d2.local
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: missing
+            port:
+              number: 8080`,
+			expected: []string{
+				"ignoring invalid cors methods on Ingress 'default/ing2': GET;POST",
+			},
+			expWarnings: []string{
+				"skipping backend config of Ingress 'default/ing2': service not found: 'default/missing'",
+			},
+		},
+		// 7
+		{
+			obj: `
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: haproxy
+spec:
+  controller: haproxy-ingress.github.io/controller
+  parameters:
+    kind: Secret
+    name: config`,
+			expected: []string{
+				"unsupported Parameters' Kind on IngressClass 'haproxy': Secret",
+			},
+		},
+		// 8
+		{
+			obj: `
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: haproxy
+spec:
+  controller: haproxy-ingress.github.io/controller
+  parameters:
+    kind: ConfigMap
+    name: config`,
+			expected: []string{
+				"ignoring invalid cors methods on ConfigMap 'ingress-controller/config': GET;POST",
+			},
+		},
+		// 9
+		{
+			obj: `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ing2
+  namespace: other
+spec:
+  rules:
+  - host: d1.local
+    http:
+      paths:
+      - path: /app
+        pathType: Prefix
+        backend:
+          service:
+            name: echo
+            port:
+              number: 8080`,
+			global: map[string]string{"host-ownership": "true"},
+			expected: []string{
+				"skipping hostname 'd1.local' of Ingress 'other/ing2': hostname 'd1.local' is owned by namespace 'default'",
+			},
+		},
+	}
+	for i, test := range testCases {
+		logger := types_helper.NewLoggerMock(t)
+		tracker := tracker.NewTracker()
+		cache := conv_helper.NewCacheMock(tracker)
+		svc, ep, _ := conv_helper.CreateService("default/echo", "8080", "172.17.0.11")
+		cache.SvcList = append(cache.SvcList, svc)
+		cache.EpList["default/echo"] = ep
+		svc, ep, _ = conv_helper.CreateService("other/echo", "8080", "172.17.0.12")
+		cache.SvcList = append(cache.SvcList, svc)
+		cache.EpList["other/echo"] = ep
+		cache.ConfigMapList = map[string]*api.ConfigMap{
+			"ingress-controller/config": {Data: map[string]string{"cors-allow-methods": "GET;POST"}},
+		}
+		ing := conv_helper.CreateObject(ing1).(*networking.Ingress)
+		ing.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		cache.IngList = []*networking.Ingress{ing}
+		options := &convtypes.ConverterOptions{
+			Cache:            cache,
+			Logger:           logger,
+			Tracker:          tracker,
+			DynamicConfig:    &convtypes.DynamicConfig{},
+			AnnotationPrefix: []string{"ingress.kubernetes.io"},
+			DisableKeywords:  test.disable,
+		}
+		hconfig := haproxy.CreateInstance(logger, haproxy.InstanceOptions{}).Config()
+		obj := conv_helper.CreateObject(test.obj).(client.Object)
+		errs, warnings, err := Validate(hconfig, options, test.global, obj)
+		if err != nil {
+			t.Errorf("unexpected error on %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(errs, test.expected) {
+			t.Errorf("errors differ on %d - expected: %v - actual: %v", i, test.expected, errs)
+		}
+		if !reflect.DeepEqual(warnings, test.expWarnings) {
+			t.Errorf("warnings differ on %d - expected: %v - actual: %v", i, test.expWarnings, warnings)
+		}
+		if len(cache.IngList) != 1 || cache.IngList[0] != ing {
+			t.Errorf("cache was changed on %d", i)
+		}
+		logger.CompareLogging("")
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	ing := func(name, host, svc string) string {
+		return `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ` + name + `
+  namespace: default
+spec:
+  rules:
+  - host: ` + host + `
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: ` + svc + `
+            port:
+              number: 8080`
+	}
+	route := func(name, gw, host string) string {
+		return `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: ` + name + `
+  namespace: default
+spec:
+  parentRefs:
+  - name: ` + gw + `
+  hostnames:
+  - ` + host
+	}
+	gateways := []string{`
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gw1
+  namespace: default
+spec:
+  listeners:
+  - name: l1
+    port: 80
+    protocol: HTTP`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gw2
+  namespace: default
+spec:
+  listeners:
+  - name: l1
+    hostname: d1.local
+    port: 80
+    protocol: HTTP`,
+	}
+	ingresses := []string{
+		ing("ing1", "d1.local", "echo1"),
+		ing("ing2", "d2.local", "echo1"),
+		ing("ing3", "d3.local", "echo3"),
+		ing("ing4", "", "echo4"),
+	}
+	routes := []string{
+		route("route1", "gw1", "d1.local"),
+		route("route2", "gw1", "d3.local"),
+		route("route3", "gw2", "d4.local"),
+	}
+	testCases := []struct {
+		obj        string
+		expIngress []string
+		expRoutes  []string
+	}{
+		// 0
+		{
+			obj:        ing("ing5", "d5.local", "echo5"),
+			expIngress: []string{"ing5"},
+		},
+		// 1
+		{
+			obj:        ing("ing5", "d1.local", "echo5"),
+			expIngress: []string{"ing1", "ing5"},
+			expRoutes:  []string{"route1", "route3"},
+		},
+		// 2
+		{
+			obj:        ing("ing5", "d5.local", "echo1"),
+			expIngress: []string{"ing1", "ing2", "ing5"},
+		},
+		// 3
+		{
+			obj:        ing("ing3", "d3.local", "echo5"),
+			expIngress: []string{"ing3"},
+			expRoutes:  []string{"route2"},
+		},
+		// 4
+		{
+			obj:        ing("ing5", "", "echo5"),
+			expIngress: []string{"ing4", "ing5"},
+		},
+		// 5
+		{
+			obj:        route("route4", "gw2", "d5.local"),
+			expIngress: []string{"ing1"},
+			expRoutes:  []string{"route1", "route3", "route4"},
+		},
+		// 6
+		{
+			obj:        route("route2", "gw1", "d2.local"),
+			expIngress: []string{"ing2"},
+			expRoutes:  []string{"route2"},
+		},
+	}
+	for i, test := range testCases {
+		cache := conv_helper.NewCacheMock(tracker.NewTracker())
+		for _, gw := range gateways {
+			cache.GatewayList = append(cache.GatewayList, createGatewayObject(gw).(*gatewayv1.Gateway))
+		}
+		for _, ing := range ingresses {
+			cache.IngList = append(cache.IngList, conv_helper.CreateObject(ing).(*networking.Ingress))
+		}
+		for _, route := range routes {
+			cache.HTTPRouteList = append(cache.HTTPRouteList, createGatewayObject(route).(*gatewayv1.HTTPRoute))
+		}
+		obj := conv_helper.CreateObject(test.obj)
+		if obj == nil {
+			obj = createGatewayObject(test.obj)
+		}
+		c := newValidateCache(cache, obj.(client.Object))
+		ingList, _ := c.GetIngressList()
+		routeList, _ := c.GetHTTPRouteList()
+		var actualIngress, actualRoutes []string
+		for _, ing := range ingList {
+			actualIngress = append(actualIngress, ing.Name)
+		}
+		for _, route := range routeList {
+			actualRoutes = append(actualRoutes, route.Name)
+		}
+		if !reflect.DeepEqual(actualIngress, test.expIngress) {
+			t.Errorf("ingress differ on %d - expected: %v - actual: %v", i, test.expIngress, actualIngress)
+		}
+		if !reflect.DeepEqual(actualRoutes, test.expRoutes) {
+			t.Errorf("routes differ on %d - expected: %v - actual: %v", i, test.expRoutes, actualRoutes)
+		}
+	}
+}
+
+func createGatewayObject(cfg string) runtime.Object {
+	obj, _, err := gwapischeme.Codecs.UniversalDeserializer().Decode([]byte(cfg), nil, nil)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
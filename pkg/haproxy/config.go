@@ -18,6 +18,7 @@ package haproxy
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"strings"
 
@@ -34,6 +35,8 @@ type Config interface {
 	WriteTCPServicesMaps() error
 	WriteFrontendMaps() error
 	WriteBackendMaps() error
+	ExplainRoute(req *RouteRequest) (*RouteExplain, error)
+	DumpModel(section string, filter *ModelFilter) (interface{}, error)
 	AcmeData() *hatypes.AcmeData
 	Global() *hatypes.Global
 	TCPBackends() *hatypes.TCPBackends
@@ -108,6 +111,15 @@ func (c *config) SyncConfig() {
 		c.frontend.BindSocket = c.global.Bind.HTTPSBind
 		c.frontend.AcceptProxy = c.global.Bind.AcceptProxy
 	}
+	if c.backends.Changed() {
+		// the cache section is declared only if at least one path uses it
+		cache := &c.global.Cache
+		cache.Enabled = c.backends.HasCache()
+		cache.PurgeMapFile = ""
+		if cache.Enabled {
+			cache.PurgeMapFile = c.options.mapsDir + "/_global_cache_purge.map"
+		}
+	}
 	for _, host := range c.hosts.ItemsAdd() {
 		if host.SSLPassthrough() {
 			// no action if ssl-passthrough
@@ -212,7 +224,7 @@ func (c *config) WriteFrontendMaps() error {
 				if host.SSLPassthrough() {
 					// no ssl offload, cannot inspect incoming path, so tracking root only
 					if path.Path() == "/" {
-						fmaps.SSLPassthroughMap.AddHostnameMapping(host.Hostname, backendID)
+						fmaps.SSLPassthroughMap.AddHostnameMappingPath(host.Hostname, path, backendID)
 						// the backend of the root path is the ssl-passthrough, which speaks TLS,
 						// so we cannot use it in the HTTP map. Change to the configured HTTP port
 						// in that server (if declared) or use a redirect otherwise.
@@ -363,8 +375,30 @@ func (c *config) WriteBackendMaps() error {
 		// backends are clean, maps are updated
 		return nil
 	}
+	if c.global.Cache.PurgeMapFile != "" {
+		// purge entries are added only via the API, an empty map is
+		// fine since a reload also cleans the cache
+		if err := os.WriteFile(c.global.Cache.PurgeMapFile, nil, 0644); err != nil {
+			return err
+		}
+	}
 	mapBuilder := hatypes.CreateMaps(c.global.MatchOrder)
 	for _, backend := range c.backends.ItemsAdd() {
+		if backend.Response != nil {
+			backend.Response.BodyFile = c.options.mapsDir + "/_back_" + backend.ID + "_response.body"
+			if err := os.WriteFile(backend.Response.BodyFile, []byte(backend.Response.Body), 0644); err != nil {
+				return err
+			}
+		}
+		if err := c.writeJWTKeys(backend); err != nil {
+			return err
+		}
+		for _, apiKeys := range backend.APIKeys {
+			apiKeys.Filename = c.options.mapsDir + "/_back_" + backend.ID + "_apikey_" + apiKeys.Name + ".map"
+			if err := writeAPIKeys(apiKeys, c.options.mapsTemplate); err != nil {
+				return err
+			}
+		}
 		if backend.NeedACL() {
 			mapsPrefix := c.options.mapsDir + "/_back_" + backend.ID
 			pathsMap := mapBuilder.AddMap(mapsPrefix + "_idpath.map")
@@ -393,6 +427,36 @@ func (c *config) WriteBackendMaps() error {
 	return writeMaps(mapBuilder, c.options.mapsTemplate)
 }
 
+// writeJWTKeys writes the public keys used by jwt_verify, one file per
+// distinct key of the backend.
+func (c *config) writeJWTKeys(backend *hatypes.Backend) error {
+	filenames := map[string]string{}
+	for _, path := range backend.Paths {
+		for i := range path.AuthJWT.Keys {
+			key := &path.AuthJWT.Keys[i]
+			filename, found := filenames[key.Key]
+			if !found {
+				filename = fmt.Sprintf("%s/_back_%s_jwt%d.pem", c.options.mapsDir, backend.ID, len(filenames)+1)
+				if err := os.WriteFile(filename, []byte(key.Key), 0644); err != nil {
+					return err
+				}
+				filenames[key.Key] = filename
+			}
+			key.Filename = filename
+		}
+	}
+	return nil
+}
+
+func writeAPIKeys(apiKeys *hatypes.APIKeyMap, template *template.Config) error {
+	keys := sortedKeys(apiKeys.Keys)
+	entries := make([]*hatypes.HostsMapEntry, len(keys))
+	for i, key := range keys {
+		entries[i] = &hatypes.HostsMapEntry{Key: key, Value: apiKeys.Keys[key]}
+	}
+	return template.WriteOutput(entries, apiKeys.Filename)
+}
+
 func writeMaps(maps *hatypes.HostsMaps, template *template.Config) error {
 	for _, hmap := range maps.Items {
 		for _, matchFile := range hmap.MatchFiles() {
@@ -17,7 +17,13 @@ limitations under the License.
 package haproxy
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
+
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
 )
 
 func TestEmptyFrontend(t *testing.T) {
@@ -85,3 +91,205 @@ func TestClear(t *testing.T) {
 		t.Error("expected len(backends) == 0")
 	}
 }
+
+func TestExplainRoute(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	def := c.config.Backends().AcquireBackend("default", "default-backend", "8080")
+	c.config.Backends().DefaultBackend = def
+
+	b1 := c.config.Backends().AcquireBackend("default", "app1", "8080")
+	b2 := c.config.Backends().AcquireBackend("default", "app2", "8080")
+	b3 := c.config.Backends().AcquireBackend("default", "app3", "8443")
+	b3.ModeTCP = true
+
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b1, "/", hatypes.MatchPrefix).Source = "Ingress 'default/app1'"
+	h.AddPath(b2, "/api", hatypes.MatchExact).Source = "Ingress 'default/app2'"
+	canary := h.AddLink(b2, hatypes.CreateHostPathLink("d1.local", "/", hatypes.MatchPrefix).
+		WithHeadersMatch(hatypes.HTTPHeaderMatch{{Name: "x-canary", Value: "true"}}))
+	h.FindPathWithLink(canary).Source = "Ingress 'default/canary'"
+	h.TLS.TLSFilename = "/var/haproxy/ssl/certs/default.pem"
+
+	h = c.config.Hosts().AcquireHost("*.d2.local")
+	h.AddPath(b2, "/", hatypes.MatchBegin).Source = "HTTPRoute 'default/app2'"
+
+	h = c.config.Hosts().AcquireHost("d3.local")
+	h.AddPath(b3, "/", hatypes.MatchBegin).Source = "Ingress 'default/app3'"
+	h.SetSSLPassthrough(true)
+
+	if err := c.config.WriteFrontendMaps(); err != nil {
+		t.Errorf("error writing frontend maps: %v", err)
+	}
+
+	testCases := []struct {
+		host     string
+		path     string
+		headers  map[string]string
+		https    bool
+		expected string
+	}{
+		// 0
+		{
+			host:     "d1.local",
+			path:     "/app",
+			expected: "backend=default_app1_8080 map=_front_http_host__prefix.map entry=d1.local#/ default_app1_8080 sources=Ingress 'default/app1'",
+		},
+		// 1
+		{
+			host:     "D1.local:8080",
+			path:     "/api",
+			expected: "backend=default_app2_8080 map=_front_http_host__exact.map entry=d1.local#/api default_app2_8080 sources=Ingress 'default/app2'",
+		},
+		// 2
+		{
+			host:     "d1.local",
+			path:     "/api",
+			https:    true,
+			expected: "backend=default_app2_8080 map=_front_https_host__exact.map entry=d1.local#/api default_app2_8080 sources=Ingress 'default/app2'",
+		},
+		// 3
+		{
+			host:     "d1.local",
+			path:     "/app",
+			headers:  map[string]string{"X-Canary": "true"},
+			expected: "backend=default_app2_8080 map=_front_http_host__prefix_01.map entry=d1.local#/ default_app2_8080 sources=Ingress 'default/canary'",
+		},
+		// 4
+		{
+			host:     "sub.d2.local",
+			path:     "/app",
+			expected: `backend=default_app2_8080 map=_front_http_host__regex.map entry=^[^.]+\.d2\.local#/ default_app2_8080 sources=HTTPRoute 'default/app2'`,
+		},
+		// 5
+		{
+			host:     "d3.local",
+			path:     "/app",
+			https:    true,
+			expected: "backend=default_app3_8443 passthrough map=_front_sslpassthrough__exact.map entry=d3.local default_app3_8443 sources=Ingress 'default/app3'",
+		},
+		// 6
+		{
+			host:     "d3.local",
+			path:     "/",
+			expected: "backend=_redirect_https map=_front_http_host__begin.map entry=d3.local#/ _redirect_https sources=Ingress 'default/app3'",
+		},
+		// 7
+		{
+			host:     "d4.local",
+			path:     "/",
+			expected: "backend=default_default-backend_8080 map= entry= sources=",
+		},
+	}
+	for i, test := range testCases {
+		headers := http.Header{}
+		for name, value := range test.headers {
+			headers.Set(name, value)
+		}
+		explain, err := c.config.ExplainRoute(&RouteRequest{
+			Host:    test.host,
+			Path:    test.path,
+			Headers: headers,
+			HTTPS:   test.https,
+		})
+		if err != nil {
+			t.Errorf("item %d: unexpected error: %v", i, err)
+			continue
+		}
+		var passthrough string
+		if explain.SSLPassthrough {
+			passthrough = " passthrough"
+		}
+		mapFile := strings.TrimPrefix(explain.MapFile, c.tempdir+"/")
+		actual := "backend=" + explain.Backend + passthrough + " map=" + mapFile + " entry=" + explain.MapEntry + " sources=" + strings.Join(explain.Sources, ",")
+		if actual != test.expected {
+			t.Errorf("item %d: expected '%s' but was '%s'", i, test.expected, actual)
+		}
+	}
+}
+
+func TestDumpModel(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.config.Global().Stats.Auth = "admin:secret"
+	b1 := c.config.Backends().AcquireBackend("ns1", "app1", "8080")
+	b1.APIKeys = []*hatypes.APIKeyMap{{Name: "ns1_keys", Keys: map[string]string{"secretkey": "app"}}}
+	b1.ConfigSources = []*hatypes.ConfigSource{{Key: "timeout-server", Value: "30s", Source: "Ingress 'ns1/app1'"}}
+	b2 := c.config.Backends().AcquireBackend("ns2", "app2", "8080")
+	c.config.Hosts().AcquireHost("d1.local").AddPath(b1, "/", hatypes.MatchBegin)
+	c.config.Hosts().AcquireHost("d2.local").AddPath(b2, "/", hatypes.MatchBegin)
+	c.config.Userlists().Replace("ns1_users", []hatypes.User{{Name: "user1", Passwd: "secret"}})
+
+	testCases := []struct {
+		section  string
+		filter   *ModelFilter
+		expected []string
+		expError string
+	}{
+		// 0
+		{
+			section:  "global",
+			expected: []string{`"Auth": "<redacted>"`},
+		},
+		// 1
+		{
+			section:  "hosts",
+			filter:   &ModelFilter{Namespace: "ns2"},
+			expected: []string{`"Hostname": "d2.local"`, `"ID": "ns2_app2_8080"`},
+		},
+		// 2
+		{
+			section:  "backends",
+			filter:   &ModelFilter{Hostname: "d1.local"},
+			expected: []string{`"ID": "ns1_app1_8080"`, `"Name": "ns1_keys"`, `"Source": "Ingress 'ns1/app1'"`, `"Hostname": "d1.local"`},
+		},
+		// 3
+		{
+			section:  "backends",
+			filter:   &ModelFilter{Backend: "ns3_app3_8080"},
+			expected: []string{`[]`},
+		},
+		// 4
+		{
+			section:  "userlists",
+			expected: []string{`"Name": "user1"`, `"Passwd": "<redacted>"`},
+		},
+		// 5
+		{
+			section:  "frontends",
+			expError: "unsupported model section: 'frontends'",
+		},
+	}
+	for i, test := range testCases {
+		model, err := c.config.DumpModel(test.section, test.filter)
+		if err != nil {
+			if err.Error() != test.expError {
+				t.Errorf("item %d: expected error '%s' but was '%v'", i, test.expError, err)
+			}
+			continue
+		}
+		buf := &bytes.Buffer{}
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(model); err != nil {
+			t.Errorf("item %d: error encoding the model: %v", i, err)
+			continue
+		}
+		out := buf.String()
+		for _, exp := range test.expected {
+			if !strings.Contains(out, exp) {
+				t.Errorf("item %d: expected '%s' in the output: %s", i, exp, out)
+			}
+		}
+		for _, secret := range []string{"secret", "d2.local", "ns2_app2"} {
+			if test.filter == nil || test.filter.Namespace != "ns2" {
+				if strings.Contains(out, secret) {
+					t.Errorf("item %d: unexpected '%s' in the output: %s", i, secret, out)
+				}
+			}
+		}
+	}
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package haproxy
 
 import (
-	"strings"
 	"sync"
 	"time"
 
@@ -41,6 +40,8 @@ type connections struct {
 	master       socket.HAProxySocket
 	dynUpdate    socket.HAProxySocket
 	idleChk      socket.HAProxySocket
+	serversChk   socket.HAProxySocket
+	stats        socket.HAProxySocket
 }
 
 func (c *connections) TrackCurrentInstance(timeoutStopDur, closeSessDur time.Duration) error {
@@ -98,26 +99,13 @@ func (c *connections) shrinkConns() {
 }
 
 func shutdownSessionsSync(sock socket.HAProxySocket, duration time.Duration) {
-	sess, err := sock.Send(nil, "show sess")
+	sessions, err := socket.HAProxySessions(sock)
 	if err != nil {
 		return
 	}
-	// sess output:
-	//
-	// 0x7f9440810000: proto=unix_stream src=...
-	// 0x7f943f87f200: proto=unix_stream src=...
-	// 0x7f9442808200: proto=unix_stream src=...
-	// ...
-	var sessionList []string
-	for _, s := range strings.Split(sess[0], "\n") {
-		i := strings.Index(s, ":")
-		if i > 0 {
-			sessionList = append(sessionList, s[:i])
-		}
-	}
-	interval := duration / time.Duration((len(sessionList) + 1))
-	for _, s := range sessionList {
-		_, err := sock.Send(nil, "shutdown session "+s)
+	interval := duration / time.Duration((len(sessions) + 1))
+	for _, s := range sessions {
+		_, err := sock.Send(nil, "shutdown session "+s.ID)
 		if err != nil {
 			// maybe the connection or the instance is gone,
 			// haproxy takes care of the remaining sessions if any
@@ -159,3 +147,21 @@ func (c *connections) IdleChk() socket.HAProxySocket {
 	}
 	return c.idleChk
 }
+
+func (c *connections) ServersChk() socket.HAProxySocket {
+	if c.serversChk == nil {
+		c.serversChk = socket.NewSocket(c.adminSock, false)
+	}
+	return c.serversChk
+}
+
+// Stats is used to read the state of the running haproxy on behalf of
+// other packages, so it can be called concurrently.
+func (c *connections) Stats() socket.HAProxySocket {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.stats == nil {
+		c.stats = socket.NewSocketConcurrent(c.adminSock, false)
+	}
+	return c.stats
+}
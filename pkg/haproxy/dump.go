@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"fmt"
+	"strings"
+
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+// ModelFilter restricts the items of a model dump. Empty fields match all the items.
+type ModelFilter struct {
+	Namespace string
+	Hostname  string
+	Backend   string
+}
+
+const redacted = "<redacted>"
+
+// DumpModel returns a copy of a section of the model, suitable to be
+// serialized, without secrets like passwords and api keys. Sections
+// are global, hosts, backends, tcpservices and userlists.
+func (c *config) DumpModel(section string, filter *ModelFilter) (interface{}, error) {
+	if filter == nil {
+		filter = &ModelFilter{}
+	}
+	switch section {
+	case "global":
+		return c.dumpGlobal(), nil
+	case "hosts":
+		return c.dumpHosts(filter), nil
+	case "backends":
+		return c.dumpBackends(filter), nil
+	case "tcpservices":
+		return c.dumpTCPServices(filter), nil
+	case "userlists":
+		return c.dumpUserlists(filter), nil
+	}
+	return nil, fmt.Errorf("unsupported model section: '%s'", section)
+}
+
+func (c *config) dumpGlobal() *hatypes.Global {
+	global := *c.global
+	if global.Stats.Auth != "" {
+		global.Stats.Auth = redacted
+	}
+	return &global
+}
+
+func (c *config) dumpHosts(filter *ModelFilter) []*hatypes.Host {
+	hosts := []*hatypes.Host{}
+	for _, host := range c.hosts.BuildSortedItems() {
+		if filter.Hostname != "" && host.Hostname != filter.Hostname {
+			continue
+		}
+		if filter.Namespace != "" || filter.Backend != "" {
+			var found bool
+			for _, path := range host.Paths {
+				if filter.matchBackend(path.Backend.Namespace, path.Backend.ID) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+func (c *config) dumpBackends(filter *ModelFilter) []*hatypes.Backend {
+	backends := []*hatypes.Backend{}
+	for _, backend := range c.backends.BuildSortedItems() {
+		if !filter.matchBackend(backend.Namespace, backend.ID) {
+			continue
+		}
+		if filter.Hostname != "" {
+			var found bool
+			for _, path := range backend.Paths {
+				if path.Link.Hostname() == filter.Hostname {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		if len(backend.APIKeys) > 0 {
+			b := *backend
+			b.APIKeys = make([]*hatypes.APIKeyMap, len(backend.APIKeys))
+			for i, apiKeys := range backend.APIKeys {
+				b.APIKeys[i] = &hatypes.APIKeyMap{
+					Name:     apiKeys.Name,
+					Filename: apiKeys.Filename,
+				}
+			}
+			backend = &b
+		}
+		backends = append(backends, backend)
+	}
+	return backends
+}
+
+// TCPServiceDump ...
+type TCPServiceDump struct {
+	Port         int
+	Hosts        []TCPServiceHostDump
+	CustomConfig []string
+	LogFormat    string
+	ProxyProt    bool
+	TLS          hatypes.TLSConfig
+}
+
+// TCPServiceHostDump ...
+type TCPServiceHostDump struct {
+	Hostname string
+	Backend  string
+}
+
+func (c *config) dumpTCPServices(filter *ModelFilter) []*TCPServiceDump {
+	tcpServices := []*TCPServiceDump{}
+	for _, tcpPort := range c.tcpservices.BuildSortedItems() {
+		var hosts []TCPServiceHostDump
+		tcpHosts := tcpPort.BuildSortedItems()
+		if defaultHost := tcpPort.DefaultHost(); defaultHost != nil {
+			tcpHosts = append(tcpHosts, defaultHost)
+		}
+		for _, tcpHost := range tcpHosts {
+			if filter.Hostname != "" && tcpHost.Hostname() != filter.Hostname {
+				continue
+			}
+			if !filter.matchBackend(tcpHost.Backend.Namespace, tcpHost.Backend.String()) {
+				continue
+			}
+			hosts = append(hosts, TCPServiceHostDump{
+				Hostname: tcpHost.Hostname(),
+				Backend:  tcpHost.Backend.String(),
+			})
+		}
+		if len(hosts) == 0 && (filter.Hostname != "" || filter.Namespace != "" || filter.Backend != "") {
+			continue
+		}
+		tcpServices = append(tcpServices, &TCPServiceDump{
+			Port:         tcpPort.Port(),
+			Hosts:        hosts,
+			CustomConfig: tcpPort.CustomConfig,
+			LogFormat:    tcpPort.LogFormat,
+			ProxyProt:    tcpPort.ProxyProt,
+			TLS:          tcpPort.TLS,
+		})
+	}
+	return tcpServices
+}
+
+func (c *config) dumpUserlists(filter *ModelFilter) []*hatypes.Userlist {
+	userlists := []*hatypes.Userlist{}
+	for _, userlist := range c.userlists.BuildSortedItems() {
+		// userlist names are built from the namespace and the name of the secret
+		if filter.Namespace != "" && !strings.HasPrefix(userlist.Name, filter.Namespace+"_") {
+			continue
+		}
+		users := make([]hatypes.User, len(userlist.Users))
+		for i, user := range userlist.Users {
+			users[i] = user
+			users[i].Passwd = redacted
+		}
+		userlists = append(userlists, &hatypes.Userlist{
+			Name:  userlist.Name,
+			Users: users,
+		})
+	}
+	return userlists
+}
+
+func (f *ModelFilter) matchBackend(namespace, id string) bool {
+	return (f.Namespace == "" || f.Namespace == namespace) &&
+		(f.Backend == "" || f.Backend == id)
+}
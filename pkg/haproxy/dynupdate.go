@@ -31,11 +31,69 @@ import (
 )
 
 type dynUpdater struct {
-	logger  types.Logger
-	config  *config
-	socket  socket.HAProxySocket
-	cmdCnt  int
-	metrics types.Metrics
+	logger   types.Logger
+	config   *config
+	socket   socket.HAProxySocket
+	cmdCnt   int
+	metrics  types.Metrics
+	reasons  reloadReasons
+	fallback bool
+}
+
+// Reasons of a full reload, used as the reason label of the reload metric.
+const (
+	reloadReasonStartup          = "startup"
+	reloadReasonGlobal           = "global-changed"
+	reloadReasonTCPServices      = "tcp-services-changed"
+	reloadReasonFrontend         = "frontend-changed"
+	reloadReasonUserlists        = "userlists-changed"
+	reloadReasonHostAdded        = "host-added"
+	reloadReasonHostRemoved      = "host-removed"
+	reloadReasonHostChanged      = "host-changed"
+	reloadReasonCertAdded        = "cert-added"
+	reloadReasonBackendAdded     = "backend-added"
+	reloadReasonBackendChanged   = "backend-changed"
+	reloadReasonAPIKeysChanged   = "apikeys-changed"
+	reloadReasonEndpointsGrew    = "endpoints-grew"
+	reloadReasonEndpointsChanged = "endpoints-changed"
+	reloadReasonDynUpdateFailed  = "dynupdate-failed"
+	reloadReasonFallbackConfig   = "fallback-config"
+)
+
+// reloadReason describes a change that could not be dynamically applied,
+// and the object of the model that caused it.
+type reloadReason struct {
+	reason    string
+	namespace string
+	object    string
+	fields    []string
+}
+
+type reloadReasons []*reloadReason
+
+// maxLoggedReasons limits the size of the log line on huge changes, e.g. on startup.
+const maxLoggedReasons = 20
+
+func (r reloadReasons) String() string {
+	out := make([]string, 0, len(r))
+	for i, reason := range r {
+		if i == maxLoggedReasons {
+			out = append(out, fmt.Sprintf("and %d more", len(r)-i))
+			break
+		}
+		entry := "reason=" + reason.reason
+		if reason.namespace != "" {
+			entry += " namespace=" + reason.namespace
+		}
+		if reason.object != "" {
+			entry += " object=" + reason.object
+		}
+		if len(reason.fields) > 0 {
+			entry += " fields=" + strings.Join(reason.fields, ",")
+		}
+		out = append(out, entry)
+	}
+	return strings.Join(out, "; ")
 }
 
 type hostPair struct {
@@ -55,14 +113,25 @@ type epPair struct {
 
 func (i *instance) newDynUpdater() *dynUpdater {
 	return &dynUpdater{
-		logger:  i.logger,
-		config:  i.config.(*config),
-		socket:  i.conns.DynUpdate(),
-		metrics: i.metrics,
+		logger:   i.logger,
+		config:   i.config.(*config),
+		socket:   i.conns.DynUpdate(),
+		metrics:  i.metrics,
+		fallback: i.fallback != nil,
 	}
 }
 
 func (d *dynUpdater) update() bool {
+	if d.fallback {
+		// haproxy is running the last known good configuration, and the
+		// current one can only be applied by a reload
+		d.addReason(reloadReasonFallbackConfig, "", "")
+		d.alignSlots()
+		return false
+	}
+	if !d.config.hasCommittedData() {
+		d.addReason(reloadReasonStartup, "", "")
+	}
 	updated := d.config.hasCommittedData() && d.checkConfigChange()
 	if !updated {
 		// Need to reload, time to adjust empty slots according to config
@@ -81,18 +150,23 @@ func (d *dynUpdater) checkConfigChange() bool {
 	var diff []string
 	if d.config.globalOld != nil && !reflect.DeepEqual(d.config.globalOld, d.config.global) {
 		diff = append(diff, "global")
+		d.addReason(reloadReasonGlobal, "", "", diffFields(d.config.globalOld, d.config.global)...)
 	}
 	if d.config.tcpbackends.Changed() {
 		diff = append(diff, "tcp-services (configmap)")
+		d.addReason(reloadReasonTCPServices, "", "configmap")
 	}
 	if d.config.tcpservices.Changed() {
 		diff = append(diff, "tcp-services")
+		d.addReason(reloadReasonTCPServices, "", "")
 	}
 	if d.config.frontend.Changed() {
 		diff = append(diff, "frontend")
+		d.addReason(reloadReasonFrontend, "", "")
 	}
 	if d.config.userlists.Changed() {
 		diff = append(diff, "userlists")
+		d.addReason(reloadReasonUserlists, "", "")
 	}
 	if !d.frontendUpdated() {
 		diff = append(diff, "hosts")
@@ -119,6 +193,7 @@ func (d *dynUpdater) frontendUpdated() bool {
 		h, found := hosts[id]
 		if !found {
 			d.logger.InfoV(2, "added host '%s'", id)
+			d.addReason(reloadReasonHostAdded, hostNamespace(host), id)
 			updated = false
 		} else {
 			h.cur = host
@@ -128,6 +203,7 @@ func (d *dynUpdater) frontendUpdated() bool {
 	for _, pair := range hosts {
 		if pair.cur == nil {
 			d.logger.InfoV(2, "removed host '%s'", pair.old.Hostname)
+			d.addReason(reloadReasonHostRemoved, hostNamespace(pair.old), pair.old.Hostname)
 			updated = false
 		} else if !d.checkHostPair(pair) {
 			updated = false
@@ -151,6 +227,7 @@ func (d *dynUpdater) backendUpdated() bool {
 		back, found := backends[id]
 		if !found {
 			d.logger.InfoV(2, "added backend '%s'", id)
+			d.addReason(reloadReasonBackendAdded, backend.Namespace, id)
 			updated = false
 		} else {
 			back.cur = backend
@@ -183,12 +260,19 @@ func (d *dynUpdater) checkHostPair(pair *hostPair) bool {
 	oldHostCopy.TLS.TLSNotAfter = curHost.TLS.TLSNotAfter
 	if !reflect.DeepEqual(&oldHostCopy, curHost) {
 		d.logger.InfoV(2, "diff outside server certificate of host '%s'", curHost.Hostname)
+		fields := diffFields(&oldHostCopy, curHost)
+		reason := reloadReasonHostChanged
+		if oldHost.TLS.TLSFilename != curHost.TLS.TLSFilename && reflect.DeepEqual(fields, []string{"TLS"}) {
+			reason = reloadReasonCertAdded
+		}
+		d.addReason(reason, hostNamespace(curHost), curHost.Hostname, fields...)
 		updated = false
 	}
 
 	if curHost.TLS.HasTLS() && oldHost.TLS.TLSHash != curHost.TLS.TLSHash &&
 		oldHost.TLS.TLSFilename == curHost.TLS.TLSFilename &&
 		!d.execUpdateCert(curHost.Hostname, curHost.TLS.TLSFilename) {
+		d.addReason(reloadReasonDynUpdateFailed, hostNamespace(curHost), curHost.Hostname, "TLS")
 		updated = false
 	}
 
@@ -211,14 +295,32 @@ func (d *dynUpdater) checkBackendPair(pair *backendPair) bool {
 	oldBackCopy.ID = curBack.ID
 	oldBackCopy.Dynamic = curBack.Dynamic
 	oldBackCopy.Endpoints = curBack.Endpoints
+	oldBackCopy.Fallback = curBack.Fallback
+	oldBackCopy.APIKeys = curBack.APIKeys
+	// debugging info, not used in the haproxy config
+	oldBackCopy.ConfigSources = curBack.ConfigSources
 	if !reflect.DeepEqual(&oldBackCopy, curBack) {
 		d.logger.InfoV(2, "diff outside endpoints of backend '%s'", curBack.ID)
+		d.addReason(reloadReasonBackendChanged, curBack.Namespace, curBack.ID, diffFields(&oldBackCopy, curBack)...)
+		updated = false
+	}
+
+	// API keys are added, updated or removed from the map files via socket,
+	// as long as the backend references the same maps
+	if !d.checkAPIKeys(oldBack, curBack) {
+		updated = false
+	}
+
+	// fallback endpoints are updated in place, removed ones are kept
+	// as disabled slots, as long as the backend doesn't need more servers
+	if !d.checkFallback(oldBack, curBack) {
 		updated = false
 	}
 
 	// can decrease endpoints, cannot increase
 	if len(oldBack.Endpoints) < len(curBack.Endpoints) {
 		d.logger.InfoV(2, "added endpoints on backend '%s'", curBack.ID)
+		d.addReason(reloadReasonEndpointsGrew, curBack.Namespace, curBack.ID)
 		// cannot continue -- missing empty slots in the backend
 		return false
 	}
@@ -240,6 +342,7 @@ func (d *dynUpdater) checkBackendPair(pair *backendPair) bool {
 	if !curBack.Dynamic.DynUpdate {
 		if updated && !reflect.DeepEqual(oldBack.Endpoints, curBack.Endpoints) {
 			d.logger.InfoV(2, "backend '%s' changed and its dynamic-scaling is 'false'", curBack.ID)
+			d.addReason(reloadReasonEndpointsChanged, curBack.Namespace, curBack.ID)
 			return false
 		}
 		return updated
@@ -282,7 +385,11 @@ func (d *dynUpdater) checkBackendPair(pair *backendPair) bool {
 			added = added[1:]
 		}
 		if pair.cur == nil {
-			if !d.execDisableEndpoint(curBack.ID, pair.old) || pair.old.Label != "" {
+			if !d.execDisableEndpoint(curBack.ID, pair.old) {
+				d.addReason(reloadReasonDynUpdateFailed, curBack.Namespace, curBack.ID)
+				updated = false
+			} else if pair.old.Label != "" {
+				d.addReason(reloadReasonEndpointsChanged, curBack.Namespace, curBack.ID)
 				updated = false
 			}
 			empty = append(empty, pair.old)
@@ -296,8 +403,13 @@ func (d *dynUpdater) checkBackendPair(pair *backendPair) bool {
 		if curBack.Cookie.Preserve && added[i].CookieValue != empty[i].CookieValue {
 			// if cookie doesn't match here and preserving the value is
 			// important, don't even enable the endpoint before reloading
+			d.addReason(reloadReasonEndpointsChanged, curBack.Namespace, curBack.ID)
+			updated = false
+		} else if !d.execEnableEndpoint(curBack.ID, nil, added[i]) {
+			d.addReason(reloadReasonDynUpdateFailed, curBack.Namespace, curBack.ID)
 			updated = false
-		} else if !d.execEnableEndpoint(curBack.ID, nil, added[i]) || added[i].Label != "" {
+		} else if added[i].Label != "" {
+			d.addReason(reloadReasonEndpointsChanged, curBack.Namespace, curBack.ID)
 			updated = false
 		}
 	}
@@ -321,15 +433,120 @@ func (d *dynUpdater) checkEndpointPair(backend *hatypes.Backend, pair *epPair) b
 	if backend.Cookie.Preserve && pair.old.CookieValue != pair.cur.CookieValue {
 		// if cookie doesn't match here and preserving the value is
 		// important, don't even enable the endpoint before reloading
+		d.addReason(reloadReasonEndpointsChanged, backend.Namespace, backend.ID)
 		return false
 	}
-	updated := d.execEnableEndpoint(backend.ID, pair.old, pair.cur)
-	if !updated || pair.old.Label != "" || pair.cur.Label != "" {
+	if !d.execEnableEndpoint(backend.ID, pair.old, pair.cur) {
+		d.addReason(reloadReasonDynUpdateFailed, backend.Namespace, backend.ID)
+		return false
+	}
+	if pair.old.Label != "" || pair.cur.Label != "" {
+		d.addReason(reloadReasonEndpointsChanged, backend.Namespace, backend.ID)
 		return false
 	}
 	return true
 }
 
+func (d *dynUpdater) checkFallback(oldBack, curBack *hatypes.Backend) bool {
+	if len(oldBack.Fallback) < len(curBack.Fallback) {
+		d.logger.InfoV(2, "added fallback endpoints on backend '%s'", curBack.ID)
+		d.addReason(reloadReasonEndpointsGrew, curBack.Namespace, curBack.ID, "Fallback")
+		return false
+	}
+	if !curBack.Dynamic.DynUpdate {
+		if !reflect.DeepEqual(oldBack.Fallback, curBack.Fallback) {
+			d.logger.InfoV(2, "fallback of backend '%s' changed and its dynamic-scaling is 'false'", curBack.ID)
+			d.addReason(reloadReasonEndpointsChanged, curBack.Namespace, curBack.ID, "Fallback")
+			return false
+		}
+		return true
+	}
+	updated := true
+	for i, oldEP := range oldBack.Fallback {
+		if i >= len(curBack.Fallback) {
+			empty := curBack.AddFallbackEndpoint("127.0.0.1", 1023, "")
+			empty.Enabled = false
+			if oldEP.Enabled && !d.execDisableEndpoint(curBack.ID, oldEP) {
+				d.addReason(reloadReasonDynUpdateFailed, curBack.Namespace, curBack.ID, "Fallback")
+				updated = false
+			}
+			continue
+		}
+		curEP := curBack.Fallback[i]
+		if !reflect.DeepEqual(oldEP, curEP) && !d.execEnableEndpoint(curBack.ID, oldEP, curEP) {
+			d.addReason(reloadReasonDynUpdateFailed, curBack.Namespace, curBack.ID, "Fallback")
+			updated = false
+		}
+	}
+	return updated
+}
+
+func (d *dynUpdater) checkAPIKeys(oldBack, curBack *hatypes.Backend) bool {
+	if len(oldBack.APIKeys) != len(curBack.APIKeys) {
+		d.logger.InfoV(2, "added or removed API key maps on backend '%s'", curBack.ID)
+		d.addReason(reloadReasonAPIKeysChanged, curBack.Namespace, curBack.ID)
+		return false
+	}
+	updated := true
+	for i, curMap := range curBack.APIKeys {
+		oldMap := oldBack.APIKeys[i]
+		if oldMap.Name != curMap.Name || oldMap.Filename != curMap.Filename {
+			d.logger.InfoV(2, "added or removed API key maps on backend '%s'", curBack.ID)
+			d.addReason(reloadReasonAPIKeysChanged, curBack.Namespace, curBack.ID)
+			return false
+		}
+		if !d.execUpdateAPIKeys(curBack.ID, oldMap, curMap) {
+			d.addReason(reloadReasonDynUpdateFailed, curBack.Namespace, curBack.ID, "APIKeys")
+			updated = false
+		}
+	}
+	return updated
+}
+
+func (d *dynUpdater) addReason(reason, namespace, object string, fields ...string) {
+	d.reasons = append(d.reasons, &reloadReason{
+		reason:    reason,
+		namespace: namespace,
+		object:    object,
+		fields:    fields,
+	})
+}
+
+// diffFields lists the name of the fields whose values differ
+// between two structs, or pointers to structs, of the same type.
+func diffFields(old, cur interface{}) []string {
+	oldValue := reflect.Indirect(reflect.ValueOf(old))
+	curValue := reflect.Indirect(reflect.ValueOf(cur))
+	if oldValue.Kind() != reflect.Struct || oldValue.Type() != curValue.Type() {
+		return nil
+	}
+	var fields []string
+	for i := 0; i < curValue.NumField(); i++ {
+		field := curValue.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), curValue.Field(i).Interface()) {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+// hostNamespace returns the namespace of the backends referenced
+// by a host, or an empty string if they differ.
+func hostNamespace(host *hatypes.Host) string {
+	var namespace string
+	for i, path := range host.Paths {
+		if i == 0 {
+			namespace = path.Backend.Namespace
+		} else if path.Backend.Namespace != namespace {
+			return ""
+		}
+	}
+	return namespace
+}
+
 func (d *dynUpdater) alignSlots() {
 	backends := d.config.Backends()
 	for _, back := range backends.Items() {
@@ -414,6 +631,53 @@ func (d *dynUpdater) execUpdateCert(hostname, filename string) bool {
 	return true
 }
 
+func (d *dynUpdater) execUpdateAPIKeys(backname string, oldMap, curMap *hatypes.APIKeyMap) bool {
+	// Keys are sorted only to have predictable results (tests).
+	// Keys are never logged, only their labels.
+	var cmd, labels []string
+	for _, key := range sortedKeys(oldMap.Keys) {
+		if _, found := curMap.Keys[key]; !found {
+			cmd = append(cmd, fmt.Sprintf("del map %s %s", curMap.Filename, key))
+			labels = append(labels, "-"+oldMap.Keys[key])
+		}
+	}
+	for _, key := range sortedKeys(curMap.Keys) {
+		label := curMap.Keys[key]
+		if oldLabel, found := oldMap.Keys[key]; !found {
+			cmd = append(cmd, fmt.Sprintf("add map %s %s %s", curMap.Filename, key, label))
+			labels = append(labels, "+"+label)
+		} else if oldLabel != label {
+			cmd = append(cmd, fmt.Sprintf("set map %s %s %s", curMap.Filename, key, label))
+			labels = append(labels, "~"+label)
+		}
+	}
+	if len(cmd) == 0 {
+		return true
+	}
+	msg, err := d.execCommand(d.metrics.HAProxySetMapResponseTime, cmd)
+	if err != nil {
+		d.logger.Error("error updating API keys of map '%s' on backend '%s': %v", curMap.Name, backname, err)
+		return false
+	}
+	for _, m := range msg {
+		if m != "" {
+			d.logger.Warn("unrecognized response updating API keys of map '%s' on backend '%s': %s", curMap.Name, backname, strings.TrimSpace(m))
+			return false
+		}
+	}
+	d.logger.InfoV(2, "updated API keys of map '%s' on backend '%s': %v", curMap.Name, backname, labels)
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (d *dynUpdater) execDisableEndpoint(backname string, ep *hatypes.Endpoint) bool {
 	server := fmt.Sprintf("set server %s/%s ", backname, ep.Name)
 	cmd := []string{
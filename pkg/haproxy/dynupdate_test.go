@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/kylelemons/godebug/diff"
+
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
 )
 
 func TestDynUpdate(t *testing.T) {
@@ -999,6 +1001,202 @@ INFO-V(2) removed host 'domain2.local'
 INFO-V(2) need to reload due to config changes: [hosts]
 `,
 		},
+		// 33
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.APIKeys = []*hatypes.APIKeyMap{{
+					Name:     "default_keys",
+					Keys:     map[string]string{"key1": "app1", "key2": "app2", "key3": "app3"},
+					Filename: "/tmp/apikey.map",
+				}}
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.APIKeys = []*hatypes.APIKeyMap{{
+					Name:     "default_keys",
+					Keys:     map[string]string{"key1": "app1", "key3": "app4", "key4": "app5"},
+					Filename: "/tmp/apikey.map",
+				}}
+			},
+			dynamic: true,
+			cmd: `
+del map /tmp/apikey.map key2
+set map /tmp/apikey.map key3 app4
+add map /tmp/apikey.map key4 app5
+`,
+			logging: `INFO-V(2) updated API keys of map 'default_keys' on backend 'default_app_8080': [-app2 ~app4 +app5]`,
+		},
+		// 34
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.APIKeys = []*hatypes.APIKeyMap{{
+					Name:     "default_keys",
+					Keys:     map[string]string{"key1": "app1"},
+					Filename: "/tmp/apikey.map",
+				}}
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.APIKeys = []*hatypes.APIKeyMap{{
+					Name:     "default_keys",
+					Keys:     map[string]string{"key2": "app1"},
+					Filename: "/tmp/apikey.map",
+				}}
+			},
+			dynamic: false,
+			cmd: `
+del map /tmp/apikey.map key1
+add map /tmp/apikey.map key2 app1
+`,
+			cmdOutput: []string{"", "Unknown map identifier.\n"},
+			logging: `
+WARN unrecognized response updating API keys of map 'default_keys' on backend 'default_app_8080': Unknown map identifier.
+INFO-V(2) need to reload due to config changes: [backends]
+`,
+		},
+		// 35
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.APIKeys = []*hatypes.APIKeyMap{{
+					Name:     "default_keys",
+					Keys:     map[string]string{"key1": "app1"},
+					Filename: "/tmp/apikey.map",
+				}}
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.APIKeys = []*hatypes.APIKeyMap{{
+					Name:     "default_keys2",
+					Keys:     map[string]string{"key1": "app1"},
+					Filename: "/tmp/apikey2.map",
+				}}
+			},
+			dynamic: false,
+			logging: `
+INFO-V(2) added or removed API key maps on backend 'default_app_8080'
+INFO-V(2) need to reload due to config changes: [backends]
+`,
+		},
+		// 36
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.11", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.12", 8080, "")
+			},
+			expected: []string{
+				"srv001:172.17.0.2:8080:1",
+			},
+			dynamic: true,
+			cmd: `
+set server default_app_8080/fallback001 addr 172.17.0.12 port 8080
+set server default_app_8080/fallback001 state ready
+set server default_app_8080/fallback001 weight 1
+`,
+			logging: `
+INFO-V(2) updated endpoint '172.17.0.12:8080' weight '1' state 'ready' on backend/server 'default_app_8080/fallback001'
+`,
+		},
+		// 37
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.11", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.12", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.11", 8080, "")
+			},
+			expected: []string{
+				"srv001:172.17.0.2:8080:1",
+			},
+			dynamic: true,
+			cmd: `
+set server default_app_8080/fallback002 state maint
+set server default_app_8080/fallback002 addr 127.0.0.1 port 1023
+set server default_app_8080/fallback002 weight 0
+`,
+			logging: `
+INFO-V(2) disabled endpoint '172.17.0.12:8080' on backend/server 'default_app_8080/fallback002'
+`,
+		},
+		// 38
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.11", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.11", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.12", 8080, "")
+			},
+			expected: []string{
+				"srv001:172.17.0.2:8080:1",
+			},
+			dynamic: false,
+			logging: `
+INFO-V(2) added fallback endpoints on backend 'default_app_8080'
+INFO-V(2) need to reload due to config changes: [backends]
+`,
+		},
+		// 39
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.11", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AddFallbackEndpoint("172.17.0.12", 8080, "")
+			},
+			expected: []string{
+				"srv001:172.17.0.2:8080:1",
+			},
+			dynamic: false,
+			logging: `
+INFO-V(2) fallback of backend 'default_app_8080' changed and its dynamic-scaling is 'false'
+INFO-V(2) need to reload due to config changes: [backends]
+`,
+		},
+		// 40
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.ConfigSources = []*hatypes.ConfigSource{{Key: "balance-algorithm", Value: "roundrobin", Source: "ingress 'default/app'"}}
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.ConfigSources = []*hatypes.ConfigSource{{Key: "balance-algorithm", Value: "roundrobin", Source: "ingress 'default/app2'"}}
+			},
+			expected: []string{
+				"srv001:172.17.0.2:8080:1",
+			},
+			dynamic: true,
+		},
 	}
 	readFile = func(_ string) ([]byte, error) {
 		return []byte("<content>"), nil
@@ -1076,3 +1274,132 @@ func (cli *clientMock) Unlistening() error {
 func (cli *clientMock) Close() error {
 	return nil
 }
+
+func TestReloadReasons(t *testing.T) {
+	testCases := []struct {
+		doconfig1 func(c *testConfig)
+		doconfig2 func(c *testConfig)
+		fallback  bool
+		expected  string
+		logging   string
+	}{
+		// 0
+		{
+			doconfig1: func(c *testConfig) {
+				c.config.Global().MaxConn = 1
+			},
+			doconfig2: func(c *testConfig) {
+				c.config.Global().MaxConn = 2
+			},
+			expected: "reason=global-changed fields=MaxConn",
+			logging:  `INFO-V(2) need to reload due to config changes: [global]`,
+		},
+		// 1
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.AcquireEndpoint("172.17.0.3", 8080, "")
+			},
+			expected: "reason=endpoints-grew namespace=default object=default_app_8080",
+			logging: `
+INFO-V(2) added endpoints on backend 'default_app_8080'
+INFO-V(2) need to reload due to config changes: [backends]`,
+		},
+		// 2
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+				b.ModeTCP = true
+				b.Resolver = "k8s"
+			},
+			expected: "reason=backend-changed namespace=default object=default_app_8080 fields=ModeTCP,Resolver",
+			logging: `
+INFO-V(2) diff outside endpoints of backend 'default_app_8080'
+INFO-V(2) need to reload due to config changes: [backends]`,
+		},
+		// 3
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				c.config.Hosts().AcquireHost("domain1.local").AddPath(b, "/", hatypes.MatchBegin)
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				h1 := c.config.Hosts().AcquireHost("domain1.local")
+				h1.AddPath(b, "/", hatypes.MatchBegin)
+				h1.TLS.TLSFilename = "/tmp/domain1.pem"
+				h1.TLS.TLSHash = "1"
+				c.config.Hosts().AcquireHost("domain2.local").AddPath(b, "/", hatypes.MatchBegin)
+			},
+			expected: "reason=host-added namespace=default object=domain2.local; reason=cert-added namespace=default object=domain1.local fields=TLS; reason=backend-changed namespace=default object=default_app_8080 fields=Paths",
+			logging: `
+INFO-V(2) added host 'domain2.local'
+INFO-V(2) diff outside server certificate of host 'domain1.local'
+INFO-V(2) diff outside endpoints of backend 'default_app_8080'
+INFO-V(2) need to reload due to config changes: [hosts backends]`,
+		},
+		// 4
+		{
+			doconfig1: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+			},
+			doconfig2: func(c *testConfig) {
+				b := c.config.Backends().AcquireBackend("default", "app", "8080")
+				b.Dynamic.DynUpdate = true
+				b.AcquireEndpoint("172.17.0.2", 8080, "")
+			},
+			fallback: true,
+			expected: "reason=fallback-config",
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		if test.doconfig1 != nil {
+			test.doconfig1(c)
+		}
+		c.instance.config.Commit()
+		hostnames := []string{}
+		for hostname := range c.config.hosts.Items() {
+			hostnames = append(hostnames, hostname)
+		}
+		c.config.Hosts().RemoveAll(hostnames)
+		backendIDs := []string{}
+		for _, backend := range c.config.Backends().Items() {
+			backendIDs = append(backendIDs, backend.ID)
+		}
+		c.config.Backends().RemoveAll(backendIDs)
+		if test.doconfig2 != nil {
+			test.doconfig2(c)
+		}
+		if test.fallback {
+			c.instance.fallback = &FallbackState{Since: time.Now()}
+		}
+		dynUpdater := c.instance.newDynUpdater()
+		dynUpdater.socket = &clientMock{}
+		if dynUpdater.update() {
+			t.Errorf("item %d: expected a full reload", i)
+		}
+		actual := dynUpdater.reasons.String()
+		if actual != test.expected {
+			t.Errorf("reasons differ on %d:\nexpected: %s\n  actual: %s", i, test.expected, actual)
+		}
+		c.logger.CompareLogging(test.logging)
+		c.teardown()
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+// RouteRequest describes the request whose route should be explained.
+type RouteRequest struct {
+	Host    string
+	Path    string
+	Headers http.Header
+	HTTPS   bool
+}
+
+// RouteExplain describes how the frontend routes a request: the selected
+// backend, or redirect, the map file and entry that matched the request,
+// and the resources that declared it.
+type RouteExplain struct {
+	Backend        string              `json:"backend,omitempty"`
+	RedirectTo     string              `json:"redirectTo,omitempty"`
+	SSLPassthrough bool                `json:"sslPassthrough,omitempty"`
+	MapFile        string              `json:"mapFile,omitempty"`
+	MapMethod      string              `json:"mapMethod,omitempty"`
+	MapEntry       string              `json:"mapEntry,omitempty"`
+	Headers        []hatypes.HTTPMatch `json:"headers,omitempty"`
+	Sources        []string            `json:"sources,omitempty"`
+}
+
+// ExplainRoute evaluates the frontend maps of the current model in the same
+// order the frontends of the configuration file do, and reports which one
+// would be used to route the request.
+func (c *config) ExplainRoute(req *RouteRequest) (*RouteExplain, error) {
+	fmaps := c.frontend.Maps
+	if fmaps == nil {
+		return nil, fmt.Errorf("frontend maps weren't built yet")
+	}
+	hostname := strings.ToLower(req.Host)
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = h
+	}
+	if hostname == "" {
+		return nil, fmt.Errorf("missing hostname")
+	}
+	path := req.Path
+	if path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path should start with a slash: '%s'", path)
+	}
+	base := hostname + "#" + path
+
+	if req.HTTPS {
+		if matchFile, entry := fmaps.SSLPassthroughMap.Match(hostname, nil); entry != nil {
+			explain := newRouteExplain(matchFile, entry)
+			explain.Backend = entry.Value
+			explain.SSLPassthrough = true
+			return explain, nil
+		}
+		if defaultHost := c.hosts.DefaultHost(); defaultHost != nil && defaultHost.SSLPassthrough() {
+			if paths := defaultHost.FindPath("/"); len(paths) > 0 {
+				return &RouteExplain{
+					Backend:        paths[0].Backend.ID,
+					SSLPassthrough: true,
+					Sources:        hostPathSources(paths[0]),
+				}, nil
+			}
+		}
+	}
+
+	if !c.hasNoRedirect(path) {
+		if matchFile, entry := fmaps.RedirToMap.Match(base, req.Headers); entry != nil {
+			explain := newRouteExplain(matchFile, entry)
+			explain.RedirectTo = entry.Value
+			return explain, nil
+		}
+	}
+
+	var hostMaps []*hatypes.HostsMap
+	if req.HTTPS {
+		hostMaps = []*hatypes.HostsMap{fmaps.HTTPSHostMap, fmaps.HTTPSSNIMap}
+	} else {
+		hostMaps = []*hatypes.HostsMap{fmaps.HTTPHostMap}
+	}
+	for _, hostMap := range hostMaps {
+		if matchFile, entry := hostMap.Match(base, req.Headers); entry != nil {
+			explain := newRouteExplain(matchFile, entry)
+			explain.Backend = entry.Value
+			return explain, nil
+		}
+	}
+
+	defaultHost := c.hosts.DefaultHost()
+	if !req.HTTPS && defaultHost != nil && defaultHost.HTTPPassthroughBackend != "" {
+		return &RouteExplain{Backend: defaultHost.HTTPPassthroughBackend}, nil
+	}
+	if matchFile, entry := fmaps.DefaultHostMap.Match(hatypes.DefaultHost+"#"+path, req.Headers); entry != nil {
+		explain := newRouteExplain(matchFile, entry)
+		explain.Backend = entry.Value
+		return explain, nil
+	}
+	if defaultBackend := c.backends.DefaultBackend; defaultBackend != nil {
+		return &RouteExplain{Backend: defaultBackend.ID}, nil
+	}
+	return &RouteExplain{Backend: "_error404"}, nil
+}
+
+func (c *config) hasNoRedirect(path string) bool {
+	for _, prefix := range c.global.NoRedirects {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func newRouteExplain(matchFile *hatypes.MatchFile, entry *hatypes.HostsMapEntry) *RouteExplain {
+	return &RouteExplain{
+		MapFile:   matchFile.Filename(),
+		MapMethod: matchFile.Method(),
+		MapEntry:  strings.TrimSpace(entry.Key + " " + entry.Value),
+		Headers:   matchFile.Headers(),
+		Sources:   hostPathSources(entry.HostPath()),
+	}
+}
+
+func hostPathSources(hostPath *hatypes.HostPath) []string {
+	if hostPath == nil || hostPath.Source == "" {
+		return nil
+	}
+	return []string{hostPath.Source}
+}
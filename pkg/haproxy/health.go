@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/socket"
+)
+
+// BackendHealth summarizes the state of the servers of a backend, and the
+// traffic it received since the former summary.
+type BackendHealth struct {
+	ID         string
+	Up         int
+	Down       int
+	Maint      int
+	DownReason string
+	Requests   int64
+	Rsp5xx     int64
+	Sources    []*BackendSource
+}
+
+// String describes the health of the backend, e.g. `2/3 endpoints DOWN (L7 timeout)`.
+// The rate of 5xx responses is only added if the backend responded with errors.
+func (h *BackendHealth) String() string {
+	out := h.endpoints()
+	if h.Down > 0 && h.DownReason != "" {
+		out += " (" + h.DownReason + ")"
+	}
+	if h.Maint > 0 {
+		out += fmt.Sprintf(", %d MAINT", h.Maint)
+	}
+	if h.Rsp5xx > 0 && h.Requests > 0 {
+		out += fmt.Sprintf(", 5xx %.0f%%", h.Rsp5xxRatio()*100)
+	}
+	return out
+}
+
+// State describes only the state of the servers of the backend, e.g.
+// `2/3 endpoints DOWN, 1 MAINT`, so it only changes when a server changes
+// its state.
+func (h *BackendHealth) State() string {
+	out := h.endpoints()
+	if h.Maint > 0 {
+		out += fmt.Sprintf(", %d MAINT", h.Maint)
+	}
+	return out
+}
+
+// Rsp5xxRatio is the ratio, from 0 to 1, of the 5xx responses of the backend
+// since the former summary.
+func (h *BackendHealth) Rsp5xxRatio() float64 {
+	if h.Requests == 0 {
+		return 0
+	}
+	return float64(h.Rsp5xx) / float64(h.Requests)
+}
+
+func (h *BackendHealth) endpoints() string {
+	total := h.Up + h.Down
+	if h.Down > 0 {
+		return fmt.Sprintf("%d/%d endpoints DOWN", h.Down, total)
+	}
+	return fmt.Sprintf("%d/%d endpoints UP", h.Up, total)
+}
+
+// backendCounters are the cumulative counters of a backend, used to
+// calculate the traffic between two summaries.
+type backendCounters struct {
+	requests int64
+	rsp5xx   int64
+}
+
+var checkStatusDesc = map[string]string{
+	"L4TOUT":   "L4 timeout",
+	"L4CON":    "L4 connection problem",
+	"L6TOUT":   "L6 timeout",
+	"L6RSP":    "L6 invalid response",
+	"L7TOUT":   "L7 timeout",
+	"L7RSP":    "L7 invalid response",
+	"L7STS":    "L7 response error",
+	"SOCKERR":  "socket error",
+	"PROCERR":  "external check error",
+	"PROCTOUT": "external check timeout",
+}
+
+// BackendsHealth summarizes the health of the backends of the model from the
+// live stats of haproxy, read by Stats() so the socket can be queried without
+// locking the model. Empty slots of the backends are not counted. Traffic is
+// calculated since the last call, so this should be called by a single
+// periodic job.
+func (i *instance) BackendsHealth(stats []*socket.Stat) []*BackendHealth {
+	backends := i.config.Backends().Items()
+	healthMap := map[string]*BackendHealth{}
+	reasons := map[string]map[string]int{}
+	counters := map[string]backendCounters{}
+	for _, stat := range stats {
+		backend := backends[stat.Proxy]
+		if backend == nil {
+			continue
+		}
+		health := healthMap[stat.Proxy]
+		if health == nil {
+			health = &BackendHealth{ID: stat.Proxy}
+			healthMap[stat.Proxy] = health
+		}
+		switch stat.Type {
+		case socket.StatBackend:
+			cur := backendCounters{requests: stat.ReqTotal, rsp5xx: stat.Rsp5xx}
+			last, found := i.backendCounters[stat.Proxy]
+			if !found || cur.requests < last.requests || cur.rsp5xx < last.rsp5xx {
+				// first summary or counters were reset by a reload
+				last = backendCounters{}
+			}
+			health.Requests = cur.requests - last.requests
+			health.Rsp5xx = cur.rsp5xx - last.rsp5xx
+			counters[stat.Proxy] = cur
+		case socket.StatServer:
+			ep := backend.FindEndpointByName(stat.Name)
+			if ep == nil || ep.IsEmpty() {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(stat.Status, "DOWN"):
+				health.Down++
+				if desc := checkStatusDesc[strings.TrimPrefix(stat.CheckStatus, "* ")]; desc != "" {
+					if reasons[stat.Proxy] == nil {
+						reasons[stat.Proxy] = map[string]int{}
+					}
+					reasons[stat.Proxy][desc]++
+				}
+			case strings.HasPrefix(stat.Status, "MAINT"), strings.HasPrefix(stat.Status, "DRAIN"):
+				health.Maint++
+			default:
+				// UP, UP n/m (going down), NOLB and no check
+				health.Up++
+			}
+		}
+	}
+	i.backendCounters = counters
+	healthList := make([]*BackendHealth, 0, len(healthMap))
+	for id, health := range healthMap {
+		health.DownReason = mostFrequent(reasons[id])
+		health.Sources = i.backendSources(id)
+		healthList = append(healthList, health)
+	}
+	sort.Slice(healthList, func(i, j int) bool {
+		return healthList[i].ID < healthList[j].ID
+	})
+	return healthList
+}
+
+// TargetsUp reports which target refs, the `namespace/name` of the pods, are
+// UP servers, from the live state of the servers read by ServersState(). A pod
+// referenced by more than one backend is UP only if all of its servers are UP.
+func (i *instance) TargetsUp(states []*socket.ServerState) map[string]bool {
+	stateMap := make(map[string]*socket.ServerState, len(states))
+	for _, state := range states {
+		stateMap[state.Backend+"/"+state.Name] = state
+	}
+	targets := map[string]bool{}
+	for _, backend := range i.config.Backends().Items() {
+		for _, ep := range backend.Endpoints {
+			if ep.IsEmpty() || ep.TargetRef == "" {
+				continue
+			}
+			state := stateMap[backend.ID+"/"+ep.Name]
+			up := state != nil && state.OpState == socket.SrvOpRunning && !state.IsMaint()
+			if cur, found := targets[ep.TargetRef]; found {
+				up = up && cur
+			}
+			targets[ep.TargetRef] = up
+		}
+	}
+	return targets
+}
+
+// EjectedServer is a server that went down on a backend configured with
+// passive health checks, so it might have been ejected due to the errors
+// observed on live traffic.
+type EjectedServer struct {
+	Backend  string
+	Server   string
+	Endpoint string
+	Service  *BackendSource
+}
+
+// EjectedServers filters the servers that went down, as returned by
+// CheckServersState(), whose backend has passive health checks. Empty slots
+// and servers not found in the model are ignored.
+func (i *instance) EjectedServers(serversDown []string) []*EjectedServer {
+	if i.config == nil {
+		return nil
+	}
+	backends := i.config.Backends().Items()
+	var ejected []*EjectedServer
+	for _, server := range serversDown {
+		id, name, _ := strings.Cut(server, "/")
+		backend := backends[id]
+		if backend == nil || backend.HealthCheck.Observe == "" {
+			continue
+		}
+		ep := backend.FindEndpointByName(name)
+		if ep == nil || ep.IsEmpty() {
+			continue
+		}
+		ejected = append(ejected, &EjectedServer{
+			Backend:  id,
+			Server:   name,
+			Endpoint: fmt.Sprintf("%s:%d", ep.IP, ep.Port),
+			Service: &BackendSource{
+				Kind:      "Service",
+				Namespace: backend.Namespace,
+				Name:      backend.Name,
+				source:    fmt.Sprintf("Service '%s/%s'", backend.Namespace, backend.Name),
+			},
+		})
+	}
+	return ejected
+}
+
+func mostFrequent(count map[string]int) string {
+	var item string
+	var max int
+	for k, v := range count {
+		if v > max || (v == max && k < item) {
+			item = k
+			max = v
+		}
+	}
+	return item
+}
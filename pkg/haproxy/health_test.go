@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/socket"
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+)
+
+func TestBackendsHealth(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b1 := c.config.Backends().AcquireBackend("default", "app", "8080")
+	b1.AcquireEndpoint("172.17.0.11", 8080, "")
+	b1.AcquireEndpoint("172.17.0.12", 8080, "")
+	b1.AcquireEndpoint("172.17.0.13", 8080, "")
+	b1.AddEmptyEndpoint()
+	c.config.Hosts().AcquireHost("app.local").AddPath(b1, "/", hatypes.MatchBegin).Source = "Ingress 'default/app'"
+	b2 := c.config.Backends().AcquireBackend("default", "web", "80")
+	b2.AcquireEndpoint("172.17.0.21", 80, "")
+	b2.AcquireEndpoint("172.17.0.22", 80, "")
+	c.config.Hosts().AcquireHost("web.local").AddPath(b2, "/", hatypes.MatchBegin).Source = "HTTPRoute 'default/web'"
+
+	stats := func(req1, rsp1, req2, rsp2 int64) []*socket.Stat {
+		return []*socket.Stat{
+			{Proxy: "_front_http", Name: "FRONTEND", Type: socket.StatFrontend, Status: "OPEN", ReqTotal: req1 + req2},
+			{Proxy: b1.ID, Name: "srv001", Type: socket.StatServer, Status: "UP", CheckStatus: "L7OK"},
+			{Proxy: b1.ID, Name: "srv002", Type: socket.StatServer, Status: "DOWN", CheckStatus: "L7TOUT"},
+			{Proxy: b1.ID, Name: "srv003", Type: socket.StatServer, Status: "DOWN", CheckStatus: "* L7TOUT"},
+			{Proxy: b1.ID, Name: "srv004", Type: socket.StatServer, Status: "MAINT"},
+			{Proxy: b1.ID, Name: "BACKEND", Type: socket.StatBackend, Status: "UP", ReqTotal: req1, Rsp5xx: rsp1},
+			{Proxy: b2.ID, Name: "srv001", Type: socket.StatServer, Status: "UP", CheckStatus: "L4OK"},
+			{Proxy: b2.ID, Name: "srv002", Type: socket.StatServer, Status: "MAINT"},
+			{Proxy: b2.ID, Name: "BACKEND", Type: socket.StatBackend, Status: "UP", ReqTotal: req2, Rsp5xx: rsp2},
+		}
+	}
+	summary := func(healthList []*BackendHealth) map[string]string {
+		out := map[string]string{}
+		for _, health := range healthList {
+			for _, src := range health.Sources {
+				out[health.ID+" "+src.String()] = health.String() + " | " + health.State()
+			}
+		}
+		return out
+	}
+
+	testCases := []struct {
+		stats    []*socket.Stat
+		expected map[string]string
+	}{
+		// 0
+		{
+			stats: stats(100, 10, 50, 0),
+			expected: map[string]string{
+				"default_app_8080 Ingress 'default/app'": "2/3 endpoints DOWN (L7 timeout), 5xx 10% | 2/3 endpoints DOWN",
+				"default_web_80 HTTPRoute 'default/web'": "1/1 endpoints UP, 1 MAINT | 1/1 endpoints UP, 1 MAINT",
+			},
+		},
+		// 1
+		{
+			stats: stats(300, 60, 50, 0),
+			expected: map[string]string{
+				"default_app_8080 Ingress 'default/app'": "2/3 endpoints DOWN (L7 timeout), 5xx 25% | 2/3 endpoints DOWN",
+				"default_web_80 HTTPRoute 'default/web'": "1/1 endpoints UP, 1 MAINT | 1/1 endpoints UP, 1 MAINT",
+			},
+		},
+		// 2
+		{
+			stats: stats(20, 1, 10, 5),
+			expected: map[string]string{
+				"default_app_8080 Ingress 'default/app'": "2/3 endpoints DOWN (L7 timeout), 5xx 5% | 2/3 endpoints DOWN",
+				"default_web_80 HTTPRoute 'default/web'": "1/1 endpoints UP, 1 MAINT, 5xx 50% | 1/1 endpoints UP, 1 MAINT",
+			},
+		},
+	}
+	for i, test := range testCases {
+		actual := summary(c.instance.BackendsHealth(test.stats))
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("backends health differ on %d\nexpected: %v\n  actual: %v", i, test.expected, actual)
+		}
+	}
+	c.logger.CompareLogging("")
+}
+
+func TestTargetsUp(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b1 := c.config.Backends().AcquireBackend("default", "app", "8080")
+	b1.AcquireEndpoint("172.17.0.11", 8080, "default/app-1")
+	b1.AcquireEndpoint("172.17.0.12", 8080, "default/app-2")
+	b1.AcquireEndpoint("172.17.0.13", 8080, "default/app-3")
+	b1.AcquireEndpoint("172.17.0.14", 8080, "")
+	b1.AddEmptyEndpoint()
+	b2 := c.config.Backends().AcquireBackend("default", "app", "8443")
+	b2.AcquireEndpoint("172.17.0.11", 8443, "default/app-1")
+	b2.AcquireEndpoint("172.17.0.12", 8443, "default/app-2")
+
+	states := []*socket.ServerState{
+		{Backend: b1.ID, Name: "srv001", OpState: socket.SrvOpRunning},
+		{Backend: b1.ID, Name: "srv002", OpState: socket.SrvOpRunning},
+		{Backend: b1.ID, Name: "srv003", OpState: socket.SrvOpStopped},
+		{Backend: b1.ID, Name: "srv004", OpState: socket.SrvOpRunning},
+		{Backend: b1.ID, Name: "srv005", OpState: socket.SrvOpStopped, AdminState: 0x20},
+		{Backend: b2.ID, Name: "srv001", OpState: socket.SrvOpRunning},
+		{Backend: b2.ID, Name: "srv002", OpState: socket.SrvOpRunning, AdminState: 0x01},
+	}
+	expected := map[string]bool{
+		"default/app-1": true,
+		"default/app-2": false,
+		"default/app-3": false,
+	}
+	if actual := c.instance.TargetsUp(states); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("targets up differ\nexpected: %v\n  actual: %v", expected, actual)
+	}
+	c.logger.CompareLogging("")
+}
+
+func TestEjectedServers(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b1 := c.config.Backends().AcquireBackend("default", "app", "8080")
+	b1.HealthCheck.Observe = "layer7"
+	b1.AcquireEndpoint("172.17.0.11", 8080, "")
+	b1.AcquireEndpoint("172.17.0.12", 8080, "")
+	b1.AddEmptyEndpoint()
+	b2 := c.config.Backends().AcquireBackend("default", "web", "80")
+	b2.AcquireEndpoint("172.17.0.21", 80, "")
+
+	serversDown := []string{
+		b1.ID + "/srv002",
+		b1.ID + "/srv003",
+		b1.ID + "/srv009",
+		b2.ID + "/srv001",
+		"default_missing_80/srv001",
+	}
+	var actual []string
+	for _, server := range c.instance.EjectedServers(serversDown) {
+		actual = append(actual, server.Service.String()+" "+server.Backend+"/"+server.Server+" "+server.Endpoint)
+	}
+	expected := []string{
+		"Service 'default/app' default_app_8080/srv002 172.17.0.12:8080",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ejected servers differ\nexpected: %v\n  actual: %v", expected, actual)
+	}
+	c.logger.CompareLogging("")
+}
@@ -21,10 +21,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -56,6 +56,9 @@ type InstanceOptions struct {
 	Metrics           types.Metrics
 	ReloadQueue       utils.Queue
 	ReloadStrategy    string
+	RollbackConfig    bool
+	RollbackDir       string
+	RollbackDirs      []string
 	SortEndpointsBy   string
 	StopCh            <-chan struct{}
 	TrackInstances    bool
@@ -67,23 +70,43 @@ type InstanceOptions struct {
 // Instance ...
 type Instance interface {
 	AcmeCheck(source string) (int, error)
+	CachePurge(hostname string) error
+	ExplainRoute(req *RouteRequest) (*RouteExplain, error)
 	ParseTemplates() error
 	Config() Config
 	CalcIdleMetric()
+	CheckServersState() []string
+	EjectedServers(serversDown []string) []*EjectedServer
+	Stats() ([]*socket.Stat, error)
+	ServersState() ([]*socket.ServerState, error)
+	Sessions() ([]*socket.Session, error)
+	BackendsHealth(stats []*socket.Stat) []*BackendHealth
+	TargetsUp(states []*socket.ServerState) map[string]bool
 	AcmeUpdate()
 	HAProxyUpdate(timer *utils.Timer)
+	CheckConfig() error
+	WriteConfig() error
 	Reload(timer *utils.Timer)
+	StartLastConfig() error
+	Synced() bool
+	FallbackState() *FallbackState
 	Shutdown()
 }
 
 // CreateInstance ...
 func CreateInstance(logger types.Logger, options InstanceOptions) Instance {
+	var snapshot *configSnapshot
+	if options.RollbackConfig {
+		dirs := append([]string{options.HAProxyCfgDir}, options.RollbackDirs...)
+		snapshot = newConfigSnapshot(options.RollbackDir, dirs)
+	}
 	return &instance{
 		waitProc: make(chan struct{}),
 		logger:   logger,
 		options:  &options,
 		conns:    newConnections(options.MasterSocket, options.AdminSocket),
 		metrics:  options.Metrics,
+		snapshot: snapshot,
 		//
 		haproxyTmpl:     template.CreateConfig(),
 		mapsTmpl:        template.CreateConfig(),
@@ -94,14 +117,20 @@ func CreateInstance(logger types.Logger, options InstanceOptions) Instance {
 }
 
 type instance struct {
-	up          bool
+	up          atomic.Bool
+	synced      atomic.Bool
 	waitProc    chan struct{}
 	failedSince *time.Time
+	fallback    *FallbackState
+	snapshot    *configSnapshot
 	logger      types.Logger
 	options     *InstanceOptions
 	config      Config
 	conns       *connections
 	metrics     types.Metrics
+	serversDown map[string]bool
+	//
+	backendCounters map[string]backendCounters
 	//
 	haproxyTmpl     *template.Config
 	mapsTmpl        *template.Config
@@ -112,7 +141,7 @@ type instance struct {
 
 func (i *instance) AcmeCheck(source string) (int, error) {
 	var count int
-	if !i.up {
+	if !i.up.Load() {
 		return count, fmt.Errorf("controller wasn't started yet")
 	}
 	if i.options.AcmeQueue == nil {
@@ -141,6 +170,44 @@ func (i *instance) AcmeCheck(source string) (int, error) {
 	return count, nil
 }
 
+func (i *instance) CachePurge(hostname string) error {
+	if !i.up.Load() {
+		return fmt.Errorf("controller wasn't started yet")
+	}
+	cache := i.config.Global().Cache
+	if !cache.Enabled {
+		return fmt.Errorf("cache is not enabled")
+	}
+	hostname = strings.ToLower(hostname)
+	if hostname == "" || strings.ContainsAny(hostname, " \t\r\n") {
+		return fmt.Errorf("invalid hostname: '%s'", hostname)
+	}
+	// HAProxy cannot remove objects from its cache, so the host skips the cache
+	// lookup until all the objects stored before the purge expire. Responses
+	// are still stored in the meantime, replacing the old objects.
+	expire := time.Now().Unix() + int64(cache.MaxAge) + 1
+	msg, err := i.conns.DynUpdate().Send(i.metrics.HAProxySetMapResponseTime,
+		fmt.Sprintf("del map %s %s", cache.PurgeMapFile, hostname),
+		fmt.Sprintf("add map %s %s %d", cache.PurgeMapFile, hostname, expire),
+	)
+	if err != nil {
+		return err
+	}
+	// `del map` response is ignored, it fails if the host wasn't purged before
+	if len(msg) > 1 && strings.TrimSpace(msg[1]) != "" {
+		return fmt.Errorf("unrecognized response purging cache: %s", strings.TrimSpace(msg[1]))
+	}
+	i.logger.Info("purged cache of host '%s'", hostname)
+	return nil
+}
+
+func (i *instance) ExplainRoute(req *RouteRequest) (*RouteExplain, error) {
+	if !i.up.Load() {
+		return nil, fmt.Errorf("controller wasn't started yet")
+	}
+	return i.config.ExplainRoute(req)
+}
+
 func (i *instance) acmeEnsureConfig(acmeConfig *hatypes.AcmeData) bool {
 	signer := i.options.AcmeSigner
 	signer.AcmeConfig(acmeConfig.Expiring)
@@ -231,29 +298,105 @@ func (i *instance) Config() Config {
 	return i.config
 }
 
-var idleRegex = regexp.MustCompile(`Idle_pct: ([0-9]+)`)
-
 func (i *instance) CalcIdleMetric() {
-	if !i.up {
+	if !i.up.Load() {
 		return
 	}
-	msg, err := i.conns.IdleChk().Send(i.metrics.HAProxyShowInfoResponseTime, "show info")
+	info, err := socket.HAProxyInfo(i.conns.IdleChk(), i.metrics.HAProxyShowInfoResponseTime)
 	if err != nil {
 		i.logger.Error("error reading admin socket: %v", err)
 		return
 	}
-	idleStr := idleRegex.FindStringSubmatch(msg[0])
-	if len(idleStr) < 2 {
+	idleStr, found := info.Fields["Idle_pct"]
+	if !found {
 		i.logger.Error("cannot find Idle_pct field in the show info socket command")
 		return
 	}
-	idle, err := strconv.Atoi(idleStr[1])
+	idle, err := strconv.Atoi(idleStr)
 	if err != nil {
-		i.logger.Error("Idle_pct has an invalid integer: %s", idleStr[1])
+		i.logger.Error("Idle_pct has an invalid integer: %s", idleStr)
 	}
 	i.metrics.AddIdleFactor(idle)
 }
 
+// CheckServersState reads the operational state of the backend servers,
+// logging and counting the ones that went down since the last check, either
+// due to active health checks or due to errors observed on live traffic. The
+// `<backend>/<server>` names of the servers that went down are returned.
+func (i *instance) CheckServersState() []string {
+	if !i.up.Load() {
+		return nil
+	}
+	state, err := i.conns.ServersChk().Send(nil, "show servers state")
+	if err != nil {
+		i.logger.Error("error reading admin socket: %v", err)
+		return nil
+	}
+	serversDown := parseServersDown(state[0])
+	backendsDown := map[string]int{}
+	var wentDown []string
+	for server := range serversDown {
+		backend, name, _ := strings.Cut(server, "/")
+		if !i.serversDown[server] {
+			i.logger.Warn("server '%s' of backend '%s' is down", name, backend)
+			i.metrics.IncServerDown(backend)
+			wentDown = append(wentDown, server)
+		}
+		backendsDown[backend]++
+	}
+	for server := range i.serversDown {
+		backend, name, _ := strings.Cut(server, "/")
+		if !serversDown[server] {
+			i.logger.Info("server '%s' of backend '%s' is up", name, backend)
+		}
+		if backendsDown[backend] == 0 {
+			i.metrics.SetServersDown(backend, 0)
+		}
+	}
+	for backend, count := range backendsDown {
+		i.metrics.SetServersDown(backend, count)
+	}
+	i.serversDown = serversDown
+	sort.Strings(wentDown)
+	return wentDown
+}
+
+// Stats returns the parsed `show stat` of the running haproxy.
+func (i *instance) Stats() ([]*socket.Stat, error) {
+	if !i.up.Load() {
+		return nil, fmt.Errorf("haproxy is not running")
+	}
+	return socket.HAProxyStat(i.conns.Stats())
+}
+
+// ServersState returns the parsed `show servers state` of the running haproxy.
+func (i *instance) ServersState() ([]*socket.ServerState, error) {
+	if !i.up.Load() {
+		return nil, fmt.Errorf("haproxy is not running")
+	}
+	return socket.HAProxyServersState(i.conns.Stats())
+}
+
+// Sessions returns the parsed `show sess` of the running haproxy.
+func (i *instance) Sessions() ([]*socket.Session, error) {
+	if !i.up.Load() {
+		return nil, fmt.Errorf("haproxy is not running")
+	}
+	return socket.HAProxySessions(i.conns.Stats())
+}
+
+// parseServersDown reads the output of `show servers state` and returns
+// the `<backend>/<server>` names of the servers that are down.
+func parseServersDown(state string) map[string]bool {
+	serversDown := map[string]bool{}
+	for _, server := range socket.ParseServersState(state) {
+		if server.IsDown() {
+			serversDown[server.Backend+"/"+server.Name] = true
+		}
+	}
+	return serversDown
+}
+
 func (i *instance) AcmeUpdate() {
 	if i.config == nil || i.options.AcmeQueue == nil {
 		return
@@ -312,6 +455,9 @@ func (i *instance) HAProxyUpdate(timer *utils.Timer) {
 	}
 	updater := i.newDynUpdater()
 	updated := updater.update()
+	if !updated {
+		i.trackReloadReasons(updater.reasons)
+	}
 	if i.options.SortEndpointsBy != "random" {
 		i.config.Backends().SortChangedEndpoints(i.options.SortEndpointsBy)
 	} else if !updated {
@@ -364,6 +510,39 @@ func (i *instance) HAProxyUpdate(timer *utils.Timer) {
 	}
 }
 
+// CheckConfig writes the configuration files of the current model and
+// validates them, without applying the changes. Used by instances whose
+// HAProxyCfgDir and HAProxyMapsDir do not belong to the running haproxy.
+func (i *instance) CheckConfig() error {
+	if err := i.WriteConfig(); err != nil {
+		return err
+	}
+	return i.check()
+}
+
+// WriteConfig writes the configuration files of the current model, without
+// validating or applying the changes.
+func (i *instance) WriteConfig() error {
+	if i.config == nil {
+		return fmt.Errorf("configuration was not created")
+	}
+	i.config.SyncConfig()
+	if err := i.config.WriteTCPServicesMaps(); err != nil {
+		return fmt.Errorf("error building tcp services maps: %w", err)
+	}
+	if err := i.config.WriteFrontendMaps(); err != nil {
+		return fmt.Errorf("error building frontend maps: %w", err)
+	}
+	if err := i.config.WriteBackendMaps(); err != nil {
+		return fmt.Errorf("error building backend maps: %w", err)
+	}
+	i.config.Backends().FillSourceIPs()
+	if err := i.writeConfig(); err != nil {
+		return fmt.Errorf("error writing configuration: %w", err)
+	}
+	return nil
+}
+
 func (i *instance) Reload(timer *utils.Timer) {
 	i.metrics.IncUpdateFull()
 	if i.options.TrackInstances {
@@ -373,6 +552,21 @@ func (i *instance) Reload(timer *utils.Timer) {
 			i.logger.Error("error tracking instance: %v", err)
 		}
 	}
+	if i.snapshot != nil {
+		// validating before reloading, so a rejected configuration can be
+		// rolled back before haproxy has a chance to read it
+		err := checkConfig(i)
+		timer.Tick("validate_cfg")
+		if err != nil {
+			i.logger.Error("error validating config file:\n%v", err)
+			i.updateSuccessful(false)
+			if i.options.TrackInstances {
+				i.conns.ReleaseLastInstance()
+			}
+			i.rollbackConfig(err)
+			return
+		}
+	}
 	err := i.reloadHAProxy()
 	timer.Tick("reload_haproxy")
 	if err != nil {
@@ -381,10 +575,17 @@ func (i *instance) Reload(timer *utils.Timer) {
 		if i.options.TrackInstances {
 			i.conns.ReleaseLastInstance()
 		}
+		if i.snapshot != nil {
+			i.rollbackConfig(err)
+		}
 		return
 	}
-	i.up = true
+	i.up.Store(true)
+	i.synced.Store(true)
 	i.updateSuccessful(true)
+	if i.snapshot != nil {
+		i.snapshotConfig()
+	}
 	message := "haproxy successfully reloaded"
 	if i.options.IsExternal {
 		message += " (external)"
@@ -399,8 +600,98 @@ func (i *instance) Reload(timer *utils.Timer) {
 	i.logger.Info(message)
 }
 
+// StartLastConfig starts haproxy with the configuration files found in the
+// configuration directory, written by a former controller process, so
+// haproxy can handle requests while the first model is being built. The
+// first sync is applied as a regular reload.
+func (i *instance) StartLastConfig() error {
+	if i.up.Load() {
+		return fmt.Errorf("haproxy is already running")
+	}
+	if i.options.IsExternal {
+		return fmt.Errorf("lifecycle of an external haproxy is not controlled by HAProxy Ingress")
+	}
+	cfgFile := filepath.Join(i.options.HAProxyCfgDir, "haproxy.cfg")
+	if _, err := os.Stat(cfgFile); err != nil {
+		return fmt.Errorf("cannot read the last written configuration: %w", err)
+	}
+	if err := i.check(); err != nil {
+		return fmt.Errorf("last written configuration is not valid:\n%w", err)
+	}
+	// the model is still empty, so the servers state file is not updated
+	// here; it is still loaded by haproxy if the last configuration asks for it
+	i.Config()
+	if err := i.reloadHAProxy(); err != nil {
+		return fmt.Errorf("error starting haproxy: %w", err)
+	}
+	i.up.Store(true)
+	if i.snapshot != nil {
+		i.snapshotConfig()
+	}
+	i.logger.Info("haproxy started from the last written configuration")
+	return nil
+}
+
+// Synced reports whether haproxy is running a configuration built from
+// the model, i.e. at least one reload has succeeded. It is safe to be
+// called concurrently with the updates.
+func (i *instance) Synced() bool {
+	return i.synced.Load()
+}
+
+// FallbackState returns the state of the last known good configuration,
+// or nil if haproxy is running the current configuration.
+func (i *instance) FallbackState() *FallbackState {
+	if i.fallback == nil {
+		return nil
+	}
+	state := *i.fallback
+	return &state
+}
+
+// rollbackConfig restores the last known good configuration, after the
+// current one was rejected by haproxy. The resources that declared the
+// rejected backends are logged, and the instance enters the fallback
+// state until a new configuration is successfully loaded.
+func (i *instance) rollbackConfig(reason error) {
+	if i.fallback == nil {
+		i.fallback = &FallbackState{Since: time.Now()}
+	}
+	i.fallback.Reason = strings.TrimSpace(reason.Error())
+	i.metrics.SetFallbackConfig(true)
+	for _, backend := range parseRejectedBackends(reason.Error()) {
+		for _, source := range i.backendSources(backend.id) {
+			i.logger.Warn("configuration of backend '%s' rejected by haproxy, declared by %s: %s", backend.id, source, backend.message)
+		}
+	}
+	cfgFile := filepath.Join(i.options.HAProxyCfgDir, "haproxy.cfg")
+	if rejected, err := i.snapshot.saveRejected(cfgFile); err != nil {
+		i.logger.Warn("error saving the rejected configuration: %v", err)
+	} else {
+		i.logger.Info("rejected configuration saved to %s", rejected)
+	}
+	if err := i.snapshot.restore(); err != nil {
+		i.logger.Error("error restoring the last known good configuration: %v", err)
+		return
+	}
+	i.logger.Warn("last known good configuration restored, haproxy will be reloaded as soon as a valid configuration is built")
+}
+
+// snapshotConfig updates the last known good configuration with the
+// configuration that haproxy just loaded, leaving the fallback state.
+func (i *instance) snapshotConfig() {
+	if err := i.snapshot.save(); err != nil {
+		i.logger.Error("error saving the last known good configuration: %v", err)
+	}
+	if i.fallback != nil {
+		i.logger.Info("haproxy is running the current configuration, leaving the fallback state started at %s", i.fallback.Since.Format("2006-01-02 15:04:05.999999 -0700 MST"))
+		i.fallback = nil
+		i.metrics.SetFallbackConfig(false)
+	}
+}
+
 func (i *instance) Shutdown() {
-	if !i.up || i.options.IsExternal {
+	if !i.up.Load() || i.options.IsExternal {
 		// lifecycle isn't controlled by HAProxy Ingress
 		return
 	}
@@ -459,6 +750,26 @@ func (i *instance) logChanged() {
 	}
 }
 
+// trackReloadReasons logs the changes that prevented a dynamic update,
+// and counts them once per reason and namespace.
+func (i *instance) trackReloadReasons(reasons reloadReasons) {
+	if len(reasons) == 0 {
+		reasons = reloadReasons{{reason: "unknown"}}
+	}
+	counted := make(map[[2]string]bool, len(reasons))
+	for _, reason := range reasons {
+		key := [2]string{reason.reason, reason.namespace}
+		if !counted[key] {
+			counted[key] = true
+			i.metrics.IncReloadReason(reason.reason, reason.namespace)
+		}
+	}
+	if !i.options.fake {
+		// TODO update tests and remove `if !fake`
+		i.logger.Info("reload required: %s", reasons)
+	}
+}
+
 func (i *instance) writeConfig() (err error) {
 	//
 	// modsec template execution
@@ -561,6 +872,10 @@ func (i *instance) updateCertExpiring() {
 	}
 }
 
+// checkConfig validates the configuration files before a reload,
+// overridden by tests that need to simulate a rejected configuration.
+var checkConfig = (*instance).check
+
 func (i *instance) check() error {
 	if i.options.fake {
 		i.logger.Info("(test) check was skipped")
@@ -573,6 +888,9 @@ func (i *instance) check() error {
 		out, err := exec.Command("haproxy", "-c", "-f", i.options.HAProxyCfgDir).CombinedOutput()
 		outstr := string(out)
 		if err != nil {
+			if outstr == "" {
+				return err
+			}
 			return fmt.Errorf(outstr)
 		}
 	}
@@ -613,7 +931,7 @@ func (i *instance) reloadEmbeddedDaemon() error {
 }
 
 func (i *instance) reloadEmbeddedMasterWorker() error {
-	if !i.up {
+	if !i.up.Load() {
 		go func() {
 			wait.Until(i.startHAProxySync, 4*time.Second, i.options.StopCh)
 			close(i.waitProc)
@@ -666,7 +984,7 @@ func (i *instance) startHAProxySync() {
 }
 
 func (i *instance) reloadExternal() error {
-	if !i.up {
+	if !i.up.Load() {
 		// first run, wait until the external haproxy is running
 		// and successfully listening to the master socket.
 		if err := i.waitMaster(); err != nil {
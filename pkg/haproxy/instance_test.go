@@ -17,10 +17,13 @@ limitations under the License.
 package haproxy
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -785,6 +788,52 @@ d1.local#/ path01`,
     option httpchk /check`,
 			srvsuffix: "check port 4000",
 		},
+		{
+			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
+				b.HealthCheck.URI = "/check"
+				b.HealthCheck.Method = "GET"
+				b.HealthCheck.Host = "app.local"
+				b.HealthCheck.Headers = []*hatypes.BackendHeader{{Name: "X-Check", Value: "some value"}}
+				b.HealthCheck.ExpectStatus = "200-299,401"
+				b.HealthCheck.ExpectBody = "^ok$"
+				b.HealthCheck.Interval = "2s"
+			},
+			expected: `
+    option httpchk
+    http-check send meth GET uri /check ver HTTP/1.1 hdr host app.local hdr X-Check 'some value'
+    http-check expect status 200-299,401
+    http-check expect rstring '^ok$'`,
+			srvsuffix: "check inter 2s",
+		},
+		{
+			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
+				b.Server.Protocol = "h2"
+				b.Server.Secure = true
+				b.HealthCheck.Protocol = "grpc"
+				b.HealthCheck.Interval = "2s"
+			},
+			expected: `
+    option httpchk
+    http-check send meth POST uri /grpc.health.v1.Health/Check hdr content-type application/grpc hdr te trailers body-hex 0000000000
+    http-check expect status 200
+    http-check expect binary 00000000020801`,
+			srvsuffix: "proto h2 alpn h2 ssl verify none check check-proto h2 check-alpn h2 inter 2s",
+		},
+		{
+			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
+				b.HealthCheck.Observe = "layer7"
+			},
+			srvsuffix: "check observe layer7",
+		},
+		{
+			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
+				b.HealthCheck.Interval = "2s"
+				b.HealthCheck.Observe = "layer7"
+				b.HealthCheck.ErrorLimit = 5
+				b.HealthCheck.OnError = "mark-down"
+			},
+			srvsuffix: "check inter 2s observe layer7 error-limit 5 on-error mark-down",
+		},
 		{
 			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
 				b.AgentCheck.Port = 8000
@@ -1076,6 +1125,26 @@ frontend _front_https
 				"_back_d1_app_8080_idpathdef__prefix_02.map": "<default>#/app1 path02",
 			},
 		},
+		{
+			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
+				b.Endpoints = nil
+				b.Response = &hatypes.BackendResponse{
+					StatusCode:  503,
+					ContentType: "text/html",
+					Headers: []hatypes.HTTPHeader{
+						{Name: "Retry-After", Value: "120"},
+						{Name: "Cache-Control", Value: "no-cache"},
+					},
+					Body: "<h1>under maintenance</h1>",
+				}
+			},
+			skipSrv: true,
+			expected: `
+    http-request return status 503 content-type "text/html" file /etc/haproxy/maps/_back_d1_app_8080_response.body hdr "Retry-After" "120" hdr "Cache-Control" "no-cache"`,
+			expCheck: map[string]string{
+				"_back_d1_app_8080_response.body": "<h1>under maintenance</h1>",
+			},
+		},
 		{
 			doconfig: func(c *config, h *hatypes.Host, b *hatypes.Backend) {
 				b.ModeTCP = true
@@ -1169,6 +1238,45 @@ func TestInstanceClean(t *testing.T) {
 	c.logger.CompareLogging(defaultLogging)
 }
 
+func TestInstanceStartLastConfig(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	if err := c.instance.StartLastConfig(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+	c.Update()
+	c.logger.CompareLogging(defaultLogging)
+	if !c.instance.Synced() {
+		t.Errorf("expected synced instance after the first update")
+	}
+	if err := c.instance.StartLastConfig(); err == nil {
+		t.Errorf("expected error starting a running instance")
+	}
+
+	c2 := setup(t)
+	defer c2.teardown()
+	data, _ := os.ReadFile(filepath.Join(c.tempdir, "haproxy.cfg"))
+	if err := os.WriteFile(filepath.Join(c2.tempdir, "haproxy.cfg"), data, 0644); err != nil {
+		t.Fatalf("error writing config: %v", err)
+	}
+	if err := c2.instance.StartLastConfig(); err != nil {
+		t.Errorf("error starting from the last config: %v", err)
+	}
+	c2.logger.CompareLogging(`
+INFO (test) check was skipped
+INFO (test) reload was skipped
+INFO haproxy started from the last written configuration`)
+	if c2.instance.Synced() {
+		t.Errorf("expected not synced instance before the first update")
+	}
+	c2.Update()
+	c2.logger.CompareLogging(defaultLogging)
+	if !c2.instance.Synced() {
+		t.Errorf("expected synced instance after the first update")
+	}
+}
+
 func TestInstanceBare(t *testing.T) {
 	c := setup(t)
 	defer c.teardown()
@@ -4702,6 +4810,593 @@ backend d3_app_http
 	c.logger.CompareLogging(defaultLogging)
 }
 
+func TestPeers(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.config.Global().Peers = hatypes.PeersConfig{
+		LocalPeer: "ingress-1",
+		Port:      10000,
+		Servers: []hatypes.PeerServer{
+			{Name: "ingress-1", IP: "10.0.0.1"},
+			{Name: "ingress-2", IP: "10.0.0.2"},
+			{Name: "ingress-3", IP: "10.0.0.3"},
+		},
+	}
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	b.Limit.RPS = 20
+	b.Limit.Connections = 10
+	b.Limit.Whitelist = []string{"10.0.0.0/8"}
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	b.ModeTCP = true
+	b.Limit.Connections = 5
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+
+	b = c.config.Backends().AcquireBackend("d3", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d3.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	h.AddPath(b, "/api", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/api")[0].Link).RateLimit = hatypes.RateLimit{
+		Requests:   100,
+		Window:     "10s",
+		Key:        "req.hdr(X-Api-Key)",
+		KeyType:    "string",
+		Exempt:     []string{"10.0.0.0/8", "192.168.0.0/16"},
+		RetryAfter: 10,
+	}
+
+	c.Update()
+	c.checkConfig(`
+global
+    daemon
+    unix-bind mode 0600
+    stats socket /var/run/haproxy.sock level admin expose-fd listeners mode 600
+    maxconn 2000
+    localpeer ingress-1
+    hard-stop-after 15m
+    lua-prepend-path /etc/haproxy/lua/?.lua
+    lua-load /etc/haproxy/lua/auth-request.lua
+    lua-load /etc/haproxy/lua/services.lua
+    lua-load /etc/haproxy/lua/responses.lua
+    ssl-dh-param-file /var/haproxy/tls/dhparam.pem
+    ssl-default-bind-ciphers ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES128-GCM-SHA256
+    ssl-default-bind-ciphersuites TLS_AES_128_GCM_SHA256
+    ssl-default-bind-options no-sslv3
+    ssl-default-server-ciphers ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES128-GCM-SHA256
+    ssl-default-server-ciphersuites TLS_AES_128_GCM_SHA256
+<<defaults>>
+peers ingress
+    peer ingress-1 10.0.0.1:10000
+    peer ingress-2 10.0.0.2:10000
+    peer ingress-3 10.0.0.3:10000
+    table d1_app_8080_ingress-1 type ip size 200k expire 5m store conn_cur,conn_rate(1s)
+    table d1_app_8080_ingress-2 type ip size 200k expire 5m store conn_cur,conn_rate(1s)
+    table d1_app_8080_ingress-3 type ip size 200k expire 5m store conn_cur,conn_rate(1s)
+    table d2_app_8080_ingress-1 type ip size 200k expire 5m store conn_cur,conn_rate(1s)
+    table d2_app_8080_ingress-2 type ip size 200k expire 5m store conn_cur,conn_rate(1s)
+    table d2_app_8080_ingress-3 type ip size 200k expire 5m store conn_cur,conn_rate(1s)
+    table _limit_d3_app_8080_1_ingress-1 type string len 128 size 200k expire 10s store http_req_rate(10s)
+    table _limit_d3_app_8080_1_ingress-2 type string len 128 size 200k expire 10s store http_req_rate(10s)
+    table _limit_d3_app_8080_1_ingress-3 type string len 128 size 200k expire 10s store http_req_rate(10s)
+backend d1_app_8080
+    mode http
+    http-request track-sc1 src table ingress/d1_app_8080_ingress-1
+    acl wlist_conn src 10.0.0.0/8
+    http-request set-var(txn.limit_conn_cur) sc1_conn_cur
+    http-request set-var(txn.limit_conn_cur) src,table_conn_cur(ingress/d1_app_8080_ingress-2),add(txn.limit_conn_cur)
+    http-request set-var(txn.limit_conn_cur) src,table_conn_cur(ingress/d1_app_8080_ingress-3),add(txn.limit_conn_cur)
+    http-request deny deny_status 429 if !wlist_conn { var(txn.limit_conn_cur) gt 10 }
+    http-request set-var(txn.limit_conn_rate) sc1_conn_rate
+    http-request set-var(txn.limit_conn_rate) src,table_conn_rate(ingress/d1_app_8080_ingress-2),add(txn.limit_conn_rate)
+    http-request set-var(txn.limit_conn_rate) src,table_conn_rate(ingress/d1_app_8080_ingress-3),add(txn.limit_conn_rate)
+    http-request deny deny_status 429 if !wlist_conn { var(txn.limit_conn_rate) gt 20 }
+    server s1 172.17.0.11:8080 weight 100
+backend d2_app_8080
+    mode tcp
+    tcp-request content track-sc1 src table ingress/d2_app_8080_ingress-1
+    tcp-request content set-var(txn.limit_conn_cur) sc1_conn_cur
+    tcp-request content set-var(txn.limit_conn_cur) src,table_conn_cur(ingress/d2_app_8080_ingress-2),add(txn.limit_conn_cur)
+    tcp-request content set-var(txn.limit_conn_cur) src,table_conn_cur(ingress/d2_app_8080_ingress-3),add(txn.limit_conn_cur)
+    tcp-request content reject if { var(txn.limit_conn_cur) gt 5 }
+    server s1 172.17.0.11:8080 weight 100
+backend d3_app_8080
+    mode http
+    # path01 = d3.local/
+    # path02 = d3.local/api
+    http-request set-var(txn.pathID) var(req.base),lower,map_beg(/etc/haproxy/maps/_back_d3_app_8080_idpath__begin.map)
+    http-request track-sc2 req.hdr(X-Api-Key) table ingress/_limit_d3_app_8080_1_ingress-1 if { var(txn.pathID) -m str path02 } !{ src 10.0.0.0/8 192.168.0.0/16 }
+    http-request set-var(txn.limit_req_rate) sc2_http_req_rate if { var(txn.pathID) -m str path02 } !{ src 10.0.0.0/8 192.168.0.0/16 }
+    http-request set-var(txn.limit_req_rate) req.hdr(X-Api-Key),table_http_req_rate(ingress/_limit_d3_app_8080_1_ingress-2),add(txn.limit_req_rate) if { var(txn.pathID) -m str path02 } !{ src 10.0.0.0/8 192.168.0.0/16 }
+    http-request set-var(txn.limit_req_rate) req.hdr(X-Api-Key),table_http_req_rate(ingress/_limit_d3_app_8080_1_ingress-3),add(txn.limit_req_rate) if { var(txn.pathID) -m str path02 } !{ src 10.0.0.0/8 192.168.0.0/16 }
+    http-request set-var(txn.limit_retry_after) int(10) if { var(txn.pathID) -m str path02 } { var(txn.limit_req_rate) gt 100 }
+    http-request deny deny_status 429 if { var(txn.limit_retry_after) -m found }
+    http-after-response set-header Retry-After %[var(txn.limit_retry_after)] if { var(txn.limit_retry_after) -m found }
+    server s1 172.17.0.11:8080 weight 100
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceRateLimit(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	h.AddPath(b, "/api", hatypes.MatchBegin)
+	h.AddPath(b, "/app", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/api")[0].Link).RateLimit = hatypes.RateLimit{
+		Requests:   100,
+		Window:     "10s",
+		Key:        "req.hdr(X-Api-Key)",
+		KeyType:    "string",
+		Exempt:     []string{"10.0.0.0/8"},
+		RetryAfter: 10,
+	}
+	b.FindBackendPath(h.FindPath("/app")[0].Link).RateLimit = hatypes.RateLimit{
+		Requests:   20,
+		Window:     "1s",
+		Key:        "src",
+		KeyType:    "ip",
+		RetryAfter: 1,
+	}
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/")[0].Link).RateLimit = hatypes.RateLimit{
+		Requests:   20,
+		Window:     "1s",
+		Key:        "src",
+		KeyType:    "ip",
+		RetryAfter: 1,
+	}
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+backend d1_app_8080
+    mode http
+    # path01 = d1.local/
+    # path02 = d1.local/api
+    # path03 = d1.local/app
+    http-request set-var(txn.pathID) var(req.base),lower,map_beg(/etc/haproxy/maps/_back_d1_app_8080_idpath__begin.map)
+    http-request track-sc2 req.hdr(X-Api-Key) table _limit_d1_app_8080_1 if { var(txn.pathID) -m str path02 } !{ src 10.0.0.0/8 }
+    http-request set-var(txn.limit_retry_after) int(10) if { var(txn.pathID) -m str path02 } { sc2_http_req_rate gt 100 }
+    http-request track-sc2 src table _limit_d1_app_8080_2 if { var(txn.pathID) -m str path03 }
+    http-request set-var(txn.limit_retry_after) int(1) if { var(txn.pathID) -m str path03 } { sc2_http_req_rate gt 20 }
+    http-request deny deny_status 429 if { var(txn.limit_retry_after) -m found }
+    http-after-response set-header Retry-After %[var(txn.limit_retry_after)] if { var(txn.limit_retry_after) -m found }
+    server s1 172.17.0.11:8080 weight 100
+backend _limit_d1_app_8080_1
+    stick-table type string len 128 size 200k expire 10s store http_req_rate(10s)
+backend _limit_d1_app_8080_2
+    stick-table type ip size 200k expire 1s store http_req_rate(1s)
+backend d2_app_8080
+    mode http
+    http-request track-sc2 src table _limit_d2_app_8080_0
+    http-request set-var(txn.limit_retry_after) int(1) if { sc2_http_req_rate gt 20 }
+    http-request deny deny_status 429 if { var(txn.limit_retry_after) -m found }
+    http-after-response set-header Retry-After %[var(txn.limit_retry_after)] if { var(txn.limit_retry_after) -m found }
+    server s1 172.17.0.11:8080 weight 100
+backend _limit_d2_app_8080_0
+    stick-table type ip size 200k expire 1s store http_req_rate(1s)
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceCache(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	c.config.Global().Cache = hatypes.CacheConfig{
+		TotalMaxSize:  128,
+		MaxObjectSize: 1048576,
+		MaxAge:        60,
+	}
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	h.AddPath(b, "/api", hatypes.MatchBegin)
+	h.AddPath(b, "/static", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/api")[0].Link).Cache = hatypes.Cache{
+		Enabled: true,
+		Bypass:  []string{"req.hdr(X-No-Cache)", "req.cook(session)"},
+	}
+	b.FindBackendPath(h.FindPath("/static")[0].Link).Cache = hatypes.Cache{
+		Enabled: true,
+		Vary:    []string{"Accept-Encoding", "Origin"},
+	}
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/")[0].Link).Cache = hatypes.Cache{
+		Enabled: true,
+	}
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+cache ingress
+    total-max-size 128
+    max-object-size 1048576
+    max-age 60
+    process-vary on
+backend d1_app_8080
+    mode http
+    # path01 = d1.local/
+    # path02 = d1.local/api
+    # path03 = d1.local/static
+    http-request set-var(txn.pathID) var(req.base),lower,map_beg(/etc/haproxy/maps/_back_d1_app_8080_idpath__begin.map)
+    filter cache ingress
+    http-request set-var(txn.cache_now) date()
+    http-request set-var(txn.cache_bypass) bool(true) if { var(txn.pathID) -m str path02 } { req.hdr(X-No-Cache) -m found }
+    http-request set-var(txn.cache_bypass) bool(true) if { var(txn.pathID) -m str path02 } { req.cook(session) -m found }
+    http-request cache-use ingress if { var(txn.pathID) -m str path02 } !{ var(txn.cache_bypass) -m bool } !{ var(req.host),map_str_int(/etc/haproxy/maps/_global_cache_purge.map,0),sub(txn.cache_now) -m int gt 0 }
+    http-request cache-use ingress if { var(txn.pathID) -m str path03 } !{ var(txn.cache_bypass) -m bool } !{ var(req.host),map_str_int(/etc/haproxy/maps/_global_cache_purge.map,0),sub(txn.cache_now) -m int gt 0 }
+    http-response cache-store ingress if { var(txn.pathID) -m str path02 } !{ var(txn.cache_bypass) -m bool }
+    http-response set-header Vary %[res.fhdr(Vary)],Accept-Encoding,Origin if { var(txn.pathID) -m str path03 } { res.hdr(Vary) -m found }
+    http-response set-header Vary Accept-Encoding,Origin if { var(txn.pathID) -m str path03 } !{ res.hdr(Vary) -m found }
+    http-response cache-store ingress if { var(txn.pathID) -m str path03 } !{ var(txn.cache_bypass) -m bool }
+    server s1 172.17.0.11:8080 weight 100
+backend d2_app_8080
+    mode http
+    filter cache ingress
+    http-request set-var(txn.cache_now) date()
+    http-request cache-use ingress if !{ var(txn.cache_bypass) -m bool } !{ var(req.host),map_str_int(/etc/haproxy/maps/_global_cache_purge.map,0),sub(txn.cache_now) -m int gt 0 }
+    http-response cache-store ingress if !{ var(txn.cache_bypass) -m bool }
+    server s1 172.17.0.11:8080 weight 100
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.checkMap("_global_cache_purge.map", ``)
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceCompression(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	h.AddPath(b, "/api", hatypes.MatchBegin)
+	h.AddPath(b, "/download", hatypes.MatchBegin)
+	b.Compression = hatypes.BackendCompression{
+		Algorithms: []string{"gzip", "deflate"},
+		MinSize:    1024,
+		Types:      []string{"text/html", "application/json"},
+	}
+	b.FindBackendPath(h.FindPath("/")[0].Link).Compression.Enabled = true
+	b.FindBackendPath(h.FindPath("/api")[0].Link).Compression.Enabled = true
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.Compression = hatypes.BackendCompression{
+		Algorithms: []string{"gzip"},
+		Offload:    true,
+	}
+	b.FindBackendPath(h.FindPath("/")[0].Link).Compression.Enabled = true
+
+	b = c.config.Backends().AcquireBackend("d3", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d3.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.Compression = hatypes.BackendCompression{
+		Algorithms: []string{"gzip"},
+		Offload:    true,
+	}
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+backend d1_app_8080
+    mode http
+    # path01 = d1.local/
+    # path02 = d1.local/api
+    # path03 = d1.local/download
+    http-request set-var(txn.pathID) var(req.base),lower,map_beg(/etc/haproxy/maps/_back_d1_app_8080_idpath__begin.map)
+    filter compression
+    compression algo gzip deflate
+    compression type text/html application/json
+    compression minsize-res 1024
+    http-response set-header Cache-Control no-transform if { var(txn.pathID) -m str path03 } !{ res.hdr(Content-Encoding) -m found } !{ res.hdr(Cache-Control) -m found }
+    http-response replace-header Cache-Control ^(.*)$ \1,no-transform if { var(txn.pathID) -m str path03 } !{ res.hdr(Content-Encoding) -m found } !{ res.hdr(Cache-Control) -i -m str no-transform }
+    server s1 172.17.0.11:8080 weight 100
+backend d2_app_8080
+    mode http
+    filter compression
+    compression algo gzip
+    compression offload
+    server s1 172.17.0.11:8080 weight 100
+backend d3_app_8080
+    mode http
+    server s1 172.17.0.11:8080 weight 100
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceFallback(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	b.AddFallbackEndpoint("172.17.0.21", 8080, "")
+	b.AddFallbackEndpoint("172.17.0.22", 8080, "")
+	b.AddFallbackEndpoint("127.0.0.1", 1023, "").Enabled = false
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+backend d1_app_8080
+    mode http
+    server s1 172.17.0.11:8080 weight 100
+    option allbackups
+    server fallback001 172.17.0.21:8080 backup weight 1
+    server fallback002 172.17.0.22:8080 backup weight 1
+    server fallback003 127.0.0.1:1023 backup disabled weight 1
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceRetry(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	var h *hatypes.Host
+	var b *hatypes.Backend
+
+	b = c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.Retry = hatypes.BackendRetry{
+		Redispatch:     "true",
+		Retries:        "3",
+		RetryOn:        []string{"conn-failure", "503", "response-timeout"},
+		TimeoutConnect: "200ms",
+	}
+	b.Timeout.Connect = b.Retry.TimeoutConnect
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.Retry = hatypes.BackendRetry{
+		Redispatch: "false",
+		Retries:    "0",
+	}
+
+	b = c.config.Backends().AcquireBackend("d3", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	b.ModeTCP = true
+	h = c.config.Hosts().AcquireHost("d3.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.Retry = hatypes.BackendRetry{
+		Redispatch: "2",
+		RetryOn:    []string{"conn-failure"},
+	}
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+backend d1_app_8080
+    mode http
+    timeout connect 200ms
+    retries 3
+    retry-on conn-failure 503 response-timeout
+    option redispatch
+    server s1 172.17.0.11:8080 weight 100
+backend d2_app_8080
+    mode http
+    retries 0
+    no option redispatch
+    server s1 172.17.0.11:8080 weight 100
+backend d3_app_8080
+    mode tcp
+    option redispatch 2
+    server s1 172.17.0.11:8080 weight 100
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceAuthJWT(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	h.AddPath(b, "/api", hatypes.MatchBegin)
+	h.AddPath(b, "/app", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/api")[0].Link).AuthJWT = hatypes.AuthJWT{
+		Audience: "api",
+		ClaimHeaders: []hatypes.JWTClaimHeader{
+			{Claim: "sub", Header: "X-User"},
+		},
+		Issuer: "https://issuer.local/",
+		Keys: []hatypes.JWTKey{
+			{Algorithm: "RS256", ID: "k1", Key: "key1"},
+			{Algorithm: "ES256", ID: "k2", Key: "key2"},
+		},
+	}
+	b.FindBackendPath(h.FindPath("/app")[0].Link).AuthJWT = hatypes.AuthJWT{
+		Keys: []hatypes.JWTKey{
+			{Algorithm: "RS256", Key: "key1"},
+		},
+	}
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/")[0].Link).AuthJWT = hatypes.AuthJWT{
+		AlwaysDeny: true,
+	}
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+backend d1_app_8080
+    mode http
+    # path01 = d1.local/
+    # path02 = d1.local/api
+    # path03 = d1.local/app
+    http-request set-var(txn.pathID) var(req.base),lower,map_beg(/etc/haproxy/maps/_back_d1_app_8080_idpath__begin.map)
+    http-request set-var(txn.jwt) http_auth_bearer if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.jwt_alg) var(txn.jwt),jwt_header_query('$.alg') if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.jwt_kid) var(txn.jwt),jwt_header_query('$.kid') if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.jwt_verified) var(txn.jwt),jwt_verify("RS256","/etc/haproxy/maps/_back_d1_app_8080_jwt1.pem") if { var(txn.pathID) -m str path02 } { var(txn.jwt_alg) -m str RS256 } { var(txn.jwt_kid) -m str k1 }
+    http-request set-var(txn.jwt_verified) var(txn.jwt),jwt_verify("ES256","/etc/haproxy/maps/_back_d1_app_8080_jwt2.pem") if { var(txn.pathID) -m str path02 } { var(txn.jwt_alg) -m str ES256 } { var(txn.jwt_kid) -m str k2 } !{ var(txn.jwt_verified) -m int 1 }
+    http-request set-var(txn.jwt_exp) var(txn.jwt),jwt_payload_query('$.exp','int') if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.jwt_now) date() if { var(txn.pathID) -m str path02 }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path02 } !{ var(txn.jwt_verified) -m int 1 }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path02 } !{ var(txn.jwt_exp),sub(txn.jwt_now) -m int gt 0 }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path02 } !{ var(txn.jwt),jwt_payload_query('$.iss') -m str https://issuer.local/ }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path02 } !{ var(txn.jwt),jwt_payload_query('$.aud') -m str api }
+    http-request set-header X-User %[var(txn.jwt),jwt_payload_query('$.sub')] if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.jwt) http_auth_bearer if { var(txn.pathID) -m str path03 }
+    http-request set-var(txn.jwt_alg) var(txn.jwt),jwt_header_query('$.alg') if { var(txn.pathID) -m str path03 }
+    http-request set-var(txn.jwt_verified) var(txn.jwt),jwt_verify("RS256","/etc/haproxy/maps/_back_d1_app_8080_jwt1.pem") if { var(txn.pathID) -m str path03 } { var(txn.jwt_alg) -m str RS256 }
+    http-request set-var(txn.jwt_exp) var(txn.jwt),jwt_payload_query('$.exp','int') if { var(txn.pathID) -m str path03 }
+    http-request set-var(txn.jwt_now) date() if { var(txn.pathID) -m str path03 }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path03 } !{ var(txn.jwt_verified) -m int 1 }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path03 } !{ var(txn.jwt_exp),sub(txn.jwt_now) -m int gt 0 }
+    server s1 172.17.0.11:8080 weight 100
+backend d2_app_8080
+    mode http
+    http-request deny deny_status 401
+    server s1 172.17.0.11:8080 weight 100
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	for filename, key := range map[string]string{"_back_d1_app_8080_jwt1.pem": "key1", "_back_d1_app_8080_jwt2.pem": "key2"} {
+		content, err := os.ReadFile(c.tempdir + "/" + filename)
+		if err != nil {
+			t.Errorf("error reading %s: %v", filename, err)
+		} else if string(content) != key {
+			t.Errorf("%s content differs - expected: %s - actual: %s", filename, key, string(content))
+		}
+	}
+	c.logger.CompareLogging(defaultLogging)
+}
+
+func TestInstanceAuthAPIKey(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	b.APIKeys = []*hatypes.APIKeyMap{{
+		Name: "default_keys",
+		Keys: map[string]string{"key2": "app2", "key1": "app1"},
+	}}
+	h := c.config.Hosts().AcquireHost("d1.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	h.AddPath(b, "/api", hatypes.MatchBegin)
+	h.AddPath(b, "/app", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/api")[0].Link).AuthAPIKey = hatypes.AuthAPIKey{
+		Header:      "X-Api-Key",
+		LabelHeader: "X-Client",
+		MapName:     "default_keys",
+		Query:       "api_key",
+	}
+	b.FindBackendPath(h.FindPath("/app")[0].Link).AuthAPIKey = hatypes.AuthAPIKey{
+		MapName: "default_keys",
+		Query:   "api_key",
+	}
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	b.APIKeys = []*hatypes.APIKeyMap{{
+		Name: "default_keys",
+		Keys: map[string]string{},
+	}}
+	h = c.config.Hosts().AcquireHost("d2.local")
+	h.AddPath(b, "/", hatypes.MatchBegin)
+	b.FindBackendPath(h.FindPath("/")[0].Link).AuthAPIKey = hatypes.AuthAPIKey{
+		Header:  "X-Api-Key",
+		MapName: "default_keys",
+	}
+
+	c.Update()
+	c.checkConfig(`
+<<global>>
+<<defaults>>
+backend d1_app_8080
+    mode http
+    # path01 = d1.local/
+    # path02 = d1.local/api
+    # path03 = d1.local/app
+    http-request set-var(txn.pathID) var(req.base),lower,map_beg(/etc/haproxy/maps/_back_d1_app_8080_idpath__begin.map)
+    http-request set-var(txn.apikey_label) req.hdr(X-Api-Key),map(/etc/haproxy/maps/_back_d1_app_8080_apikey_default_keys.map) if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.apikey_label) url_param(api_key),map(/etc/haproxy/maps/_back_d1_app_8080_apikey_default_keys.map) if { var(txn.pathID) -m str path02 } !{ var(txn.apikey_label) -m found }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path02 } !{ var(txn.apikey_label) -m found }
+    http-request set-header X-Client %[var(txn.apikey_label)] if { var(txn.pathID) -m str path02 }
+    http-request set-var(txn.apikey_label) url_param(api_key),map(/etc/haproxy/maps/_back_d1_app_8080_apikey_default_keys.map) if { var(txn.pathID) -m str path03 }
+    http-request deny deny_status 401 if { var(txn.pathID) -m str path03 } !{ var(txn.apikey_label) -m found }
+    server s1 172.17.0.11:8080 weight 100
+backend d2_app_8080
+    mode http
+    http-request set-var(txn.apikey_label) req.hdr(X-Api-Key),map(/etc/haproxy/maps/_back_d2_app_8080_apikey_default_keys.map)
+    http-request deny deny_status 401 if !{ var(txn.apikey_label) -m found }
+    server s1 172.17.0.11:8080 weight 100
+<<backends-default>>
+<<frontends-default>>
+<<support>>
+`)
+	c.checkMap("_back_d1_app_8080_apikey_default_keys.map", `
+key1 app1
+key2 app2
+`)
+	c.checkMap("_back_d2_app_8080_apikey_default_keys.map", ``)
+	c.logger.CompareLogging(defaultLogging)
+}
+
 func TestUserlist(t *testing.T) {
 	type list struct {
 		name  string
@@ -5820,3 +6515,23 @@ func (c *testConfig) compareRawText(name, actual, expected string) {
 		c.t.Error("\ndiff of " + name + ":" + diff.Diff(expected, actual))
 	}
 }
+
+func TestParseServersDown(t *testing.T) {
+	state := `1
+# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state srv_uweight srv_iweight srv_time_since_last_change srv_check_status srv_check_result srv_check_health srv_check_state srv_agent_state bk_f_forced_id srv_f_forced_id srv_fqdn srv_port srvrecord srv_use_ssl srv_check_port srv_check_addr srv_agent_addr srv_agent_port
+3 d1_app_8080 1 srv001 172.17.0.11 2 0 1 1 10 6 3 4 6 0 0 0 - 8080 - 0 0 - - 0
+3 d1_app_8080 2 srv002 172.17.0.12 0 0 1 1 10 8 2 0 6 0 0 0 - 8080 - 0 0 - - 0
+3 d1_app_8080 3 srv003 127.0.0.1 0 5 1 1 10 1 0 0 14 0 0 0 - 1023 - 0 0 - - 0
+4 d2_app_8080 1 srv001 172.17.0.21 0 0 1 1 10 8 2 0 6 0 0 0 - 8080 - 0 0 - - 0
+4 d2_app_8080 2 srv002 172.17.0.22 0 8 1 1 10 8 2 0 6 0 0 0 - 8080 - 0 0 - - 0
+`
+	expected := map[string]bool{
+		"d1_app_8080/srv002": true,
+		"d2_app_8080/srv001": true,
+		"d2_app_8080/srv002": true,
+	}
+	actual := parseServersDown(state)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("servers down differ - expected: %v - actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FallbackState describes an instance whose last configuration was rejected
+// by haproxy, and that is running the last known good configuration instead.
+type FallbackState struct {
+	Since  time.Time
+	Reason string
+}
+
+// configSnapshot is a copy of the configuration files, maps and certificates
+// of the last configuration that haproxy successfully loaded.
+type configSnapshot struct {
+	dir   string
+	dirs  []string
+	taken bool
+}
+
+func newConfigSnapshot(dir string, dirs []string) *configSnapshot {
+	return &configSnapshot{
+		dir:  dir,
+		dirs: dirs,
+	}
+}
+
+// save updates the snapshot with the content of the current configuration.
+// Only missing and changed files are copied.
+func (s *configSnapshot) save() error {
+	for _, dir := range s.dirs {
+		if err := s.syncFiles(dir, s.snapshotDir(dir)); err != nil {
+			return err
+		}
+	}
+	s.taken = true
+	return nil
+}
+
+// restore overwrites the current configuration with the content of the
+// snapshot. Files that do not exist in the snapshot are removed as well,
+// e.g. a backend shard or a map file added by the rejected configuration.
+func (s *configSnapshot) restore() error {
+	if !s.taken {
+		return fmt.Errorf("there is no last known good configuration to restore")
+	}
+	for _, dir := range s.dirs {
+		if err := s.syncFiles(s.snapshotDir(dir), dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveRejected copies a rejected configuration file to the snapshot
+// directory, so it can be inspected after the rollback.
+func (s *configSnapshot) saveRejected(file string) (string, error) {
+	rejected := filepath.Join(s.dir, filepath.Base(file)+".rejected")
+	if err := copyFile(file, rejected); err != nil {
+		return "", err
+	}
+	return rejected, nil
+}
+
+func (s *configSnapshot) snapshotDir(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	return filepath.Join(s.dir, abs)
+}
+
+// syncFiles makes dst a copy of src, copying the files that are missing
+// or differ, and removing the ones that do not exist in src.
+func (s *configSnapshot) syncFiles(src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	srcFiles := map[string]bool{}
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path == s.dir {
+			return filepath.SkipDir
+		}
+		if isOldConfigFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		srcFiles[rel] = true
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return syncFile(path, filepath.Join(dst, rel))
+	})
+	if err != nil {
+		return err
+	}
+	var remove []string
+	err = filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path == s.dir {
+			return filepath.SkipDir
+		}
+		if isOldConfigFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if !srcFiles[rel] {
+			remove = append(remove, path)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, path := range remove {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isOldConfigFile reports whether name is a timestamped copy of a former
+// haproxy.cfg, kept due to max-old-config-files. haproxy does not read them.
+func isOldConfigFile(name string) bool {
+	return strings.HasPrefix(name, "haproxy.cfg.")
+}
+
+func syncFile(src, dst string) error {
+	srcData, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if dstData, err := os.ReadFile(dst); err == nil && bytes.Equal(srcData, dstData) {
+		return nil
+	}
+	return writeFile(src, dst, srcData)
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return writeFile(src, dst, data)
+}
+
+func writeFile(src, dst string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(src); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, mode)
+}
+
+// rejectedBackend is a backend whose configuration was pointed by haproxy
+// as the cause of a rejected configuration.
+type rejectedBackend struct {
+	id      string
+	message string
+}
+
+var checkOutputRegex = regexp.MustCompile(`\[([^\]\s]+\.cfg):([0-9]+)\]`)
+
+// parseRejectedBackends reads the output of a failed configuration check,
+// and finds the backends where the errors were found. Errors found outside
+// of a backend section cannot be assigned to a resource and are ignored.
+func parseRejectedBackends(output string) []rejectedBackend {
+	var backends []rejectedBackend
+	found := map[string]bool{}
+	files := map[string][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		match := checkOutputRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		file := match[1]
+		lineNumber, _ := strconv.Atoi(match[2])
+		lines, read := files[file]
+		if !read {
+			lines = readLines(file)
+			files[file] = lines
+		}
+		id := findBackendSection(lines, lineNumber)
+		if id == "" || found[id] {
+			continue
+		}
+		found[id] = true
+		message := strings.TrimSpace(line)
+		if pos := strings.Index(message, match[0]); pos >= 0 {
+			message = strings.TrimLeft(message[pos+len(match[0]):], " :")
+		}
+		backends = append(backends, rejectedBackend{id: id, message: message})
+	}
+	return backends
+}
+
+func readLines(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// findBackendSection returns the id of the backend section that declares
+// the 1-based lineNumber, or an empty string if it is declared elsewhere.
+func findBackendSection(lines []string, lineNumber int) string {
+	if lineNumber > len(lines) {
+		return ""
+	}
+	for n := lineNumber - 1; n >= 0; n-- {
+		line := lines[n]
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[0] == "backend" {
+			return fields[1]
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	hatypes "github.com/jcmoraisjr/haproxy-ingress/pkg/haproxy/types"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	tempdir := t.TempDir()
+	cfgDir := filepath.Join(tempdir, "etc")
+	crtDir := filepath.Join(tempdir, "crt")
+	snapshot := newConfigSnapshot(filepath.Join(tempdir, "rollback"), []string{cfgDir, crtDir})
+
+	writeFiles := func(files map[string]string) {
+		for name, content := range files {
+			file := filepath.Join(tempdir, name)
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatalf("error creating dir: %v", err)
+			}
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatalf("error writing file: %v", err)
+			}
+		}
+	}
+	readFiles := func() map[string]string {
+		files := map[string]string{}
+		for _, dir := range []string{cfgDir, crtDir} {
+			_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					rel, _ := filepath.Rel(tempdir, path)
+					data, _ := os.ReadFile(path)
+					files[rel] = string(data)
+				}
+				return nil
+			})
+		}
+		return files
+	}
+
+	if err := snapshot.restore(); err == nil {
+		t.Errorf("expected error restoring an empty snapshot")
+	}
+
+	good := map[string]string{
+		"etc/haproxy.cfg":          "good",
+		"etc/maps/_front_host.map": "good map",
+		"crt/default_crt.pem":      "good crt",
+	}
+	writeFiles(good)
+	if err := snapshot.save(); err != nil {
+		t.Fatalf("error saving snapshot: %v", err)
+	}
+
+	writeFiles(map[string]string{
+		"etc/haproxy.cfg":                   "bad",
+		"etc/haproxy.cfg.20260101-000000.0": "rotated",
+		"etc/haproxy5-backend001.cfg":       "bad shard",
+		"etc/maps/_front_host.map":          "bad map",
+		"crt/default_crt.pem":               "bad crt",
+		"crt/default_crt2.pem":              "new crt",
+	})
+	rejected, err := snapshot.saveRejected(filepath.Join(cfgDir, "haproxy.cfg"))
+	if err != nil {
+		t.Fatalf("error saving rejected config: %v", err)
+	}
+	if data, _ := os.ReadFile(rejected); string(data) != "bad" {
+		t.Errorf("unexpected rejected config: %s", data)
+	}
+	if err := snapshot.restore(); err != nil {
+		t.Fatalf("error restoring snapshot: %v", err)
+	}
+
+	expected := map[string]string{
+		"etc/haproxy.cfg":                   "good",
+		"etc/haproxy.cfg.20260101-000000.0": "rotated",
+		"etc/maps/_front_host.map":          "good map",
+		"crt/default_crt.pem":               "good crt",
+	}
+	if actual := readFiles(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("restored files differ\nexpected: %v\n  actual: %v", expected, actual)
+	}
+}
+
+func TestRejectedBackends(t *testing.T) {
+	cfg := `global
+    daemon
+
+backend default_app_8080
+    mode http
+    server srv001 172.17.0.2:8080 weight 1 foo
+
+backend default_web_80
+    mode http
+    server srv001 172.17.0.3:80 weight 1 bar
+
+frontend _front_http
+    bind :80 baz
+`
+	tempdir := t.TempDir()
+	cfgFile := filepath.Join(tempdir, "haproxy.cfg")
+	if err := os.WriteFile(cfgFile, []byte(cfg), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	output := strings.ReplaceAll(`[NOTICE]   (1) : haproxy version is 2.8.5
+[ALERT]    (1) : config : parsing [CFG:6] : 'server srv001' unknown keyword 'foo'.
+[ALERT]    (1) : config : parsing [CFG:10] : 'server srv001' unknown keyword 'bar'.
+[ALERT]    (1) : config : parsing [CFG:13] : 'bind :80' unknown keyword 'baz'.
+[ALERT]    (1) : config : Error(s) found in configuration file : CFG
+`, "CFG", cfgFile)
+
+	backends := parseRejectedBackends(output)
+	expected := []rejectedBackend{
+		{id: "default_app_8080", message: "'server srv001' unknown keyword 'foo'."},
+		{id: "default_web_80", message: "'server srv001' unknown keyword 'bar'."},
+	}
+	if !reflect.DeepEqual(backends, expected) {
+		t.Errorf("rejected backends differ\nexpected: %+v\n  actual: %+v", expected, backends)
+	}
+
+	c := setup(t)
+	defer c.teardown()
+	b := c.config.Backends().AcquireBackend("default", "app", "8080")
+	b.ConfigSources = []*hatypes.ConfigSource{
+		{Key: "config-backend", Source: "Ingress 'default/app1'"},
+		{Key: "timeout-server", Source: "Service 'default/app'"},
+	}
+	c.config.Hosts().AcquireHost("domain1.local").AddPath(b, "/", hatypes.MatchBegin).Source = "Ingress 'default/app2'"
+	c.config.Hosts().AcquireHost("domain2.local").AddPath(b, "/", hatypes.MatchBegin).Source = "Ingress 'default/app1'"
+	var sources []string
+	for _, src := range c.instance.backendSources(b.ID) {
+		ref := src.ObjectReference()
+		sources = append(sources, ref.Kind+":"+ref.Namespace+"/"+ref.Name)
+	}
+	sort.Strings(sources)
+	expectedSources := []string{"Ingress:default/app1", "Ingress:default/app2", "Service:default/app"}
+	if !reflect.DeepEqual(sources, expectedSources) {
+		t.Errorf("backend sources differ\nexpected: %v\n  actual: %v", expectedSources, sources)
+	}
+}
+
+func TestInstanceRollback(t *testing.T) {
+	c := setup(t)
+	defer c.teardown()
+	rollbackDir := t.TempDir()
+	c.instance.snapshot = newConfigSnapshot(rollbackDir, []string{c.tempdir})
+	cfgFile := filepath.Join(c.tempdir, "haproxy.cfg")
+
+	// rejects the configuration if the server of the d2 backend is found,
+	// reporting the line the same way haproxy does
+	var checkErr error
+	defer func() { checkConfig = (*instance).check }()
+	checkConfig = func(i *instance) error {
+		for n, line := range strings.Split(c.readRawConfig(cfgFile), "\n") {
+			if strings.HasPrefix(line, "    server s21 ") {
+				checkErr = fmt.Errorf("[ALERT]    (1) : config : parsing [%s:%d] : 'server s21' unknown keyword 'foo'.\n", cfgFile, n+1)
+				return checkErr
+			}
+		}
+		checkErr = nil
+		return nil
+	}
+
+	b := c.config.Backends().AcquireBackend("d1", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS1}
+	c.config.Hosts().AcquireHost("d1.local").AddPath(b, "/", hatypes.MatchBegin)
+	c.Update()
+	c.logger.CompareLogging(defaultLogging)
+	good := c.readRawConfig(cfgFile)
+	if state := c.instance.FallbackState(); state != nil {
+		t.Errorf("expected no fallback state after a successful reload, found: %+v", state)
+	}
+
+	b = c.config.Backends().AcquireBackend("d2", "app", "8080")
+	b.Endpoints = []*hatypes.Endpoint{endpointS21}
+	c.config.Hosts().AcquireHost("d2.local").AddPath(b, "/", hatypes.MatchBegin).Source = "Ingress 'd2/app'"
+	if err := c.instance.WriteConfig(); err != nil {
+		t.Fatalf("error writing configuration: %v", err)
+	}
+	rejected := c.readRawConfig(cfgFile)
+	c.instance.Reload(utils.NewTimer(nil))
+	if checkErr == nil {
+		t.Fatalf("expected the configuration to be rejected")
+	}
+	c.logger.CompareLogging(`
+ERROR error validating config file:
+` + checkErr.Error() + `
+WARN configuration of backend 'd2_app_8080' rejected by haproxy, declared by Ingress 'd2/app': 'server s21' unknown keyword 'foo'.
+INFO rejected configuration saved to ` + filepath.Join(rollbackDir, "haproxy.cfg.rejected") + `
+WARN last known good configuration restored, haproxy will be reloaded as soon as a valid configuration is built`)
+	if actual := c.readRawConfig(cfgFile); actual != good {
+		t.Errorf("expected the last known good configuration restored\nexpected:\n%s\n  actual:\n%s", good, actual)
+	}
+	if actual := c.readRawConfig(filepath.Join(rollbackDir, "haproxy.cfg.rejected")); actual != rejected {
+		t.Errorf("rejected configuration differs\nexpected:\n%s\n  actual:\n%s", rejected, actual)
+	}
+	state := c.instance.FallbackState()
+	if state == nil {
+		t.Fatalf("expected fallback state after a rejected configuration")
+	}
+	if expected := strings.TrimSpace(checkErr.Error()); state.Reason != expected {
+		t.Errorf("fallback reason differs - expected: %s - actual: %s", expected, state.Reason)
+	}
+
+	b.Endpoints = []*hatypes.Endpoint{endpointS22}
+	if err := c.instance.WriteConfig(); err != nil {
+		t.Fatalf("error writing configuration: %v", err)
+	}
+	fixed := c.readRawConfig(cfgFile)
+	c.instance.fallback.Since = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.instance.Reload(utils.NewTimer(nil))
+	c.logger.CompareLogging(`
+INFO (test) reload was skipped
+INFO haproxy is running the current configuration, leaving the fallback state started at 2026-01-01 00:00:00 +0000 UTC
+INFO haproxy successfully reloaded (embedded daemon)`)
+	if state := c.instance.FallbackState(); state != nil {
+		t.Errorf("expected no fallback state after a successful reload, found: %+v", state)
+	}
+	snapshotFile := filepath.Join(c.instance.snapshot.snapshotDir(c.tempdir), "haproxy.cfg")
+	if actual := c.readRawConfig(snapshotFile); actual != fixed {
+		t.Errorf("expected the snapshot updated with the reloaded configuration\nexpected:\n%s\n  actual:\n%s", fixed, actual)
+	}
+}
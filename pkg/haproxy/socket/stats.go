@@ -0,0 +1,337 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package socket
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/utils"
+)
+
+// Info is the response of the `show info` command.
+type Info struct {
+	Name      string
+	Version   string
+	PID       int
+	Uptime    int
+	CurrConns int
+	MaxConn   int
+	IdlePct   int
+	// Fields has all the fields of the response, indexed by name
+	Fields map[string]string
+}
+
+// StatType is the type of a `show stat` item.
+type StatType int
+
+// Types of a `show stat` item, as described by the `type` field.
+const (
+	StatFrontend StatType = 0
+	StatBackend  StatType = 1
+	StatServer   StatType = 2
+	StatListener StatType = 3
+)
+
+// Stat is an item of the `show stat` command: a frontend, a backend,
+// a server or a listener.
+type Stat struct {
+	Proxy       string
+	Name        string
+	Type        StatType
+	Status      string
+	Addr        string
+	Weight      int
+	QueueCur    int
+	QueueMax    int
+	SessCur     int
+	SessMax     int
+	SessLimit   int
+	SessTotal   int64
+	BytesIn     int64
+	BytesOut    int64
+	ReqTotal    int64
+	Rsp4xx      int64
+	Rsp5xx      int64
+	CheckStatus string
+	LastChange  int
+	// Fields has all the fields of the item, indexed by the name of the CSV header
+	Fields map[string]string
+}
+
+// Operational states of a server, as described by the srv_op_state field
+// of `show servers state`.
+const (
+	SrvOpStopped  = 0
+	SrvOpStarting = 1
+	SrvOpRunning  = 2
+	SrvOpStopping = 3
+)
+
+// srvAdmMaint has the srv_admin_state flags of a server in maintenance mode,
+// these servers are empty slots or were disabled.
+const srvAdmMaint = 0x01 | 0x02 | 0x04 | 0x20 | 0x40
+
+// ServerState is an item of the `show servers state` command.
+type ServerState struct {
+	BackendID   int
+	Backend     string
+	ID          int
+	Name        string
+	Addr        string
+	Port        int
+	OpState     int
+	AdminState  int
+	UserWeight  int
+	InitWeight  int
+	LastChange  int
+	CheckStatus int
+	CheckResult int
+	CheckHealth int
+	CheckState  int
+	// Fields has all the fields of the item, indexed by the name of the header
+	Fields map[string]string
+}
+
+// IsMaint reports whether the server is in maintenance mode, either
+// disabled or used as an empty slot for dynamic updates.
+func (s *ServerState) IsMaint() bool {
+	return s.AdminState&srvAdmMaint != 0
+}
+
+// IsDown reports whether the server is stopped and is not in maintenance
+// mode, either due to failing health checks or to errors on live traffic.
+func (s *ServerState) IsDown() bool {
+	return s.OpState == SrvOpStopped && !s.IsMaint()
+}
+
+// Session is an item of the `show sess` command.
+type Session struct {
+	ID       string
+	Proto    string
+	Source   string
+	Frontend string
+	Backend  string
+	Server   string
+	Age      string
+	// Fields has all the `name=value` fields of the session, indexed by name
+	Fields map[string]string
+}
+
+// HAProxyInfo sends `show info` to an admin socket and parses its response.
+func HAProxyInfo(sock HAProxySocket, observer func(duration time.Duration)) (*Info, error) {
+	out, err := sock.Send(observer, "show info")
+	if err != nil {
+		return nil, err
+	}
+	return ParseInfo(out[0]), nil
+}
+
+// HAProxyStat sends `show stat` to an admin socket and parses its response.
+func HAProxyStat(sock HAProxySocket) ([]*Stat, error) {
+	out, err := sock.Send(nil, "show stat")
+	if err != nil {
+		return nil, err
+	}
+	return ParseStat(out[0]), nil
+}
+
+// HAProxyServersState sends `show servers state` to an admin socket and parses its response.
+func HAProxyServersState(sock HAProxySocket) ([]*ServerState, error) {
+	out, err := sock.Send(nil, "show servers state")
+	if err != nil {
+		return nil, err
+	}
+	return ParseServersState(out[0]), nil
+}
+
+// HAProxySessions sends `show sess` to an admin socket and parses its response.
+func HAProxySessions(sock HAProxySocket) ([]*Session, error) {
+	out, err := sock.Send(nil, "show sess")
+	if err != nil {
+		return nil, err
+	}
+	return ParseSessions(out[0]), nil
+}
+
+// ParseInfo parses the `name: value` lines of `show info`
+//
+//	Name: HAProxy
+//	Version: 2.8.5-aaba8d0
+//	Pid: 12
+//	Uptime_sec: 1337
+//	...
+func ParseInfo(output string) *Info {
+	fields := map[string]string{}
+	for _, line := range utils.LineToSlice(output) {
+		name, value, found := strings.Cut(line, ":")
+		if found {
+			fields[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return &Info{
+		Name:      fields["Name"],
+		Version:   fields["Version"],
+		PID:       atoi(fields["Pid"]),
+		Uptime:    atoi(fields["Uptime_sec"]),
+		CurrConns: atoi(fields["CurrConns"]),
+		MaxConn:   atoi(fields["Maxconn"]),
+		IdlePct:   atoi(fields["Idle_pct"]),
+		Fields:    fields,
+	}
+}
+
+// ParseStat parses the CSV output of `show stat`. The first line is the
+// header, prefixed by `# `, which names the fields of the following lines.
+//
+//	# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,...
+//	_front_http,FRONTEND,,,1,3,2000,12,2431,5121,...
+//	d1_app_8080,srv001,0,0,0,1,,4,1512,3240,...
+func ParseStat(output string) []*Stat {
+	var stats []*Stat
+	var headers []string
+	for _, line := range utils.LineToSlice(output) {
+		if strings.HasPrefix(line, "# ") {
+			headers = strings.Split(strings.TrimPrefix(line, "# "), ",")
+			continue
+		}
+		if line == "" || headers == nil {
+			continue
+		}
+		fields := map[string]string{}
+		for i, value := range strings.Split(line, ",") {
+			if i < len(headers) && headers[i] != "" {
+				fields[headers[i]] = value
+			}
+		}
+		stats = append(stats, &Stat{
+			Proxy:       fields["pxname"],
+			Name:        fields["svname"],
+			Type:        StatType(atoi(fields["type"])),
+			Status:      fields["status"],
+			Addr:        fields["addr"],
+			Weight:      atoi(fields["weight"]),
+			QueueCur:    atoi(fields["qcur"]),
+			QueueMax:    atoi(fields["qmax"]),
+			SessCur:     atoi(fields["scur"]),
+			SessMax:     atoi(fields["smax"]),
+			SessLimit:   atoi(fields["slim"]),
+			SessTotal:   atoi64(fields["stot"]),
+			BytesIn:     atoi64(fields["bin"]),
+			BytesOut:    atoi64(fields["bout"]),
+			ReqTotal:    atoi64(fields["req_tot"]),
+			Rsp4xx:      atoi64(fields["hrsp_4xx"]),
+			Rsp5xx:      atoi64(fields["hrsp_5xx"]),
+			CheckStatus: fields["check_status"],
+			LastChange:  atoi(fields["lastchg"]),
+			Fields:      fields,
+		})
+	}
+	return stats
+}
+
+// ParseServersState parses the output of `show servers state`. The first
+// line is the version of the format, the second one is the header, prefixed
+// by `# `, which names the space separated fields of the following lines.
+//
+//	1
+//	# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state ...
+//	3 d1_app_8080 1 srv001 172.17.0.11 2 0 ...
+func ParseServersState(output string) []*ServerState {
+	var states []*ServerState
+	var headers []string
+	for _, line := range utils.LineToSlice(output) {
+		if strings.HasPrefix(line, "# ") {
+			headers = strings.Fields(strings.TrimPrefix(line, "# "))
+			continue
+		}
+		values := strings.Fields(line)
+		if headers == nil || len(values) < 7 {
+			// the version line, or an incomplete item
+			continue
+		}
+		fields := map[string]string{}
+		for i, value := range values {
+			if i < len(headers) {
+				fields[headers[i]] = value
+			}
+		}
+		states = append(states, &ServerState{
+			BackendID:   atoi(fields["be_id"]),
+			Backend:     fields["be_name"],
+			ID:          atoi(fields["srv_id"]),
+			Name:        fields["srv_name"],
+			Addr:        fields["srv_addr"],
+			Port:        atoi(fields["srv_port"]),
+			OpState:     atoi(fields["srv_op_state"]),
+			AdminState:  atoi(fields["srv_admin_state"]),
+			UserWeight:  atoi(fields["srv_uweight"]),
+			InitWeight:  atoi(fields["srv_iweight"]),
+			LastChange:  atoi(fields["srv_time_since_last_change"]),
+			CheckStatus: atoi(fields["srv_check_status"]),
+			CheckResult: atoi(fields["srv_check_result"]),
+			CheckHealth: atoi(fields["srv_check_health"]),
+			CheckState:  atoi(fields["srv_check_state"]),
+			Fields:      fields,
+		})
+	}
+	return states
+}
+
+// ParseSessions parses the output of `show sess`, one session per line.
+// Only the top level `name=value` fields are read, the fields of the
+// stream interfaces and buffers, e.g. `rq[f=...]`, are ignored.
+//
+//	0x55c0a4d2b200: proto=tcpv4 src=172.17.0.1:52184 fe=_front_http be=d1_app_8080 srv=srv001 ts=00 age=2s calls=3 ...
+func ParseSessions(output string) []*Session {
+	var sessions []*Session
+	for _, line := range utils.LineToSlice(output) {
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 || !strings.HasSuffix(tokens[0], ":") {
+			continue
+		}
+		fields := map[string]string{}
+		for _, token := range tokens[1:] {
+			name, value, found := strings.Cut(token, "=")
+			if found && !strings.ContainsAny(name, "[]") {
+				fields[name] = value
+			}
+		}
+		sessions = append(sessions, &Session{
+			ID:       strings.TrimSuffix(tokens[0], ":"),
+			Proto:    fields["proto"],
+			Source:   fields["src"],
+			Frontend: fields["fe"],
+			Backend:  fields["be"],
+			Server:   fields["srv"],
+			Age:      fields["age"],
+			Fields:   fields,
+		})
+	}
+	return sessions
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+func atoi64(s string) int64 {
+	i, _ := strconv.ParseInt(s, 10, 64)
+	return i
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package socket
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestHAProxyInfo(t *testing.T) {
+	testCases := []struct {
+		cmdOutput []string
+		cmdError  error
+		expOutput *Info
+		expError  bool
+	}{
+		// 0
+		{
+			cmdError: fmt.Errorf("fail"),
+			expError: true,
+		},
+		// 1
+		{
+			cmdOutput: []string{`Name: HAProxy
+Version: 2.8.5-aaba8d0
+Release_date: 2023/12/07
+Pid: 12
+Uptime_sec: 1337
+Maxconn: 2000
+CurrConns: 15
+Idle_pct: 97
+node: haproxy-ingress-7d4b9c
+`},
+			expOutput: &Info{
+				Name:      "HAProxy",
+				Version:   "2.8.5-aaba8d0",
+				PID:       12,
+				Uptime:    1337,
+				MaxConn:   2000,
+				CurrConns: 15,
+				IdlePct:   97,
+				Fields: map[string]string{
+					"Name":         "HAProxy",
+					"Version":      "2.8.5-aaba8d0",
+					"Release_date": "2023/12/07",
+					"Pid":          "12",
+					"Uptime_sec":   "1337",
+					"Maxconn":      "2000",
+					"CurrConns":    "15",
+					"Idle_pct":     "97",
+					"node":         "haproxy-ingress-7d4b9c",
+				},
+			},
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		cli := &clientMock{
+			cmdOutput: test.cmdOutput,
+			cmdError:  test.cmdError,
+		}
+		out, err := HAProxyInfo(cli, nil)
+		if !reflect.DeepEqual(out, test.expOutput) {
+			t.Errorf("output differs on %d - expected: %+v, actual: %+v", i, test.expOutput, out)
+		}
+		if (err != nil) != test.expError {
+			t.Errorf("error differs on %d - expected: %v, actual: %v", i, test.expError, err)
+		}
+		c.tearDown()
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	testCases := []struct {
+		output   string
+		expected []*Stat
+	}{
+		// 0
+		{
+			output:   "",
+			expected: nil,
+		},
+		// 1
+		{
+			output: `# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,dreq,dresp,ereq,econ,eresp,wretr,wredis,status,weight,act,bck,chkfail,chkdown,lastchg,downtime,qlimit,pid,iid,sid,throttle,lbtot,tracked,type,rate,rate_lim,rate_max,check_status,check_code,check_duration,hrsp_1xx,hrsp_2xx,hrsp_3xx,hrsp_4xx,hrsp_5xx,hrsp_other,hanafail,req_rate,req_rate_max,req_tot,cli_abrt,srv_abrt,addr
+_front_http,FRONTEND,,,1,3,2000,12,2431,5121,0,0,1,,,,,OPEN,,,,,,,,,1,2,0,,,,0,1,0,2,,,,0,10,0,2,0,0,,1,2,12,,,
+d1_app_8080,srv001,0,0,0,1,,4,1512,3240,,0,,0,0,0,0,UP,1,1,0,0,0,93,0,,1,3,1,,4,,2,0,,1,L4OK,,0,0,4,0,0,0,0,,,,,0,0,172.17.0.11:8080
+d1_app_8080,srv002,2,5,0,1,,8,1000,2000,,0,,3,0,0,0,DOWN,1,1,0,3,1,12,12,,1,3,2,,8,,2,0,,1,L4CON,,1,0,6,0,0,2,0,,,,,0,0,172.17.0.12:8080
+d1_app_8080,BACKEND,2,5,0,1,200,12,2512,5240,0,0,,3,0,0,0,UP,1,1,0,,0,93,0,,1,3,0,,12,,1,0,,2,,,,0,10,0,0,2,0,,,,12,0,0,
+`,
+			expected: []*Stat{
+				{Proxy: "_front_http", Name: "FRONTEND", Type: StatFrontend, Status: "OPEN", SessCur: 1, SessMax: 3, SessLimit: 2000, SessTotal: 12, BytesIn: 2431, BytesOut: 5121, ReqTotal: 12, Rsp4xx: 2},
+				{Proxy: "d1_app_8080", Name: "srv001", Type: StatServer, Status: "UP", Addr: "172.17.0.11:8080", Weight: 1, SessMax: 1, SessTotal: 4, BytesIn: 1512, BytesOut: 3240, CheckStatus: "L4OK", LastChange: 93},
+				{Proxy: "d1_app_8080", Name: "srv002", Type: StatServer, Status: "DOWN", Addr: "172.17.0.12:8080", Weight: 1, QueueCur: 2, QueueMax: 5, SessMax: 1, SessTotal: 8, BytesIn: 1000, BytesOut: 2000, Rsp5xx: 2, CheckStatus: "L4CON", LastChange: 12},
+				{Proxy: "d1_app_8080", Name: "BACKEND", Type: StatBackend, Status: "UP", Weight: 1, QueueCur: 2, QueueMax: 5, SessMax: 1, SessLimit: 200, SessTotal: 12, BytesIn: 2512, BytesOut: 5240, ReqTotal: 12, Rsp5xx: 2, LastChange: 93},
+			},
+		},
+	}
+	for i, test := range testCases {
+		c := setup(t)
+		actual := ParseStat(test.output)
+		for _, stat := range actual {
+			// Fields are compared by the typed ones
+			stat.Fields = nil
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("output differs on %d", i)
+			for j := range actual {
+				t.Logf("  actual %d: %+v", j, *actual[j])
+			}
+		}
+		c.tearDown()
+	}
+}
+
+func TestParseServersState(t *testing.T) {
+	output := `1
+# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state srv_uweight srv_iweight srv_time_since_last_change srv_check_status srv_check_result srv_check_health srv_check_state srv_agent_state bk_f_forced_id srv_f_forced_id srv_fqdn srv_port srvrecord srv_use_ssl srv_check_port srv_check_addr srv_agent_addr srv_agent_port
+3 d1_app_8080 1 srv001 172.17.0.11 2 0 1 1 10 6 3 4 6 0 0 0 - 8080 - 0 0 - - 0
+3 d1_app_8080 2 srv002 172.17.0.12 0 0 1 1 10 8 2 0 6 0 0 0 - 8080 - 0 0 - - 0
+3 d1_app_8080 3 srv003 127.0.0.1 0 5 1 1 10 1 0 0 14 0 0 0 - 1023 - 0 0 - - 0
+`
+	states := ParseServersState(output)
+	type server struct {
+		backend string
+		name    string
+		addr    string
+		port    int
+		down    bool
+		maint   bool
+	}
+	var actual []server
+	for _, state := range states {
+		actual = append(actual, server{
+			backend: state.Backend,
+			name:    state.Name,
+			addr:    state.Addr,
+			port:    state.Port,
+			down:    state.IsDown(),
+			maint:   state.IsMaint(),
+		})
+	}
+	expected := []server{
+		{backend: "d1_app_8080", name: "srv001", addr: "172.17.0.11", port: 8080},
+		{backend: "d1_app_8080", name: "srv002", addr: "172.17.0.12", port: 8080, down: true},
+		{backend: "d1_app_8080", name: "srv003", addr: "127.0.0.1", port: 1023, maint: true},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("servers state differ - expected: %+v - actual: %+v", expected, actual)
+	}
+	if states[0].BackendID != 3 || states[0].ID != 1 || states[0].OpState != SrvOpRunning || states[0].LastChange != 10 || states[0].CheckHealth != 4 {
+		t.Errorf("unexpected fields of the first server: %+v", *states[0])
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	output := `0x55c0a4d2b200: proto=tcpv4 src=172.17.0.1:52184 fe=_front_http be=d1_app_8080 srv=srv001 ts=00 epoch=0 age=2s calls=3 rate=0 cpu=0 lat=0 rq[f=848000h,i=0,an=00h,rx=,wx=,ax=] rp[f=80048202h,i=0,an=00h,rx=,wx=,ax=] scf=[8,1h,fd=31,rex=,wex=] exp=
+0x55c0a4d2c400: proto=unix_stream src=unix:1 fe=GLOBAL be=<NONE> srv=<none> ts=00 epoch=0x1 age=0s calls=1 rate=1 cpu=0 lat=0 rq[f=c4c220h,i=0,an=00h,rx=,wx=,ax=] exp=
+`
+	expected := []*Session{
+		{ID: "0x55c0a4d2b200", Proto: "tcpv4", Source: "172.17.0.1:52184", Frontend: "_front_http", Backend: "d1_app_8080", Server: "srv001", Age: "2s"},
+		{ID: "0x55c0a4d2c400", Proto: "unix_stream", Source: "unix:1", Frontend: "GLOBAL", Backend: "<NONE>", Server: "<none>", Age: "0s"},
+	}
+	actual := ParseSessions(output)
+	if len(actual) > 0 && actual[0].Fields["calls"] != "3" {
+		t.Errorf("unexpected fields of the first session: %v", actual[0].Fields)
+	}
+	for _, sess := range actual {
+		if _, found := sess.Fields["rq[f"]; found {
+			t.Errorf("unexpected buffer field on session %s", sess.ID)
+		}
+		sess.Fields = nil
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("sessions differ - expected: %+v - actual: %+v", expected, actual)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package haproxy
+
+import (
+	"regexp"
+	"sort"
+
+	api "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+)
+
+// BackendSource is a resource that declared the configuration of a backend.
+// It implements the ObjectReferrer interface of the converters, so loggers
+// can identify the Kubernetes object it refers to.
+type BackendSource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	source    string
+}
+
+var sourceRegex = regexp.MustCompile(`^([A-Za-z]+) '([^/']+)/([^']+)'$`)
+
+func parseSource(source string) *BackendSource {
+	match := sourceRegex.FindStringSubmatch(source)
+	if match == nil {
+		return nil
+	}
+	return &BackendSource{
+		Kind:      match[1],
+		Namespace: match[2],
+		Name:      match[3],
+		source:    source,
+	}
+}
+
+// ObjectReference returns the reference of Ingress, Service and HTTPRoute
+// sources, or nil otherwise. The API version of an HTTPRoute is not known
+// by the model, and should be resolved by the caller.
+func (s *BackendSource) ObjectReference() *api.ObjectReference {
+	var apiVersion string
+	switch s.Kind {
+	case "Ingress":
+		apiVersion = networking.SchemeGroupVersion.String()
+	case "Service":
+		apiVersion = api.SchemeGroupVersion.String()
+	case "HTTPRoute":
+	default:
+		return nil
+	}
+	return &api.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       s.Kind,
+		Namespace:  s.Namespace,
+		Name:       s.Name,
+	}
+}
+
+// String ...
+func (s *BackendSource) String() string {
+	return s.source
+}
+
+// backendSources returns the resources that declared the configuration of
+// a backend: the ones that assigned configuration keys, and the ones that
+// added paths pointing to the backend.
+func (i *instance) backendSources(id string) []*BackendSource {
+	sourceMap := map[string]*BackendSource{}
+	addSource := func(source string) {
+		if _, found := sourceMap[source]; !found {
+			if src := parseSource(source); src != nil {
+				sourceMap[source] = src
+			}
+		}
+	}
+	if backend := i.config.Backends().Items()[id]; backend != nil {
+		for _, cfg := range backend.ConfigSources {
+			addSource(cfg.Source)
+		}
+	}
+	for _, host := range i.config.Hosts().Items() {
+		for _, path := range host.Paths {
+			if path.Backend.ID == id {
+				addSource(path.Source)
+			}
+		}
+	}
+	sources := make([]*BackendSource, 0, len(sourceMap))
+	for _, src := range sourceMap {
+		sources = append(sources, src)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].source < sources[j].source
+	})
+	return sources
+}
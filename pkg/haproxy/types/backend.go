@@ -45,6 +45,16 @@ func (b *Backend) FindEndpoint(target string) *Endpoint {
 	return nil
 }
 
+// FindEndpointByName ...
+func (b *Backend) FindEndpointByName(name string) *Endpoint {
+	for _, endpoint := range b.Endpoints {
+		if endpoint.Name == name {
+			return endpoint
+		}
+	}
+	return nil
+}
+
 // AcquireEndpoint ...
 func (b *Backend) AcquireEndpoint(ip string, port int, targetRef string) *Endpoint {
 	endpoint := b.FindEndpoint(fmt.Sprintf("%s:%d", ip, port))
@@ -66,6 +76,22 @@ func (b *Backend) AddEmptyEndpoint() *Endpoint {
 	return endpoint
 }
 
+// AddFallbackEndpoint adds an endpoint of the fallback service, used only
+// when all the endpoints of the backend are down.
+func (b *Backend) AddFallbackEndpoint(ip string, port int, targetRef string) *Endpoint {
+	endpoint := &Endpoint{
+		Name:      fmt.Sprintf("fallback%03d", len(b.Fallback)+1),
+		IP:        ip,
+		Port:      port,
+		Target:    fmt.Sprintf("%s:%d", ip, port),
+		Enabled:   true,
+		TargetRef: targetRef,
+		Weight:    b.Server.InitialWeight,
+	}
+	b.Fallback = append(b.Fallback, endpoint)
+	return endpoint
+}
+
 func (b *Backend) addEndpoint(ip string, port int, targetRef string) *Endpoint {
 	var name string
 	switch b.EpNaming {
@@ -191,6 +217,26 @@ func sortPaths(paths []*BackendPath, pathReverse bool) {
 	})
 }
 
+// HasCache ...
+func (b *Backend) HasCache() bool {
+	for _, path := range b.Paths {
+		if path.Cache.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCompression ...
+func (b *Backend) HasCompression() bool {
+	for _, path := range b.Paths {
+		if path.Compression.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
 // HasCorsEnabled ...
 func (b *Backend) HasCorsEnabled() bool {
 	for _, path := range b.Paths {
@@ -221,6 +267,26 @@ func (b *Backend) HasModsec() bool {
 	return false
 }
 
+// FindAPIKeyMap ...
+func (b *Backend) FindAPIKeyMap(name string) *APIKeyMap {
+	for _, apiKeys := range b.APIKeys {
+		if apiKeys.Name == name {
+			return apiKeys
+		}
+	}
+	return nil
+}
+
+// HasRateLimit ...
+func (b *Backend) HasRateLimit() bool {
+	for _, path := range b.Paths {
+		if path.RateLimit.Requests > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // HasSSLRedirect ...
 func (b *Backend) HasSSLRedirect() bool {
 	for _, path := range b.Paths {
@@ -173,6 +173,16 @@ func (b *Backends) ChangedShards() []int {
 	return changed
 }
 
+// HasCache ...
+func (b *Backends) HasCache() bool {
+	for _, backend := range b.items {
+		if backend.HasCache() {
+			return true
+		}
+	}
+	return false
+}
+
 // FillSourceIPs ...
 func (b *Backends) FillSourceIPs() {
 	for _, backend := range b.itemsAdd {
@@ -17,6 +17,7 @@ limitations under the License.
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
@@ -445,6 +446,11 @@ func (l *PathLink) Hostname() string {
 	return l.hostname
 }
 
+// Path ...
+func (l *PathLink) Path() string {
+	return l.path
+}
+
 // IsEmpty ...
 func (l *PathLink) IsEmpty() bool {
 	return l.hostname == "" && l.path == ""
@@ -476,6 +482,21 @@ func (l *PathLink) Key() string {
 	return buildMapKey(l.match, l.hostname, l.path)
 }
 
+// MarshalJSON ...
+func (l *PathLink) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Hostname string
+		Path     string
+		Match    MatchType
+		Headers  HTTPHeaderMatch `json:",omitempty"`
+	}{
+		Hostname: l.hostname,
+		Path:     l.path,
+		Match:    l.match,
+		Headers:  l.headers,
+	})
+}
+
 // String ...
 func (h *Host) String() string {
 	return fmt.Sprintf("%+v", *h)
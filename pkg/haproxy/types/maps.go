@@ -19,6 +19,7 @@ package types
 import (
 	"container/list"
 	"fmt"
+	"net/http"
 	"reflect"
 	"regexp"
 	"sort"
@@ -61,7 +62,18 @@ func (hm *HostsMap) addHostnameMappingMatch(hostname, target string, match Match
 			match = MatchRegex
 		}
 	}
-	hm.addTarget(hostname, "", nil, 0, target, match)
+	hm.addTarget(hostname, "", nil, target, match)
+}
+
+// AddHostnameMappingPath adds a hostname only mapping, like AddHostnameMapping,
+// keeping a reference to the path that originated the mapping.
+func (hm *HostsMap) AddHostnameMappingPath(hostname string, hostPath *HostPath, target string) {
+	hostname, hasWildcard := convertWildcardToRegex(hostname)
+	match := MatchExact
+	if hasWildcard {
+		match = MatchRegex
+	}
+	hm.addTarget(hostname, "", hostPath, target, match)
 }
 
 // AddHostnamePathMapping ...
@@ -78,7 +90,7 @@ func (hm *HostsMap) AddHostnamePathMapping(hostname string, hostPath *HostPath,
 	} else if match == MatchRegex {
 		hostname = "^" + regexp.QuoteMeta(hostname) + "$"
 	}
-	hm.addTarget(hostname, path, hostPath.Link.headers, hostPath.order, target, match)
+	hm.addTarget(hostname, path, hostPath, target, match)
 }
 
 // AddAliasPathMapping ...
@@ -88,7 +100,7 @@ func (hm *HostsMap) AddAliasPathMapping(alias HostAliasConfig, path *HostPath, t
 	}
 	if alias.AliasRegex != "" {
 		pathstr := convertPathToRegex(path)
-		hm.addTarget(alias.AliasRegex, pathstr, path.Link.headers, path.order, target, MatchRegex)
+		hm.addTarget(alias.AliasRegex, pathstr, path, target, MatchRegex)
 	}
 }
 
@@ -125,18 +137,26 @@ func convertPathToRegex(hostPath *HostPath) string {
 	panic("unsupported match type")
 }
 
-func (hm *HostsMap) addTarget(hostname, path string, headers []HTTPMatch, order int, target string, match MatchType) {
+func (hm *HostsMap) addTarget(hostname, path string, hostPath *HostPath, target string, match MatchType) {
 	hostname = strings.ToLower(hostname)
 	if match == MatchBegin {
 		// this is the only match that uses case insensitive path
 		path = strings.ToLower(path)
 	}
+	var headers HTTPHeaderMatch
+	var order int
+	// hostname only mappings do not filter by headers
+	if hostPath != nil && path != "" {
+		headers = hostPath.Link.headers
+		order = hostPath.order
+	}
 	entry := &HostsMapEntry{
 		hostname: hostname,
 		path:     path,
 		match:    match,
 		headers:  headers,
 		order:    order,
+		hostPath: hostPath,
 		Key:      buildMapKey(match, hostname, path),
 		Value:    target,
 	}
@@ -358,6 +378,95 @@ func (hm *HostsMap) HasHost() bool {
 	return false
 }
 
+// Match looks for the entry HAProxy would find when input is used in a
+// lookup of this map. Match files are evaluated in the same order they are
+// used in the configuration file, using their match method and header
+// filters. headers is used to evaluate the filters, and can be nil.
+func (hm *HostsMap) Match(input string, headers http.Header) (*MatchFile, *HostsMapEntry) {
+	for _, matchFile := range hm.MatchFiles() {
+		if !matchFile.matchFile.headers.match(headers) {
+			continue
+		}
+		value := input
+		if matchFile.Lower() {
+			value = strings.ToLower(value)
+		}
+		for _, entry := range matchFile.matchFile.entries {
+			if matchFile.matchFile.matchEntry(entry, value) {
+				return matchFile, entry
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (mf *hostsMapMatchFile) matchEntry(entry *HostsMapEntry, value string) bool {
+	switch mf.match {
+	case MatchExact:
+		return value == entry.Key
+	case MatchBegin:
+		return strings.HasPrefix(value, entry.Key)
+	case MatchPrefix:
+		return matchDir(value, entry.Key)
+	case MatchRegex:
+		re, err := regexp.Compile(entry.Key)
+		return err == nil && re.MatchString(value)
+	}
+	return false
+}
+
+// matchDir mimics HAProxy's dir match: the pattern, without leading and
+// trailing slashes, should be found in the value delimited by slashes or
+// by the start and the end of the value.
+func matchDir(value, pattern string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	for i := 0; i+len(pattern) <= len(value); i++ {
+		j := strings.Index(value[i:], pattern)
+		if j < 0 {
+			return false
+		}
+		i += j
+		end := i + len(pattern)
+		if (i == 0 || value[i-1] == '/') && (end == len(value) || value[end] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+func (h HTTPHeaderMatch) match(headers http.Header) bool {
+	for _, header := range h {
+		if !header.match(headers) {
+			return false
+		}
+	}
+	return true
+}
+
+// match mimics `hdr(<name>) -m str|reg <value>` acl, which
+// evaluates every comma separated value of the header.
+func (h HTTPMatch) match(headers http.Header) bool {
+	var re *regexp.Regexp
+	if h.Regex {
+		var err error
+		if re, err = regexp.Compile(h.Value); err != nil {
+			return false
+		}
+	}
+	for _, header := range headers.Values(h.Name) {
+		for _, value := range strings.Split(header, ",") {
+			value = strings.TrimSpace(value)
+			if (re != nil && re.MatchString(value)) || (re == nil && value == h.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (mf *hostsMapMatchFile) shrink() {
 	e := mf.entries
 	l := len(e)
@@ -468,6 +577,11 @@ func (m MatchFile) Values() []*HostsMapEntry {
 	return m.matchFile.entries
 }
 
+// HostPath ...
+func (he *HostsMapEntry) HostPath() *HostPath {
+	return he.hostPath
+}
+
 func (he *HostsMapEntry) hasFilter() bool {
 	return he.headers != nil
 }
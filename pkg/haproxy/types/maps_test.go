@@ -18,6 +18,7 @@ package types
 
 import (
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 	"testing"
@@ -697,3 +698,144 @@ local1.tld /a2 prefix
 		}
 	}
 }
+
+func TestMatch(t *testing.T) {
+	type data struct {
+		hostname string
+		path     string
+		match    MatchType
+		headers  HTTPHeaderMatch
+		target   string
+	}
+	testCases := []struct {
+		data     []data
+		input    string
+		headers  map[string]string
+		expected string
+	}{
+		// 0
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/", match: MatchPrefix, target: "default_app1_8080"},
+			},
+			input:    "local2.tld#/",
+			expected: "",
+		},
+		// 1
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/", match: MatchPrefix, target: "default_app1_8080"},
+			},
+			input:    "local1.tld#/app",
+			expected: "hosts__prefix.map dir local1.tld#/ default_app1_8080",
+		},
+		// 2
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/app", match: MatchPrefix, target: "default_app1_8080"},
+			},
+			input:    "local1.tld#/application",
+			expected: "",
+		},
+		// 3
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/app", match: MatchBegin, target: "default_app1_8080"},
+			},
+			input:    "local1.tld#/APPlication",
+			expected: "hosts__begin.map beg local1.tld#/app default_app1_8080",
+		},
+		// 4
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/", match: MatchBegin, target: "default_app1_8080"},
+				{hostname: "local1.tld", path: "/app", match: MatchExact, target: "default_app2_8080"},
+			},
+			input:    "local1.tld#/app",
+			expected: "hosts__exact.map str local1.tld#/app default_app2_8080",
+		},
+		// 5
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/", match: MatchBegin, target: "default_app1_8080"},
+				{hostname: "local1.tld", path: "/app", match: MatchExact, target: "default_app2_8080"},
+			},
+			input:    "local1.tld#/app/sub",
+			expected: "hosts__begin.map beg local1.tld#/ default_app1_8080",
+		},
+		// 6
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/a", match: MatchBegin, target: "default_app1_8080"},
+				{hostname: "local1.tld", path: "/ab", match: MatchPrefix, target: "default_app2_8080"},
+			},
+			input:    "local1.tld#/ab/c",
+			expected: "hosts__prefix_01.map dir local1.tld#/ab default_app2_8080",
+		},
+		// 7
+		{
+			data: []data{
+				{hostname: "*.local1.tld", path: "/", match: MatchPrefix, target: "default_app1_8080"},
+			},
+			input:    "sub.local1.tld#/app",
+			expected: `hosts__regex.map reg ^[^.]+\.local1\.tld#/ default_app1_8080`,
+		},
+		// 8
+		{
+			data: []data{
+				{hostname: "*.local1.tld", path: "/", match: MatchPrefix, target: "default_app1_8080"},
+			},
+			input:    "sub.sub.local1.tld#/app",
+			expected: "",
+		},
+		// 9
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/", match: MatchPrefix, target: "default_app1_8080"},
+				{hostname: "local1.tld", path: "/", match: MatchPrefix, headers: HTTPHeaderMatch{{Name: "x-user", Value: "admin"}}, target: "default_app2_8080"},
+			},
+			input:    "local1.tld#/",
+			headers:  map[string]string{"X-User": "guest, admin"},
+			expected: "hosts__prefix_01.map dir local1.tld#/ default_app2_8080",
+		},
+		// 10
+		{
+			data: []data{
+				{hostname: "local1.tld", path: "/", match: MatchPrefix, target: "default_app1_8080"},
+				{hostname: "local1.tld", path: "/", match: MatchPrefix, headers: HTTPHeaderMatch{{Name: "x-user", Value: "^adm", Regex: true}}, target: "default_app2_8080"},
+			},
+			input:    "local1.tld#/",
+			headers:  map[string]string{"X-User": "guest"},
+			expected: "hosts__prefix.map dir local1.tld#/ default_app1_8080",
+		},
+		// 11
+		{
+			data: []data{
+				{hostname: "local1.tld", target: "default_app1_8443"},
+			},
+			input:    "local1.tld",
+			expected: "hosts__exact.map str local1.tld default_app1_8443",
+		},
+	}
+	for i, test := range testCases {
+		hm := CreateMaps(matchOrder).AddMap("hosts.map")
+		for _, item := range test.data {
+			if item.path == "" {
+				hm.AddHostnameMapping(item.hostname, item.target)
+			} else {
+				hm.AddHostnamePathMapping(item.hostname, &HostPath{Link: CreatePathLink(item.path, item.match).WithHeadersMatch(item.headers)}, item.target)
+			}
+		}
+		headers := http.Header{}
+		for name, value := range test.headers {
+			headers.Set(name, value)
+		}
+		var output string
+		if matchFile, entry := hm.Match(test.input, headers); entry != nil {
+			output = fmt.Sprintf("%s %s %s %s", matchFile.Filename(), matchFile.Method(), entry.Key, entry.Value)
+		}
+		if output != test.expected {
+			t.Errorf("item %d: expected '%s' but was '%s'", i, test.expected, output)
+		}
+	}
+}
@@ -53,6 +53,7 @@ type Acme struct {
 // Global ...
 type Global struct {
 	Bind                    GlobalBindConfig
+	Cache                   CacheConfig
 	Procs                   ProcsConfig
 	Syslog                  SyslogConfig
 	MaxConn                 int
@@ -73,6 +74,7 @@ type Global struct {
 	Healthz                 HealthzConfig
 	Master                  MasterConfig
 	MatchOrder              []MatchType
+	Peers                   PeersConfig
 	Prometheus              PromConfig
 	Security                SecurityConfig
 	Stats                   StatsConfig
@@ -243,6 +245,28 @@ type StatsConfig struct {
 	TLSHash     string
 }
 
+// CacheConfig ...
+type CacheConfig struct {
+	Enabled       bool
+	MaxAge        int
+	MaxObjectSize int
+	PurgeMapFile  string
+	TotalMaxSize  int
+}
+
+// PeersConfig ...
+type PeersConfig struct {
+	LocalPeer string
+	Port      int
+	Servers   []PeerServer
+}
+
+// PeerServer ...
+type PeerServer struct {
+	Name string
+	IP   string
+}
+
 // ModSecurityTimeoutConfig ...
 type ModSecurityTimeoutConfig struct {
 	// Backend
@@ -364,6 +388,7 @@ type HostsMapEntry struct {
 	match    MatchType
 	headers  HTTPHeaderMatch
 	order    int
+	hostPath *HostPath
 	_upper   *list.Element
 	_elem    *list.Element
 	Key      string
@@ -480,6 +505,8 @@ type Host struct {
 	Alias                  HostAliasConfig
 	Redirect               HostRedirectConfig
 	HTTPPassthroughBackend string
+	OwnerNamespace         string
+	OwnerTimestamp         time.Time
 	RootRedirect           string
 	TLS                    HostTLSConfig
 	VarNamespace           bool
@@ -546,6 +573,7 @@ type HostPath struct {
 	AuthExt *AuthExternal
 	Backend HostBackend
 	RedirTo string
+	Source  string
 }
 
 // HostBackend ...
@@ -631,6 +659,7 @@ type Backend struct {
 	SourceIPs []net.IP
 	Endpoints []*Endpoint
 	EpNaming  EndpointNaming
+	Fallback  []*Endpoint
 	//
 	// Paths
 	//
@@ -643,8 +672,10 @@ type Backend struct {
 	//
 	AgentCheck       AgentCheck
 	AllowedIPTCP     AccessConfig
+	APIKeys          []*APIKeyMap
 	BalanceAlgorithm string
 	BlueGreen        BlueGreenConfig
+	Compression      BackendCompression
 	Cookie           Cookie
 	CustomConfig     []string
 	DeniedIPTCP      AccessConfig
@@ -655,9 +686,44 @@ type Backend struct {
 	Limit            BackendLimit
 	ModeTCP          bool
 	Resolver         string
+	Response         *BackendResponse
+	Retry            BackendRetry
 	Server           ServerConfig
 	Timeout          BackendTimeoutConfig
 	TLS              BackendTLSConfig
+	//
+	// debugging info
+	//
+	ConfigSources []*ConfigSource
+}
+
+// ConfigSource is a configuration key assigned to a backend, and the
+// resource and path that declared it.
+type ConfigSource struct {
+	Key    string
+	Value  string
+	Source string
+	Path   *PathLink
+}
+
+// APIKeyMap is a list of API keys and their labels, written as a map file
+// when the backend maps are written. Changes in the keys of an existing map
+// are applied via the runtime API.
+type APIKeyMap struct {
+	Name     string
+	Keys     map[string]string
+	Filename string
+}
+
+// BackendResponse is a static response sent by a backend without endpoints,
+// e.g. from an Ingress resource backend. Body is written to BodyFile when the
+// backend maps are written.
+type BackendResponse struct {
+	StatusCode  int
+	ContentType string
+	Headers     []HTTPHeader
+	Body        string
+	BodyFile    string
 }
 
 // Endpoint ...
@@ -710,11 +776,16 @@ type BackendPath struct {
 	//
 	AllowedIPHTTP AccessConfig
 	AuthHTTP      AuthHTTP
+	AuthAPIKey    AuthAPIKey
 	AuthExternal  AuthExternal
+	AuthJWT       AuthJWT
+	Cache         Cache
+	Compression   Compression
 	Cors          Cors
 	DeniedIPHTTP  AccessConfig
 	HSTS          HSTS
 	MaxBodySize   int64
+	RateLimit     RateLimit
 	RewriteURL    string
 	SSLRedirect   bool
 	WAF           WAF
@@ -743,12 +814,30 @@ type DynBackendConfig struct {
 
 // HealthCheck ...
 type HealthCheck struct {
-	Addr      string
-	FallCount int
-	Interval  string
-	Port      int
-	RiseCount int
-	URI       string
+	Addr         string
+	ErrorLimit   int
+	ExpectBody   string
+	ExpectStatus string
+	FallCount    int
+	Headers      []*BackendHeader
+	Host         string
+	Interval     string
+	Method       string
+	Observe      string
+	OnError      string
+	Port         int
+	Protocol     string
+	RiseCount    int
+	URI          string
+}
+
+// BackendCompression has the compression options shared by all the paths
+// of a backend, HAProxy doesn't support distinct options per request.
+type BackendCompression struct {
+	Algorithms []string
+	MinSize    int
+	Offload    bool
+	Types      []string
 }
 
 // BackendLimit ...
@@ -786,6 +875,18 @@ type ServerConfig struct {
 	VerifyHost    string
 }
 
+// BackendRetry configures how failed requests are retried. Redispatch is
+// empty if not configured, "true", "false", or the redispatch interval.
+// TimeoutConnect overrides the connect timeout of the backend, and is also
+// the delay before a retry to the same server, since HAProxy waits
+// min(timeout connect, 1s) before such a retry.
+type BackendRetry struct {
+	Redispatch     string
+	Retries        string
+	RetryOn        []string
+	TimeoutConnect string
+}
+
 // BackendTimeoutConfig ...
 type BackendTimeoutConfig struct {
 	Connect     string
@@ -843,6 +944,53 @@ type AuthHTTP struct {
 	Realm        string
 }
 
+// AuthAPIKey ...
+type AuthAPIKey struct {
+	Header      string
+	LabelHeader string
+	MapName     string
+	Query       string
+}
+
+// AuthJWT ...
+type AuthJWT struct {
+	AlwaysDeny   bool
+	Audience     string
+	ClaimHeaders []JWTClaimHeader
+	Issuer       string
+	Keys         []JWTKey
+}
+
+// JWTClaimHeader ...
+type JWTClaimHeader struct {
+	Claim  string
+	Header string
+}
+
+// JWTKey is a PEM encoded public key used to verify the signature of a JWT.
+// Key is written to Filename when the backend maps are written.
+type JWTKey struct {
+	Algorithm string
+	ID        string
+	Key       string
+	Filename  string
+}
+
+// Cache configures the response cache of a path. Bypass has HAProxy sample
+// expressions, a request that has any of them found is not served from, and
+// doesn't update the cache. Vary has the request headers added to the Vary
+// header of the response, so the cache stores one object per header value.
+type Cache struct {
+	Enabled bool
+	Bypass  []string
+	Vary    []string
+}
+
+// Compression ...
+type Compression struct {
+	Enabled bool
+}
+
 // Cors ...
 type Cors struct {
 	Enabled bool
@@ -864,6 +1012,18 @@ type HSTS struct {
 	Preload    bool
 }
 
+// RateLimit limits the request rate of the clients of a path. Key is the
+// HAProxy sample expression that identifies a client, and KeyType the type
+// of the stick table that counts its requests.
+type RateLimit struct {
+	Requests   int
+	Window     string
+	Key        string
+	KeyType    string
+	Exempt     []string
+	RetryAfter int
+}
+
 // WAF Defines the WAF Config structure for the Backend
 type WAF struct {
 	// Mode defines On or DetectionOnly
@@ -29,10 +29,13 @@ import (
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/config"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/launch"
 	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/legacy"
+	"github.com/jcmoraisjr/haproxy-ingress/pkg/controller/services"
 )
 
 func main() {
-	if strings.ToUpper(os.Getenv("HAPROXY_INGRESS_RUNTIME")) != "LEGACY" {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		runRender()
+	} else if strings.ToUpper(os.Getenv("HAPROXY_INGRESS_RUNTIME")) != "LEGACY" {
 		run()
 	} else {
 		runLegacy()
@@ -56,6 +59,21 @@ func run() {
 	}
 }
 
+func runRender() {
+	fs := flag.NewFlagSet("HAProxy Ingress render", flag.ExitOnError)
+	opt := config.NewOptions()
+	opt.AddFlags(fs)
+	renderOpt := config.NewRenderOptions()
+	renderOpt.AddFlags(fs)
+	err := fs.Parse(os.Args[2:])
+	if err != nil {
+		log.Fatalf("unable to parse command-line arguments: %s\n", err)
+	}
+	if err := services.Render(opt, renderOpt); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
 func runLegacy() {
 	hc := legacy.NewHAProxyController()
 	errCh := make(chan error)
@@ -44,6 +44,10 @@ func (m *MetricsMock) HAProxySetServerResponseTime(duration time.Duration) {
 func (m *MetricsMock) HAProxySetSSLCertResponseTime(duration time.Duration) {
 }
 
+// HAProxySetMapResponseTime ...
+func (m *MetricsMock) HAProxySetMapResponseTime(duration time.Duration) {
+}
+
 // ControllerProcTime ...
 func (m *MetricsMock) ControllerProcTime(task string, duration time.Duration) {
 
@@ -65,10 +69,18 @@ func (m *MetricsMock) IncUpdateDynamic() {
 func (m *MetricsMock) IncUpdateFull() {
 }
 
+// IncReloadReason ...
+func (m *MetricsMock) IncReloadReason(reason, namespace string) {
+}
+
 // UpdateSuccessful ...
 func (m *MetricsMock) UpdateSuccessful(success bool) {
 }
 
+// SetFallbackConfig ...
+func (m *MetricsMock) SetFallbackConfig(fallback bool) {
+}
+
 // SetCertExpireDate ...
 func (m *MetricsMock) SetCertExpireDate(domain, cn string, notAfter *time.Time) {
 }
@@ -88,3 +100,11 @@ func (m *MetricsMock) IncCertSigningExpiring(domains string, success bool) {
 // IncCertSigningOutdated ...
 func (m *MetricsMock) IncCertSigningOutdated(domains string, success bool) {
 }
+
+// IncServerDown ...
+func (m *MetricsMock) IncServerDown(backend string) {
+}
+
+// SetServersDown ...
+func (m *MetricsMock) SetServersDown(backend string, count int) {
+}
@@ -25,15 +25,20 @@ type Metrics interface {
 	HAProxyShowInfoResponseTime(duration time.Duration)
 	HAProxySetServerResponseTime(duration time.Duration)
 	HAProxySetSSLCertResponseTime(duration time.Duration)
+	HAProxySetMapResponseTime(duration time.Duration)
 	ControllerProcTime(task string, duration time.Duration)
 	AddIdleFactor(idle int)
 	IncUpdateNoop()
 	IncUpdateDynamic()
 	IncUpdateFull()
+	IncReloadReason(reason, namespace string)
 	UpdateSuccessful(success bool)
+	SetFallbackConfig(fallback bool)
 	SetCertExpireDate(domain, cn string, notAfter *time.Time)
 	ClearCertExpire()
 	IncCertSigningMissing(domains string, success bool)
 	IncCertSigningExpiring(domains string, success bool)
 	IncCertSigningOutdated(domains string, success bool)
+	IncServerDown(backend string)
+	SetServersDown(backend string, count int)
 }
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// HAProxyVersion has the major and minor version of the HAProxy binary.
+// The zero value means an unknown version, which should be handled as an
+// old one: version dependent keywords are not used.
+type HAProxyVersion struct {
+	Major int
+	Minor int
+}
+
+// HA-Proxy version 2.2.32-4081d5a 2023/12/19 - https://haproxy.org/
+// HAProxy version 3.0-dev4-dec0175 2024/02/23 - https://haproxy.org/
+var haproxyVersionRegex = regexp.MustCompile(`HA-?Proxy version ([0-9]+)\.([0-9]+)`)
+
+// ParseHAProxyVersion reads the version from the output of `haproxy -v`.
+func ParseHAProxyVersion(out string) (HAProxyVersion, error) {
+	digits := haproxyVersionRegex.FindStringSubmatch(out)
+	if digits == nil {
+		return HAProxyVersion{}, fmt.Errorf("version not found in '%s'", out)
+	}
+	major, _ := strconv.Atoi(digits[1])
+	minor, _ := strconv.Atoi(digits[2])
+	return HAProxyVersion{Major: major, Minor: minor}, nil
+}
+
+// LocalHAProxyVersion runs the haproxy binary and reads its version.
+func LocalHAProxyVersion() (HAProxyVersion, error) {
+	out, err := exec.Command("haproxy", "-v").CombinedOutput()
+	if err != nil {
+		return HAProxyVersion{}, err
+	}
+	return ParseHAProxyVersion(string(out))
+}
+
+// AtLeast returns true if the version is the same or newer than major.minor.
+func (v HAProxyVersion) AtLeast(major, minor int) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+// String ...
+func (v HAProxyVersion) String() string {
+	if v.Major == 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The HAProxy Ingress Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseHAProxyVersion(t *testing.T) {
+	testCases := []struct {
+		out      string
+		expected HAProxyVersion
+		atLeast  bool
+		err      bool
+	}{
+		// 0
+		{
+			out:      "HA-Proxy version 2.2.32-4081d5a 2023/12/19 - https://haproxy.org/",
+			expected: HAProxyVersion{Major: 2, Minor: 2},
+		},
+		// 1
+		{
+			out:      "HAProxy version 3.0-dev4-dec0175 2024/02/23 - https://haproxy.org/",
+			expected: HAProxyVersion{Major: 3, Minor: 0},
+		},
+		// 2
+		{
+			out:      "HAProxy version 3.2.1-a2e4b6b 2025/06/10 - https://haproxy.org/",
+			expected: HAProxyVersion{Major: 3, Minor: 2},
+			atLeast:  true,
+		},
+		// 3
+		{
+			out:      "HAProxy version 4.0.0 2027/01/01 - https://haproxy.org/",
+			expected: HAProxyVersion{Major: 4, Minor: 0},
+			atLeast:  true,
+		},
+		// 4
+		{
+			out: "command not found",
+			err: true,
+		},
+	}
+	for i, test := range testCases {
+		version, err := ParseHAProxyVersion(test.out)
+		if (err != nil) != test.err {
+			t.Errorf("%d: expected error %v, found %v", i, test.err, err)
+		}
+		if version != test.expected {
+			t.Errorf("%d: expected version %v, found %v", i, test.expected, version)
+		}
+		if atLeast := version.AtLeast(3, 2); atLeast != test.atLeast {
+			t.Errorf("%d: expected at least 3.2 %v, found %v", i, test.atLeast, atLeast)
+		}
+	}
+}